@@ -122,6 +122,7 @@ type modelTableInfo struct {
 	dependentOnTable string              // Name of table on which it is dependent
 	dependentTables  []string            // list of dependent tables
 	has_static_key   bool                // True, if LIST has sonic-extension:tbl-key
+	systemGenerated  bool                // True, if LIST has sonic-extension:system-generated
 }
 
 // CVLErrorInfo Struct for CVL Error Info
@@ -472,6 +473,7 @@ func storeModelInfo(modelFile string, module *yparser.YParserModule) {
 		tInfo.custValidation = lInfo.CustValidation
 		tInfo.mandatoryNodes = lInfo.MandatoryNodes
 		tInfo.dependentOnTable = lInfo.DependentOnTable
+		tInfo.systemGenerated = lInfo.SystemGenerated
 
 		//store default values used in must and when exp
 		tInfo.dfltLeafVal = make(map[string]string, len(lInfo.DfltLeafVal))
@@ -25,6 +25,7 @@ import (
 	"reflect"
 
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -1254,6 +1255,39 @@ func (c *CVL) GetAllReferringTables(tableName string) map[string][]string {
 	return refTbls
 }
 
+// IsSystemGeneratedTable returns true if the redis table is marked with
+// sonic-extension:system-generated in the yang, i.e. its entries are
+// created by the system and not by user configuration.
+func IsSystemGeneratedTable(tableName string) bool {
+	for _, tblInfo := range modelInfo.tableInfo {
+		if tblInfo.systemGenerated && tblInfo.redisTableName == tableName {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSystemGeneratedTables returns names of all redis tables marked with
+// sonic-extension:system-generated in the yang.
+func GetSystemGeneratedTables() []string {
+	var tables []string
+	seen := make(map[string]bool)
+	for _, tblInfo := range modelInfo.tableInfo {
+		if tblInfo.systemGenerated && !seen[tblInfo.redisTableName] {
+			seen[tblInfo.redisTableName] = true
+			tables = append(tables, tblInfo.redisTableName)
+		}
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+// RedisOptions returns the redis options of the CVL DB connections, as set
+// by the last ReconfigureRedisOptions.
+func RedisOptions() redis.Options {
+	return GetRedisOptions()
+}
+
 func ReconfigureRedisOptions(opt redis.Options) {
 	UpdateRedisOptions(&opt)
 
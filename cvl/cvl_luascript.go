@@ -29,11 +29,26 @@ import (
 // can be one of "count_entries" or "filter_entries".
 // TODO move the script definitions to DBAL and remove this API
 func RunLua(name string, args ...interface{}) (interface{}, error) {
+	return RunLuaOn(redisClient, name, args...)
+}
+
+// RunLuaOn runs a named lua script, like RunLua, in the ConfigDb of the
+// given client. Eg: the ConfigDb of a multi-ASIC namespace.
+func RunLuaOn(client *redis.Client, name string, args ...interface{}) (interface{}, error) {
 	script, ok := luaScripts[name]
 	if !ok || script == nil {
 		return nil, errors.New("unknown script: " + name)
 	}
-	return script.Run(redisClient, []string{}, args...).Result()
+	return script.Run(client, []string{}, args...).Result()
+}
+
+// LuaScriptHash returns the SHA1 digest (as used by EVALSHA) of a named lua
+// script. Returns empty string for an unknown script name.
+func LuaScriptHash(name string) string {
+	if script, ok := luaScripts[name]; ok && script != nil {
+		return script.Hash()
+	}
+	return ""
 }
 
 // Redis server side script
@@ -54,6 +54,7 @@ import (
 	"sync"
 	"syscall"
 
+	"github.com/Azure/sonic-mgmt-common/internal/dbauth"
 	set "github.com/Workiva/go-datastructures/set"
 	"github.com/go-redis/redis/v7"
 	log "github.com/golang/glog"
@@ -594,6 +595,13 @@ func GetDbPassword(dbName string) string {
 	return password
 }
 
+// GetDbUsername Get DB redis ACL user; empty for the "default" user
+func GetDbUsername(dbName string) string {
+	inst := getDbInst(dbName)
+	username, _ := inst["username"].(string)
+	return username
+}
+
 // GetDbTcpAddr Get DB TCP endpoint
 func GetDbTcpAddr(dbName string) string {
 	inst := getDbInst(dbName)
@@ -701,6 +709,12 @@ func UpdateRedisOptions(opts *redis.Options) {
 	redisOptions = opts
 }
 
+// GetRedisOptions returns a copy of the redis options set by the
+// UpdateRedisOptions.
+func GetRedisOptions() redis.Options {
+	return *redisOptions
+}
+
 func getRedisOptions(dbName string) *redis.Options {
 	var dbNetwork, dbAddr string
 
@@ -722,5 +736,10 @@ func getRedisOptions(dbName string) *redis.Options {
 	opt.Password = GetDbPassword(dbName)
 	opt.DB = GetDbId(dbName)
 
+	// TLS config key is shared with translib db, of the default namespace
+	if err := dbauth.SetOptions(&opt, "/"+dbName, getDbInst(dbName)); err != nil {
+		CVL_LEVEL_LOG(ERROR, "%s TLS config: %v", dbName, err)
+	}
+
 	return &opt
 }
@@ -270,6 +270,7 @@ type YParserListInfo struct {
 	MandatoryNodes   map[string]bool
 	DependentOnTable string //for table on which it is dependent
 	Key              string //Static key, value comes from sonic-extension:tbl-key
+	SystemGenerated  bool   //Entries are generated by the system, from sonic-extension:system-generated
 }
 
 type YParserLeafValue struct {
@@ -975,6 +976,8 @@ func GetModelListInfo(module *YParserModule) []*YParserListInfo {
 						l.DependentOnTable = argVal
 					case "tbl-key":
 						l.Key = argVal
+					case "system-generated":
+						l.SystemGenerated = true
 					}
 				}
 
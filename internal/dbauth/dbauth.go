@@ -0,0 +1,175 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+// Package dbauth has the authentication (TLS, and redis ACL user) of the
+// redis DB instances of the database_config.json. It is shared by the
+// translib DB layer, and CVL.
+package dbauth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/go-redis/redis/v7"
+)
+
+// tlsConfigs caches the TLS configs (*tlsConfigEntry), so that the PEM
+// files are not parsed for every connection.
+var tlsConfigs sync.Map
+
+type tlsConfigEntry struct {
+	config     *tls.Config
+	serverName string
+	files      [3]fileStamp // CA, certificate, and key
+}
+
+// fileStamp identifies a version of a file
+type fileStamp struct {
+	path    string
+	modTime int64
+	size    int64
+}
+
+func stampFile(path string) fileStamp {
+	stamp := fileStamp{path: path}
+	if fi, err := os.Stat(path); err == nil {
+		stamp.modTime, stamp.size = fi.ModTime().UnixNano(), fi.Size()
+	}
+	return stamp
+}
+
+// TLSConfig returns the TLS config of the DB instance, whose fields (of the
+// database_config.json) are inst; nil if the instance is not configured for
+// TLS. The instance TLS fields are:
+//
+//	"tls_ca_path"     : CA certificates PEM file to verify the redis-server.
+//	                    The system roots are used if absent.
+//	"tls_cert_path"   : Client certificate PEM file
+//	"tls_key_path"    : Client private key PEM file
+//	"tls_server_name" : Name to verify the redis-server certificate against.
+//	                    The hostname is used if absent.
+//
+// The config is cached by the key (Eg: "<namespace>/<DB name>"), and loaded
+// again when a PEM file is changed (its modification time, or size), so that
+// the new connections use the renewed certificates. The open connections
+// are not affected.
+func TLSConfig(key string, inst map[string]interface{}) (*tls.Config, error) {
+	caPath, _ := inst["tls_ca_path"].(string)
+	certPath, _ := inst["tls_cert_path"].(string)
+	keyPath, _ := inst["tls_key_path"].(string)
+	serverName, _ := inst["tls_server_name"].(string)
+	if caPath == "" && certPath == "" && keyPath == "" && serverName == "" {
+		return nil, nil
+	}
+	if serverName == "" {
+		serverName, _ = inst["hostname"].(string)
+	}
+
+	files := [3]fileStamp{stampFile(caPath), stampFile(certPath), stampFile(keyPath)}
+	if c, ok := tlsConfigs.Load(key); ok {
+		if entry := c.(*tlsConfigEntry); entry.serverName == serverName &&
+			entry.files == files {
+			return entry.config, nil
+		}
+	}
+
+	config := &tls.Config{ServerName: serverName}
+
+	if caPath != "" {
+		data, err := os.ReadFile(caPath)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("%s: no CA certificates", caPath)
+		}
+	}
+
+	if certPath != "" || keyPath != "" {
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	tlsConfigs.Store(key, &tlsConfigEntry{config: config,
+		serverName: serverName, files: files})
+	return config, nil
+}
+
+// SetOptions sets the TLS config (for the "tcp" network only), and the
+// redis ACL user of the DB instance, whose fields are inst, in the redis
+// options. The Network, Addr, Password, DB and Dialer should already be set.
+// The key is that of TLSConfig. If the TLS config cannot be loaded, the
+// connections are failed, rather than falling back to plain text, and the
+// error is returned.
+func SetOptions(opts *redis.Options, key string, inst map[string]interface{}) error {
+	var tlsErr error
+	if opts.Network == "tcp" {
+		opts.TLSConfig, tlsErr = TLSConfig(key, inst)
+		if tlsErr != nil {
+			opts.Dialer = func(context.Context, string, string) (net.Conn, error) {
+				return nil, tlsErr
+			}
+		}
+	}
+
+	// go-redis AUTHs with the password only; AUTH as the ACL user, and
+	// SELECT the DB after that, in the OnConnect hook.
+	if username, _ := inst["username"].(string); username != "" {
+		opts.OnConnect = ACLAuth(username, opts.Password, opts.DB, opts.OnConnect)
+		opts.Password = ""
+		opts.DB = 0
+	}
+	return tlsErr
+}
+
+// ForgetTLSConfig drops the cached TLS config of the key.
+func ForgetTLSConfig(key string) {
+	tlsConfigs.Delete(key)
+}
+
+// ACLAuth returns the OnConnect hook to AUTH as the redis ACL user, since
+// go-redis AUTHs with the password only. The DB is SELECTed in the hook,
+// since go-redis would otherwise SELECT it before the AUTH. The onConnect
+// hook, if any, is called after that.
+func ACLAuth(username, password string, dbId int,
+	onConnect func(*redis.Conn) error) func(*redis.Conn) error {
+
+	return func(cn *redis.Conn) error {
+		_, err := cn.Pipelined(func(pipe redis.Pipeliner) error {
+			pipe.Process(redis.NewStatusCmd("auth", username, password))
+			if dbId > 0 {
+				pipe.Select(dbId)
+			}
+			return nil
+		})
+		if err == nil && onConnect != nil {
+			err = onConnect(cn)
+		}
+		return err
+	}
+}
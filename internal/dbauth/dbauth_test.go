@@ -0,0 +1,137 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package dbauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+)
+
+// writeTestCert writes a self-signed certificate of the name, and its key
+// PEM files.
+func writeTestCert(t *testing.T, dir, name string) (certPath, keyPath string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() fails e = %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() fails e = %v", err)
+	}
+	keyDer, _ := x509.MarshalECPrivateKey(key)
+
+	certPath = filepath.Join(dir, "cert.pem")
+	keyPath = filepath.Join(dir, "key.pem")
+	os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)
+	os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+	return
+}
+
+func certName(t *testing.T, key string, inst map[string]interface{}) string {
+	t.Helper()
+	config, err := TLSConfig(key, inst)
+	if err != nil || config == nil || len(config.Certificates) != 1 {
+		t.Fatalf("TLSConfig() = %+v, %v", config, err)
+	}
+	cert, err := x509.ParseCertificate(config.Certificates[0].Certificate[0])
+	if err != nil {
+		t.Fatalf("ParseCertificate() fails e = %v", err)
+	}
+	return cert.Subject.CommonName
+}
+
+func TestTLSConfigReload(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := writeTestCert(t, dir, "client1")
+	inst := map[string]interface{}{"hostname": "redis.example.com",
+		"tls_cert_path": certPath, "tls_key_path": keyPath}
+	t.Cleanup(func() { ForgetTLSConfig("test") })
+
+	if c, err := TLSConfig("test", map[string]interface{}{"hostname": "h"}); c != nil || err != nil {
+		t.Fatalf("TLSConfig() without TLS fields = %+v, %v", c, err)
+	}
+	c1, _ := TLSConfig("test", inst)
+	if c2, _ := TLSConfig("test", inst); c1 == nil || c2 != c1 ||
+		c1.ServerName != "redis.example.com" {
+		t.Fatalf("TLSConfig() = %+v, %+v; expected the cached one", c1, c2)
+	}
+
+	// Renewed certificate
+	time.Sleep(10 * time.Millisecond)
+	writeTestCert(t, dir, "client2")
+	if name := certName(t, "test", inst); name != "client2" {
+		t.Fatalf("TLSConfig() certificate = %s after renewal", name)
+	}
+
+	// Changed instance fields
+	inst["tls_server_name"] = "redis0"
+	if c, _ := TLSConfig("test", inst); c == nil || c.ServerName != "redis0" {
+		t.Fatalf("TLSConfig() = %+v after tls_server_name change", c)
+	}
+}
+
+func TestSetOptions(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := writeTestCert(t, dir, "client1")
+	inst := map[string]interface{}{"hostname": "redis.example.com", "username": "user1",
+		"tls_cert_path": certPath, "tls_key_path": keyPath}
+	t.Cleanup(func() { ForgetTLSConfig("test") })
+
+	opts := &redis.Options{Network: "tcp", Password: "pw", DB: 4}
+	if err := SetOptions(opts, "test", inst); err != nil || opts.TLSConfig == nil ||
+		opts.OnConnect == nil || opts.Password != "" || opts.DB != 0 {
+		t.Fatalf("SetOptions() = %+v, %v", opts, err)
+	}
+
+	// No TLS for the unix socket
+	opts = &redis.Options{Network: "unix", Password: "pw", DB: 4}
+	if err := SetOptions(opts, "test", inst); err != nil || opts.TLSConfig != nil ||
+		opts.OnConnect == nil {
+		t.Fatalf("SetOptions(unix) = %+v, %v", opts, err)
+	}
+
+	// Bad TLS config fails the connections
+	inst["tls_ca_path"] = filepath.Join(dir, "missing.pem")
+	opts = &redis.Options{Network: "tcp"}
+	if err := SetOptions(opts, "test", inst); err == nil || opts.Dialer == nil {
+		t.Fatalf("SetOptions() = %+v, %v with a missing CA file", opts, err)
+	}
+	if _, err := opts.Dialer(context.Background(), "tcp", "localhost:6379"); err == nil {
+		t.Fatalf("Dialer() did not fail with a missing CA file")
+	}
+}
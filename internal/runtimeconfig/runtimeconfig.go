@@ -0,0 +1,74 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+// Package runtimeconfig has the runtime config hooks of the components
+// that can not depend on the translib DB layer; Eg: ocbinds, which would
+// otherwise pull in CVL (cgo). The translib DB layer pushes the runtime
+// config (the TRANSLIB_DB|default fields) to the hooks, as it does to its
+// own RuntimeConfigComponents.
+package runtimeconfig
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Hook is the runtime config hook of a component. It is the same as the
+// translib DB RuntimeConfigComponent, with an untyped config. Unlike the
+// RuntimeConfigComponent, a Hook is also notified of the initial config.
+type Hook struct {
+	Name string
+	// Parse returns the config from the fields. The fields are empty if the
+	// TRANSLIB_DB|default entry does not exist.
+	Parse func(fields map[string]string) interface{}
+	// OnChange is called when the parsed config changes; and for the first
+	// config loaded after the registration (old is nil).
+	OnChange func(old, new interface{})
+	// Current returns the effective config. Optional.
+	Current func() interface{}
+}
+
+var hooks []Hook
+var mutexHooks sync.Mutex
+
+// Register adds the hook. Typically called from the init() of the
+// component's package.
+func Register(h Hook) error {
+	if len(h.Name) == 0 || h.Parse == nil || h.OnChange == nil {
+		return fmt.Errorf("Invalid runtime config hook %q", h.Name)
+	}
+
+	mutexHooks.Lock()
+	defer mutexHooks.Unlock()
+
+	for _, x := range hooks {
+		if x.Name == h.Name {
+			return fmt.Errorf("Runtime config hook %q is already registered", h.Name)
+		}
+	}
+	hooks = append(hooks, h)
+	return nil
+}
+
+// Hooks returns the registered hooks, in the order of registration.
+func Hooks() []Hook {
+	mutexHooks.Lock()
+	defer mutexHooks.Unlock()
+	return append([]Hook(nil), hooks...)
+}
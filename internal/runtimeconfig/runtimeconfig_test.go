@@ -0,0 +1,56 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package runtimeconfig
+
+import (
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	mutexHooks.Lock()
+	prev := hooks
+	hooks = nil
+	mutexHooks.Unlock()
+	t.Cleanup(func() {
+		mutexHooks.Lock()
+		hooks = prev
+		mutexHooks.Unlock()
+	})
+
+	noop := func(old, new interface{}) {}
+	parse := func(fields map[string]string) interface{} { return fields["a"] }
+
+	if err := Register(Hook{Name: "a", Parse: parse}); err == nil {
+		t.Errorf("Register() without OnChange did not fail")
+	}
+	for _, name := range []string{"a", "b"} {
+		if err := Register(Hook{Name: name, Parse: parse, OnChange: noop}); err != nil {
+			t.Fatalf("Register(%s) failed; err=%v", name, err)
+		}
+	}
+	if err := Register(Hook{Name: "a", Parse: parse, OnChange: noop}); err == nil {
+		t.Errorf("Register() with duplicate name did not fail")
+	}
+
+	hooks := Hooks()
+	if len(hooks) != 2 || hooks[0].Name != "a" || hooks[1].Name != "b" {
+		t.Errorf("Hooks() = %v; expected [a b]", hooks)
+	}
+}
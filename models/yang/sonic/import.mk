@@ -12,4 +12,5 @@ SONICYANG_IMPORTS += sonic-portchannel.yang
 SONICYANG_IMPORTS += sonic-vlan.yang
 SONICYANG_IMPORTS += sonic-mclag.yang
 SONICYANG_IMPORTS += sonic-types.yang
-SONICYANG_IMPORTS += sonic-vrf.yang
\ No newline at end of file
+SONICYANG_IMPORTS += sonic-vrf.yang
+SONICYANG_IMPORTS += sonic-versions.yang
@@ -203,6 +203,10 @@ func newCS(name string, username string, roles []string, pid int32) (*configSess
 		return nil, err
 	}
 
+	// For the journal records of the commit
+	ccDB.Opts.User = username
+	ccDB.Opts.SessionToken = token
+
 	uCS = &configSession{
 		name:     name,
 		token:    token,
@@ -369,6 +373,9 @@ func cleanCS() error {
 
 	uCS.ccDB.DeleteDB()
 	uCS.state = cs_STATE_None
+	if uCS.rollbackCfg != "" {
+		os.Remove(uCS.rollbackCfg)
+	}
 	// Db Unlock
 	var errSc error
 	if errSc = db.ConfigDBUnlock(uCS.token); errSc != nil {
@@ -23,9 +23,12 @@ package cs
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"strconv"
 	"time"
 
+	"github.com/Azure/sonic-mgmt-common/translib/db"
 	"github.com/Azure/sonic-mgmt-common/translib/tlerr"
 	"github.com/Azure/sonic-mgmt-common/translib/transformer"
 	"github.com/golang/glog"
@@ -38,14 +41,24 @@ const (
 func saveRunningConfig(username string) (string, error) {
 
 	now := time.Now()
-	dest := "temp://rollback.cfg." + strconv.FormatInt(int64(now.UnixNano()), 10) + ".json"
+	dest := filepath.Join(os.TempDir(),
+		"rollback.cfg."+strconv.FormatInt(int64(now.UnixNano()), 10)+".json")
 
-	options := [...]string{"running-configuration", dest, "", username}
-	queryResult := transformer.HostQuery("cfg_mgmt.save", options)
-	if queryResult.Err != nil {
-		glog.Infof("Error: %s", queryResult.Err.Error())
+	d, err := db.NewDB(db.Options{DBNo: db.ConfigDB, IsWriteDisabled: true})
+	if err != nil {
+		glog.Infof("Error: %s", err.Error())
+		return "", errors.New("Failed to save current config")
+	}
+	defer d.DeleteDB()
+
+	if dest, err = d.Export(dest); err != nil {
+		glog.Infof("Error: %s", err.Error())
+		if dest != "" {
+			os.Remove(dest)
+		}
 		return "", errors.New("Failed to save current config")
 	}
+	glog.Infof("Running config saved to %s by %s", dest, username)
 	return dest, nil
 }
 
@@ -124,11 +137,25 @@ func configReload(sess *Session, rollbackCfg string) error {
 		glog.Errorf("Commit:[%s] Broadcast message failure", sess.token)
 	}
 
-	options := [...]string{rollbackCfg, "running-configuration", "OVERWRITE", sess.username}
-	queryResult = transformer.HostQuery("cfg_mgmt.save", options)
-	if queryResult.Err != nil {
-		return errors.New(queryResult.Err.Error())
+	// Config DB is still locked by the session; apply the rollback config
+	// through a new session DB transaction.
+	d, err := db.NewDB(db.Options{DBNo: db.ConfigDB, IsSession: true})
+	if err != nil {
+		return err
+	}
+	defer d.DeleteDB()
+
+	if err = csStartTx(d); err != nil {
+		return err
+	}
+	if err = d.Import(rollbackCfg, db.ImportReplace); err != nil {
+		csAbortTx(d)
+		return err
+	}
+	if err = csCommitTx(d); err != nil {
+		return err
 	}
+	glog.Infof("Commit:[%s] Reloaded config from %s", sess.token, rollbackCfg)
 	return nil
 }
 
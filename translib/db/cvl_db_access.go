@@ -164,7 +164,8 @@ func (c *cvlDBAccess) Lookup(s ctypes.Search) ctypes.JsonResult {
 		return strResult{"", err}
 	}
 
-	v, err := cvl.RunLua(
+	v, err := cvl.RunLuaOn(
+		c.Db.client,
 		"filter_entries",
 		s.Pattern,
 		strings.Join(s.KeyNames, "|"),
@@ -187,7 +188,8 @@ func (c *cvlDBAccess) Count(s ctypes.Search) ctypes.IntResult {
 		return intResult{0, err}
 	}
 	// Advanced key search, with match criteria on has values
-	v, err := cvl.RunLua(
+	v, err := cvl.RunLuaOn(
+		c.Db.client,
 		"count_entries",
 		s.Pattern,
 		strings.Join(s.KeyNames, "|"),
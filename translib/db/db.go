@@ -157,12 +157,13 @@ func (dbNo DBNum) ID() int {
 	if len(name) == 0 {
 		panic("Invalid DBNum " + fmt.Sprintf("%d", dbNo))
 	}
-	return getDbId(name)
+	return getDbId("", name)
 }
 
 // Options gives parameters for opening the redis client.
 type Options struct {
 	DBNo               DBNum
+	Namespace          string // Multi-ASIC DB namespace. Eg: "asic0"; "" is default
 	InitIndicator      string
 	TableNameSeparator string //Overriden by the DB config file's separator.
 	KeySeparator       string //Overriden by the DB config file's separator.
@@ -182,6 +183,13 @@ type Options struct {
 	ConfigDBLazyLock bool // For Non-CCDB Action()/RPC (may write to ConfigDB)
 	TxCmdsLim        int  // Tx Limit for Candidate Config DB
 
+	// ConfigDBLockWait is the time to wait for the ConfigDB lock, held by
+	// others. By default (0), the lock is only tried a few times.
+	ConfigDBLockWait time.Duration
+
+	User         string // Originating user, recorded in the journal
+	SessionToken string // Config Session token, recorded in the journal
+
 	IsReplaced  bool // Is candidate Config DB updated by config-replace operation.
 	IsCommitted bool // Is candidate Config DB committed.
 
@@ -195,10 +203,11 @@ type Options struct {
 
 func (o Options) String() string {
 	return fmt.Sprintf(
-		"{ DBNo: %v, InitIndicator: %v, TableNameSeparator: %v, KeySeparator: %v, IsWriteDisabled: %v, IsCacheEnabled: %v, IsOnChangeEnabled: %v, SDB: %v, DisableCVLCheck: %v, IsSession: %v, ConfigDBLazyLock: %v, TxCmdsLim: %v }",
-		o.DBNo, o.InitIndicator, o.TableNameSeparator, o.KeySeparator,
+		"{ DBNo: %v, Namespace: %v, InitIndicator: %v, TableNameSeparator: %v, KeySeparator: %v, IsWriteDisabled: %v, IsCacheEnabled: %v, IsOnChangeEnabled: %v, SDB: %v, DisableCVLCheck: %v, IsSession: %v, ConfigDBLazyLock: %v, TxCmdsLim: %v, ConfigDBLockWait: %v, User: %v }",
+		o.DBNo, o.Namespace, o.InitIndicator, o.TableNameSeparator, o.KeySeparator,
 		o.IsWriteDisabled, o.IsCacheEnabled, o.IsOnChangeEnabled, o.SDB,
-		o.DisableCVLCheck, o.IsSession, o.ConfigDBLazyLock, o.TxCmdsLim)
+		o.DisableCVLCheck, o.IsSession, o.ConfigDBLazyLock, o.TxCmdsLim,
+		o.ConfigDBLockWait, o.User)
 }
 
 type _txState int
@@ -283,6 +292,7 @@ type DB struct {
 
 	txState      _txState
 	txCmds       []_txCmd
+	txConds      []_txCond                   // Conditions of the conditional writes
 	txTsEntryMap map[string]map[string]Value //map[TableSpec.Name]map[Entry]Value
 
 	// For Config Session only, cache the HGetAll for restoring the
@@ -291,6 +301,9 @@ type DB struct {
 	// it need not be read again.
 	txTsEntryHGetAll map[string]map[string]Value //map[TableSpec.Name]map[Entry]Value
 
+	// Savepoints, innermost last
+	savePoints []*_savePoint
+
 	cv                *cvl.CVL
 	cvlHintsB4Open    map[string]interface{} // Hints set before CVLSess Opened
 	cvlEditConfigData []cmn.CVLEditConfigData
@@ -303,8 +316,9 @@ type DB struct {
 	sCIP        bool          // Close in Progress
 	sOnCCacheDB *DB           // Update this DB for PubSub notifications
 
-	dbStatsConfig DBStatsConfig
-	dbCacheConfig DBCacheConfig
+	dbStatsConfig   DBStatsConfig
+	dbCacheConfig   DBCacheConfig
+	dbJournalConfig DBJournalConfig
 
 	// DBStats is used by both PerConnection cache, and OnChange cache
 	// On a DB handle, the two are mutually exclusive.
@@ -318,6 +332,10 @@ type DB struct {
 
 	// Non-Session Config DB Lock acquired
 	configDBLocked bool
+	configDBLock   *LockStruct // The ConfigDB lock placed by this DB
+
+	// Checkpoint data, when opened with a CommitIdDbDs Datastore
+	cpDs *cpDatastore
 }
 
 func (d DB) String() string {
@@ -410,6 +428,7 @@ func NewDB(opt Options) (*DB, error) {
 		dbStatsConfig:     getDBStatsConfig(),
 		stats:             DBStats{Tables: make(map[string]Stats, InitialTablesCount), Maps: make(map[string]Stats, InitialMapsCount)},
 		dbCacheConfig:     getDBCacheConfig(),
+		dbJournalConfig:   getDBJournalConfig(),
 		cache:             dbCache{Tables: make(map[string]Table, InitialTablesCount), Maps: make(map[string]MAP, InitialMapsCount)},
 	}
 
@@ -419,6 +438,13 @@ func NewDB(opt Options) (*DB, error) {
 		goto NewDBExit
 	}
 
+	if !IsNamespacePresent(opt.Namespace) {
+		glog.Error("NewDB: Unknown namespace: ", opt.Namespace)
+		d.client.Close()
+		e = tlerr.TranslibDBCannotOpen{}
+		goto NewDBExit
+	}
+
 	if opt.IsCacheEnabled && opt.IsOnChangeEnabled {
 		glog.Error("Per Connection cache cannot be enabled with OnChange cache")
 		glog.Error("Disabling Per Connection caching")
@@ -452,6 +478,14 @@ func NewDB(opt Options) (*DB, error) {
 		d.dbCacheConfig.PerConnection = false
 	}
 
+	// Global cache is for the CONFIG_DB readers only (of any namespace).
+	// Writers, and their Transactions, must see the DB itself.
+	if d.dbCacheConfig.Global && (!opt.IsWriteDisabled || opt.IsSession ||
+		opt.IsSubscribeDB || opt.IsOnChangeEnabled || opt.DBNo != ConfigDB ||
+		isCommitIdDs(opt.Datastore)) {
+		d.dbCacheConfig.Global = false
+	}
+
 	if opt.IsSession && opt.IsOnChangeEnabled {
 		glog.Error("NewDB: Subscription on Config Session not supported : ",
 			d.Name())
@@ -474,6 +508,15 @@ func NewDB(opt Options) (*DB, error) {
 		d.onCReg = dbOnChangeReg{CacheTables: make(map[string]bool, InitialTablesCount)}
 	}
 
+	// Alternate Datastore: serve the reads from the checkpoint file.
+	if isCommitIdDs(opt.Datastore) {
+		if e = d.openCommitIdDs(); e != nil {
+			d.client.Close()
+			goto NewDBExit
+		}
+		goto NewDBSkipInitIndicatorCheck
+	}
+
 	if opt.DBNo != ConfigDB {
 		if glog.V(3) {
 			glog.Info("NewDB: ! ConfigDB. Skip init. check.")
@@ -510,12 +553,18 @@ func NewDB(opt Options) (*DB, error) {
 	if opt.DBNo == ConfigDB && !opt.IsSession &&
 		!opt.IsWriteDisabled && !opt.ConfigDBLazyLock {
 
-		if e = ConfigDBTryLock(noSessionToken); e != nil {
+		if opt.ConfigDBLockWait > 0 {
+			e = ConfigDBLock(noSessionToken, opt.ConfigDBLockWait)
+		} else {
+			e = ConfigDBTryLock(noSessionToken)
+		}
+		if e != nil {
 			glog.Errorf("NewDB: ConfigDB possibly locked: %s", e)
 			d.client.Close()
 			goto NewDBExit
 		}
 		d.configDBLocked = true
+		d.configDBLock = heldConfigDBLock()
 	}
 
 	// Register Candidate Config (Session) DBs
@@ -531,7 +580,7 @@ NewDBExit:
 		dur = time.Since(now)
 	}
 
-	dbGlobalStats.updateStats(d.Opts.DBNo, true, dur, &(d.stats))
+	dbGlobalStats.updateStats(d.Opts.Namespace, d.Opts.DBNo, true, dur, &(d.stats))
 
 	if glog.V(3) {
 		glog.Info("NewDB: End: d: ", d, " e: ", e)
@@ -551,15 +600,16 @@ func (d *DB) DeleteDB() error {
 
 	// Release the ConfigDB Lock if we placed on in NewDB()
 	if d.configDBLocked {
-		ConfigDBUnlock(noSessionToken)
+		configDBUnlock(d.configDBLock)
 		d.configDBLocked = false
+		d.configDBLock = nil
 	}
 
 	if !d.IsOpen() {
 		return ConnectionClosed
 	}
 
-	dbGlobalStats.updateStats(d.Opts.DBNo, false, 0, &(d.stats))
+	dbGlobalStats.updateStats(d.Opts.Namespace, d.Opts.DBNo, false, 0, &(d.stats))
 
 	if d.txState != txStateNone {
 		glog.Warning("DeleteDB: not txStateNone, txState: ", d.txState)
@@ -661,12 +711,14 @@ func (d *DB) getEntry(ts *TableSpec, key Key, forceReadDB bool) (Value, error) {
 	var value Value
 	var e error
 	var v map[string]string
+	var gen uint64
 
 	var ok bool
 	entry := d.key2redis(ts, key)
 	useCache := ((d.Opts.IsOnChangeEnabled && d.onCReg.isCacheTable(ts.Name)) ||
 		(d.dbCacheConfig.PerConnection &&
 			d.dbCacheConfig.isCacheTable(ts.Name)))
+	useGCache := d.dbCacheConfig.Global && d.dbCacheConfig.isCacheTable(ts.Name)
 
 	// check in Tx cache first
 	if value, ok = d.txTsEntryMap[ts.Name][entry]; !ok {
@@ -679,6 +731,9 @@ func (d *DB) getEntry(ts *TableSpec, key Key, forceReadDB bool) (Value, error) {
 				}
 			}
 		}
+		if !cacheHit && useGCache && !forceReadDB {
+			value, gen, cacheHit = dbGlobalCache.getEntry(d, ts, entry)
+		}
 	} else {
 		value = value.Copy()
 		txCacheHit = true
@@ -689,8 +744,12 @@ func (d *DB) getEntry(ts *TableSpec, key Key, forceReadDB bool) (Value, error) {
 		if glog.V(3) {
 			glog.Info("getEntry: RedisCmd: ", d.Name(), ": ", "HGETALL ", entry)
 		}
-		v, e = d.client.HGetAll(entry).Result()
-		value = Value{Field: v}
+		if d.cpDs != nil {
+			value = d.cpDs.getEntry(entry)
+		} else {
+			v, e = d.client.HGetAll(entry).Result()
+			value = Value{Field: v}
+		}
 	}
 
 	if e != nil {
@@ -722,6 +781,11 @@ func (d *DB) getEntry(ts *TableSpec, key Key, forceReadDB bool) (Value, error) {
 		d.cache.Tables[ts.Name].entry[entry] = value.Copy()
 	}
 
+	if e == nil && !cacheHit && !txCacheHit && useGCache && !forceReadDB &&
+		value.IsPopulated() {
+		dbGlobalCache.putEntry(d, ts, entry, value, gen)
+	}
+
 	// Time End, Time, Peak
 	if d.dbStatsConfig.TableStats {
 		stats = d.stats.Tables[ts.Name]
@@ -747,6 +811,8 @@ func (d *DB) getEntry(ts *TableSpec, key Key, forceReadDB bool) (Value, error) {
 			stats.GetEntryPeak = dur
 		}
 		stats.GetEntryTime += dur
+
+		d.observeOp(&stats, OpGetEntry, ts, entry, dur)
 	}
 
 	if d.dbStatsConfig.TableStats {
@@ -825,13 +891,23 @@ func (d *DB) GetKeysPattern(ts *TableSpec, pat Key) ([]Key, error) {
 		}
 	}
 
+	var gen uint64
+	useGCache := d.dbCacheConfig.Global && d.dbCacheConfig.isCacheTable(ts.Name)
+	if !cacheHit && useGCache {
+		keys, gen, cacheHit = dbGlobalCache.getKeys(d, ts, d.key2redis(ts, pat))
+	}
+
 	if !cacheHit {
 		// Increase (i.e. more verbose) V() level if it gets too noisy.
 		if glog.V(3) {
 			glog.Info("GetKeysPattern: RedisCmd: ", d.Name(), ": ", "KEYS ", d.key2redis(ts, pat))
 		}
 		var redisKeys []string
-		redisKeys, e = d.client.Keys(d.key2redis(ts, pat)).Result()
+		if d.cpDs != nil {
+			redisKeys = d.cpDs.keys(d.key2redis(ts, pat))
+		} else {
+			redisKeys, e = d.client.Keys(d.key2redis(ts, pat)).Result()
+		}
 
 		keys = make([]Key, 0, len(redisKeys))
 		// On error, return promptly
@@ -862,6 +938,10 @@ func (d *DB) GetKeysPattern(ts *TableSpec, pat Key) ([]Key, error) {
 			}
 			d.cache.Tables[ts.Name].patterns[d.key2redis(ts, pat)] = keysCopy
 		}
+
+		if useGCache {
+			dbGlobalCache.putKeys(d, ts, d.key2redis(ts, pat), keys, gen)
+		}
 	}
 
 	for k := range d.txTsEntryMap[ts.Name] {
@@ -920,6 +1000,8 @@ func (d *DB) GetKeysPattern(ts *TableSpec, pat Key) ([]Key, error) {
 		}
 		stats.GetKeysPatternTime += dur
 
+		d.observeOp(&stats, OpGetKeysPattern, ts, d.key2redis(ts, pat), dur)
+
 		if (len(pat.Comp) == 1) && (pat.Comp[0] == "*") {
 
 			if dur > stats.GetKeysPeak {
@@ -1079,6 +1161,7 @@ func (d *DB) doWrite(ts *TableSpec, op _txOp, k Key, val interface{}) error {
 		goto doWriteExit
 	}
 
+	d.checkConfigDBLock()
 	if d.err != nil {
 		e = d.err
 		glog.Error("doWrite: DB in error: ", e)
@@ -1086,11 +1169,17 @@ func (d *DB) doWrite(ts *TableSpec, op _txOp, k Key, val interface{}) error {
 	}
 
 	if d.Opts.DBNo == ConfigDB && !d.Opts.IsSession && !d.configDBLocked {
-		if e = ConfigDBTryLock(noSessionToken); e != nil {
+		if d.Opts.ConfigDBLockWait > 0 {
+			e = ConfigDBLock(noSessionToken, d.Opts.ConfigDBLockWait)
+		} else {
+			e = ConfigDBTryLock(noSessionToken)
+		}
+		if e != nil {
 			glog.Errorf("doWrite: ConfigDB possibly locked: %s", e)
 			goto doWriteExit
 		}
 		d.configDBLocked = true
+		d.configDBLock = heldConfigDBLock()
 	}
 
 	if d.Opts.IsSession && (d.Opts.TxCmdsLim != 0) &&
@@ -1172,10 +1261,17 @@ func (d *DB) doWrite(ts *TableSpec, op _txOp, k Key, val interface{}) error {
 			e = errors.New("Unknown Op: " + string(rune(op)))
 		}
 
-		// No Transaction. Only update the config-timestamp, and
-		// ignore the error, if any, since the actual operation succeeded.
+		// No Transaction. Only update the config-timestamp, and the
+		// journal sequence (the write is not journaled), and ignore the
+		// error, if any, since the actual operation succeeded.
 		if d.Opts.DBNo == ConfigDB && e == nil {
 			d.markConfigDBUpdated()
+			d.markJournalGap()
+		}
+
+		if e == nil {
+			dbGlobalCache.invalidate(d.Opts.Namespace, d.Opts.DBNo,
+				d.key2redis(ts, key), d.Opts.TableNameSeparator)
 		}
 
 		goto doWriteExit
@@ -1374,6 +1470,10 @@ func (d *DB) RunScript(script *redis.Script, keys []string, args ...interface{})
 		return nil
 	}
 
+	return d.runScript(script, keys, args...)
+}
+
+func (d *DB) runScript(script *redis.Script, keys []string, args ...interface{}) *redis.Cmd {
 	return script.Run(d.client, keys, args...)
 }
 
@@ -1561,7 +1661,7 @@ func (d *DB) AbortSessTx() error {
 
 func (d *DB) StartTx(w []WatchKeys, tss []*TableSpec) error {
 	if d.Opts.IsSession {
-		return d.DeclareSP()
+		return d.DeclareNamedSP(txSavePoint)
 	}
 	return d.startTx(w, tss)
 }
@@ -1569,7 +1669,7 @@ func (d *DB) StartTx(w []WatchKeys, tss []*TableSpec) error {
 func (d *DB) CommitTx() error {
 	defer d.clearCVLHint("")
 	if d.Opts.IsSession {
-		return d.ReleaseSP()
+		return d.ReleaseNamedSP(txSavePoint)
 	}
 	return d.commitTx()
 }
@@ -1578,7 +1678,7 @@ func (d *DB) AbortTx() error {
 	if d.Opts.IsSession {
 		// Rollback creates the CVL Session again -- with only the
 		// pre-DeclareSP() CVL Hints.
-		return d.Rollback2SP()
+		return d.Rollback2NamedSP(txSavePoint)
 	}
 	d.clearCVLHint("")
 	return d.abortTx()
@@ -1731,6 +1831,9 @@ func (d *DB) commitTx() error {
 
 	var e error = nil
 	var tsmap map[TableSpec]bool = make(map[TableSpec]bool, len(d.txCmds)) // UpperBound
+	var jRec *JournalRecord
+	var jOps []JournalOp
+	var reply interface{}
 
 	// Validate State
 	switch d.txState {
@@ -1751,6 +1854,7 @@ func (d *DB) commitTx() error {
 		e = errors.New("Unknown State: " + string(rune(d.txState)))
 	}
 
+	d.checkConfigDBLock()
 	if d.err != nil {
 		e = d.err
 		glog.Error("CommitTx: DB in error: ", e)
@@ -1760,6 +1864,16 @@ func (d *DB) commitTx() error {
 		goto CommitTxExit
 	}
 
+	// Evaluate the conditions of the conditional writes, if any
+	if e = d.checkTxConds(); e != nil {
+		goto CommitTxExit
+	}
+
+	// Read the before images for the journal, if enabled
+	if jOps, e = d.journalOps(); e != nil {
+		goto CommitTxExit
+	}
+
 	// Issue MULTI
 	glog.Info("CommitTx: Do: MULTI")
 	_, e = d.client.Do("MULTI").Result()
@@ -1850,19 +1964,40 @@ func (d *DB) commitTx() error {
 		goto CommitTxExit
 	}
 
+	// Journal record. Must be the last command of the MULTI.
+	if jOps == nil && len(d.txCmds) != 0 {
+		if e = d.markJournalGap(); e != nil {
+			goto CommitTxExit
+		}
+	} else if jOps != nil {
+		jRec = &JournalRecord{Time: time.Now(), User: d.Opts.User,
+			Session: d.Opts.SessionToken, Ops: jOps}
+		if e = d.queueJournal(jRec); e != nil {
+			glog.Warning("CommitTx: Do: EVAL luaScriptJournal e: ", e.Error())
+			goto CommitTxExit
+		}
+	}
+
 	// Issue EXEC
 	glog.Info("CommitTx: Do: EXEC")
-	_, e = d.client.Do("EXEC").Result()
+	reply, e = d.client.Do("EXEC").Result()
 
 	if e != nil {
 		glog.Warning("CommitTx: Do: EXEC e: ", e.Error())
 		e = tlerr.TranslibTransactionFail{}
+	} else {
+		dbGlobalCache.invalidateTx(d)
+		if jRec != nil {
+			d.recordJournal(jRec, reply)
+		}
 	}
 
 CommitTxExit:
 	// Switch State, Clear Command list
 	d.txState = txStateNone
 	d.txCmds = d.txCmds[:0]
+	d.txConds = d.txConds[:0]
+	d.savePoints = nil
 	d.cvlEditConfigData = d.cvlEditConfigData[:0]
 	d.txTsEntryMap = make(map[string]map[string]Value)
 	d.txTsEntryHGetAll = make(map[string]map[string]Value)
@@ -1925,6 +2060,8 @@ AbortTxExit:
 	// Switch State, Clear Command list
 	d.txState = txStateNone
 	d.txCmds = d.txCmds[:0]
+	d.txConds = d.txConds[:0]
+	d.savePoints = nil
 	d.cvlEditConfigData = d.cvlEditConfigData[:0]
 	d.txTsEntryMap = make(map[string]map[string]Value)
 	d.txTsEntryHGetAll = make(map[string]map[string]Value)
@@ -2012,10 +2149,32 @@ func (d *DB) GetEntries(ts *TableSpec, keys []Key) ([]Value, []error) {
 	return d.getEntries(ts, keys, false)
 }
 
-func (d *DB) getEntries(ts *TableSpec, keys []Key, forceReadDB bool) ([]Value, []error) {
+// TableKeys is a table, and the keys of its entries to be retrieved
+type TableKeys struct {
+	Ts   *TableSpec
+	Keys []Key
+}
 
-	if glog.V(3) {
-		glog.Info("GetEntries: Begin: ", d.Name(), ": ts: ", ts, " keys: ", keys)
+// GetMultiTableEntries retrieves the entries of multiple tables, as
+// GetEntries, using a single redis pipeline for all the keys that are
+// not present in the cache. Returns the values and errors of each of the
+// TableKeys, in order. Note: A pipeline error (Eg: WRONGTYPE for a key,
+// which is not a hash) fails every key read by the pipeline, of all the
+// tables, with TranslibRedisClientEntryNotExist; as it does in GetEntries
+// for the keys of a table.
+func (d *DB) GetMultiTableEntries(tks []TableKeys) ([][]Value, [][]error) {
+	values := make([][]Value, len(tks))
+	errors := make([][]error, len(tks))
+
+	if (d == nil) || (d.client == nil) {
+		for i, tk := range tks {
+			values[i] = make([]Value, len(tk.Keys))
+			errors[i] = make([]error, len(tk.Keys))
+			for j := range errors[i] {
+				errors[i][j] = tlerr.TranslibDBConnectionReset{}
+			}
+		}
+		return values, errors
 	}
 
 	var now time.Time
@@ -2023,34 +2182,90 @@ func (d *DB) getEntries(ts *TableSpec, keys []Key, forceReadDB bool) ([]Value, [
 		now = time.Now()
 	}
 
-	var values = make([]Value, len(keys))
-	var errors []error
+	reqs := make([]*entriesReq, len(tks))
+	var dbKeys []string
+	for i, tk := range tks {
+		reqs[i] = d.getEntriesBegin(tk.Ts, tk.Keys, false)
+		dbKeys = append(dbKeys, reqs[i].dbKeys...)
+	}
+
+	var entryList []*redis.StringStringMapCmd
+	var err error
+	if len(dbKeys) > 0 {
+		entryList, err = d.getMultiEntry(nil, dbKeys)
+	}
+
+	for i, r := range reqs {
+		var rEntryList []*redis.StringStringMapCmd
+		if err == nil {
+			rEntryList, entryList = entryList[:len(r.dbKeys)], entryList[len(r.dbKeys):]
+		}
+		d.getEntriesEnd(r, rEntryList, err, now)
+		values[i], errors[i] = r.values, r.errors
+	}
+
+	return values, errors
+}
+
+// entriesReq is the state of the getEntries of a table, between the cache
+// lookup (getEntriesBegin), and the redis read (getEntriesEnd).
+type entriesReq struct {
+	ts        *TableSpec
+	keys      []Key
+	values    []Value
+	errors    []error
+	cacheHits uint
+	cacheChk  bool
+	useGCache bool
+	gen       uint64
+	keyIdxs   []int    // to keep the order of the input keys
+	dbKeys    []string // to be read from the DB
+}
+
+func (d *DB) getEntries(ts *TableSpec, keys []Key, forceReadDB bool) ([]Value, []error) {
+
+	var now time.Time
+	if d.dbStatsConfig.TimeStats {
+		now = time.Now()
+	}
+
+	r := d.getEntriesBegin(ts, keys, forceReadDB)
+
+	var entryList []*redis.StringStringMapCmd
+	var err error
+	if len(r.dbKeys) > 0 {
+		// get the values for the keys using redis pipeline
+		entryList, err = d.getMultiEntry(ts, r.dbKeys)
+	}
+
+	d.getEntriesEnd(r, entryList, err, now)
+
+	return r.values, r.errors
+}
+
+// getEntriesBegin gets the entries from the Tx and the caches; the keys
+// to be read from the DB are in the dbKeys of the returned entriesReq.
+func (d *DB) getEntriesBegin(ts *TableSpec, keys []Key, forceReadDB bool) *entriesReq {
+
+	if glog.V(3) {
+		glog.Info("GetEntries: Begin: ", d.Name(), ": ts: ", ts, " keys: ", keys)
+	}
+
+	r := &entriesReq{ts: ts, keys: keys, values: make([]Value, len(keys))}
 
-	var dur time.Duration
-	var stats Stats
 	var cacheHit bool
 	var txCacheHit bool
-	var cacheChk bool
-	var tblExist bool
-
 	var tbl Table
 
 	if (d.dbCacheConfig.PerConnection &&
 		d.dbCacheConfig.isCacheTable(ts.Name)) ||
 		(d.Opts.IsOnChangeEnabled && d.onCReg.isCacheTable(ts.Name)) {
-		cacheChk = true
-		tbl, tblExist = d.cache.Tables[ts.Name]
-	}
-
-	if d.dbStatsConfig.TableStats {
-		stats = d.stats.Tables[ts.Name]
-	} else {
-		stats = d.stats.AllTables
+		r.cacheChk = true
+		tbl = d.cache.Tables[ts.Name]
 	}
 
-	// to keep the order of the input keys
-	var keyIdxs []int
-	var dbKeys []string
+	r.useGCache = d.dbCacheConfig.Global &&
+		d.dbCacheConfig.isCacheTable(ts.Name) && !forceReadDB
 
 	for idx, key := range keys {
 		cacheHit = false
@@ -2058,95 +2273,123 @@ func (d *DB) getEntries(ts *TableSpec, keys []Key, forceReadDB bool) ([]Value, [
 		entry := d.key2redis(ts, key)
 
 		if valueTx, exist := d.txTsEntryMap[ts.Name][entry]; !exist {
-			if cacheChk && !forceReadDB {
+			if r.cacheChk && !forceReadDB {
 				if value, ok := tbl.entry[entry]; ok {
-					values[idx] = value.Copy()
+					r.values[idx] = value.Copy()
 					cacheHit = true
 				}
 			}
+			if !cacheHit && r.useGCache {
+				var value Value
+				if value, r.gen, cacheHit = dbGlobalCache.getEntry(d, ts,
+					entry); cacheHit {
+					r.values[idx] = value
+				}
+			}
 		} else {
-			values[idx] = valueTx.Copy()
+			r.values[idx] = valueTx.Copy()
 			txCacheHit = true
 			if len(valueTx.Field) == 0 {
 				keyErr := tlerr.TranslibRedisClientEntryNotExist{Entry: entry}
-				setError(keyErr, idx, &errors, len(keys))
+				setError(keyErr, idx, &r.errors, len(keys))
 			}
 		}
 
 		if !cacheHit && !txCacheHit {
-			keyIdxs = append(keyIdxs, idx)
-			dbKeys = append(dbKeys, entry)
+			r.keyIdxs = append(r.keyIdxs, idx)
+			r.dbKeys = append(r.dbKeys, entry)
 		}
 
 		if cacheHit {
-			stats.GetEntryCacheHits++
+			r.cacheHits++
 		}
 	}
 
-	if len(dbKeys) > 0 {
-		// get the values for the keys using redis pipeline
-		entryList, err := d.getMultiEntry(ts, dbKeys)
-		if err != nil {
-			glog.Error("GetEntries: ", d.Name(),
-				": error in getMultiEntry(", ts.Name, "): ", err.Error())
-			if errors == nil {
-				errors = make([]error, len(keys))
-			}
-			for i, dbKey := range dbKeys {
-				keyIdx := keyIdxs[i]
+	return r
+}
+
+// getEntriesEnd fills the values and errors of the dbKeys from the
+// getMultiEntry results, and updates the caches and stats.
+func (d *DB) getEntriesEnd(r *entriesReq, entryList []*redis.StringStringMapCmd,
+	err error, now time.Time) {
+
+	ts, keys, values := r.ts, r.keys, r.values
+	var stats Stats
+	var dur time.Duration
+
+	if len(r.dbKeys) == 0 {
+		// Nothing read from the DB
+	} else if err != nil {
+		// The pipeline error (of its first failed command) is the error of
+		// all the keys of the pipeline, including the ones read fine.
+		glog.Error("GetEntries: ", d.Name(),
+			": error in getMultiEntry(", ts.Name, "): ", err.Error())
+		if r.errors == nil {
+			r.errors = make([]error, len(keys))
+		}
+		for i, dbKey := range r.dbKeys {
+			keyIdx := r.keyIdxs[i]
+			values[keyIdx] = Value{}
+			r.errors[keyIdx] = tlerr.TranslibRedisClientEntryNotExist{Entry: dbKey}
+		}
+	} else {
+		// iterate the keys to fill the value and error slice
+		for i, dbKey := range r.dbKeys {
+			keyIdx := r.keyIdxs[i]
+			v := entryList[i]
+
+			if v == nil {
 				values[keyIdx] = Value{}
-				errors[keyIdx] = tlerr.TranslibRedisClientEntryNotExist{Entry: dbKey}
+				keyErr := tlerr.TranslibRedisClientEntryNotExist{Entry: dbKey}
+				setError(keyErr, keyIdx, &r.errors, len(keys))
+				continue
 			}
-		} else {
-			// iterate the keys to fill the value and error slice
-			for i, dbKey := range dbKeys {
-				keyIdx := keyIdxs[i]
-				v := entryList[i]
-
-				if v == nil {
-					values[keyIdx] = Value{}
-					keyErr := tlerr.TranslibRedisClientEntryNotExist{Entry: dbKey}
-					setError(keyErr, keyIdx, &errors, len(keys))
-					continue
-				}
 
-				dbValue := Value{}
-				res, e := v.Result()
-				if e != nil {
-					values[keyIdx] = dbValue
-					setError(e, keyIdx, &errors, len(keys))
-					glog.Warningf("GetEntries: %s: error %s; for the key %s",
-						d.Name(), e.Error(), dbKey)
-				} else {
-					dbValue.Field = res
-					values[keyIdx] = dbValue
-				}
+			dbValue := Value{}
+			res, e := v.Result()
+			if e != nil {
+				values[keyIdx] = dbValue
+				setError(e, keyIdx, &r.errors, len(keys))
+				glog.Warningf("GetEntries: %s: error %s; for the key %s",
+					d.Name(), e.Error(), dbKey)
+			} else {
+				dbValue.Field = res
+				values[keyIdx] = dbValue
+			}
 
-				if len(dbValue.Field) != 0 {
-					if cacheChk {
-						if !tblExist {
-							d.cache.Tables[ts.Name] = Table{
-								ts:       ts,
-								entry:    make(map[string]Value, InitialTableEntryCount),
-								complete: false,
-								patterns: make(map[string][]Key, InitialTablePatternCount),
-								db:       d,
-							}
-							tblExist = true
+			if len(dbValue.Field) != 0 {
+				if r.cacheChk {
+					if _, tblExist := d.cache.Tables[ts.Name]; !tblExist {
+						d.cache.Tables[ts.Name] = Table{
+							ts:       ts,
+							entry:    make(map[string]Value, InitialTableEntryCount),
+							complete: false,
+							patterns: make(map[string][]Key, InitialTablePatternCount),
+							db:       d,
 						}
-						d.cache.Tables[ts.Name].entry[dbKey] = dbValue.Copy()
-					}
-				} else if e == nil {
-					if glog.V(4) {
-						glog.Info("GetEntries: pipe.HGetAll(): empty map for the key: ", dbKey)
 					}
-					keyErr := tlerr.TranslibRedisClientEntryNotExist{Entry: dbKey}
-					setError(keyErr, keyIdx, &errors, len(keys))
+					d.cache.Tables[ts.Name].entry[dbKey] = dbValue.Copy()
+				}
+				if r.useGCache {
+					dbGlobalCache.putEntry(d, ts, dbKey, dbValue, r.gen)
+				}
+			} else if e == nil {
+				if glog.V(4) {
+					glog.Info("GetEntries: pipe.HGetAll(): empty map for the key: ", dbKey)
 				}
+				keyErr := tlerr.TranslibRedisClientEntryNotExist{Entry: dbKey}
+				setError(keyErr, keyIdx, &r.errors, len(keys))
 			}
 		}
 	}
 
+	if d.dbStatsConfig.TableStats {
+		stats = d.stats.Tables[ts.Name]
+	} else {
+		stats = d.stats.AllTables
+	}
+
+	stats.GetEntryCacheHits += r.cacheHits
 	stats.GetEntryHits = stats.GetEntryHits + uint(len(keys))
 	stats.Hits++
 	stats.GetEntriesHits++
@@ -2163,6 +2406,8 @@ func (d *DB) getEntries(ts *TableSpec, keys []Key, forceReadDB bool) ([]Value, [
 			stats.GetEntriesPeak = dur
 		}
 		stats.GetEntriesTime += dur
+
+		d.observeOp(&stats, OpGetEntries, ts, "", dur)
 	}
 
 	if d.dbStatsConfig.TableStats {
@@ -2172,10 +2417,8 @@ func (d *DB) getEntries(ts *TableSpec, keys []Key, forceReadDB bool) ([]Value, [
 	}
 
 	if glog.V(3) {
-		glog.Info("GetEntries: End: ", "ts: ", ts, "values: ", values, " errors: ", errors)
+		glog.Info("GetEntries: End: ", "ts: ", ts, "values: ", values, " errors: ", r.errors)
 	}
-
-	return values, errors
 }
 
 func setError(e error, idx int, errors *[]error, numKeys int) {
@@ -2194,6 +2437,13 @@ func (d *DB) getMultiEntry(ts *TableSpec, keys []string) ([]*redis.StringStringM
 
 	var results = make([]*redis.StringStringMapCmd, len(keys))
 
+	if d.cpDs != nil {
+		for i, key := range keys {
+			results[i] = redis.NewStringStringMapResult(d.cpDs.getEntry(key).Field, nil)
+		}
+		return results, nil
+	}
+
 	pipe := d.client.Pipeline()
 	defer pipe.Close()
 
@@ -0,0 +1,133 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"context"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/Azure/sonic-mgmt-common/cvl"
+	"github.com/golang/glog"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+//  Exported Types                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+// Backend is the redis-protocol server behind the DB connections. It is not
+// a storage abstraction: the DB layer always talks the redis protocol
+// through go-redis, and a Backend only provides the connections on which
+// those commands are served. The command set used by the DB layer, and
+// expected of every Backend, is:
+//
+//   - Hash: HGET, HGETALL, HSET, HMSET, HSETNX, HDEL, HEXISTS, HKEYS, HLEN
+//   - Key: GET, SET, DEL, EXISTS, KEYS, SCAN, HSCAN, TYPE
+//   - Pipelining, and MULTI, EXEC, DISCARD, WATCH, UNWATCH
+//   - PUBLISH, (P)SUBSCRIBE, (P)UNSUBSCRIBE, and keyspace notifications
+//   - EVAL/EVALSHA of the Lua scripts defined by the DB layer
+//
+// That command set is the operation-level interface of a Backend; there is
+// no Go interface for the hash, scan, transaction and pub/sub operations,
+// and DB is not decoupled from go-redis. A store other than redis is added
+// by serving these commands, as the MemoryBackend does.
+//
+// The redis-server (RedisBackend) is the default backend.
+type Backend interface {
+
+	// Name of the backend. Eg: "redis", "memory"
+	Name() string
+
+	// Dialer returns the go-redis dialer for connecting to the backend.
+	// nil indicates go-redis default dialer, i.e. the redis-server.
+	Dialer() func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// BackendEnv is the environment variable to select the backend at
+// startup. Eg: TRANSLIB_DB_BACKEND=memory
+const BackendEnv = "TRANSLIB_DB_BACKEND"
+
+// RedisBackend is the redis-server backend
+type RedisBackend struct {
+}
+
+func (b *RedisBackend) Name() string {
+	return "redis"
+}
+
+func (b *RedisBackend) Dialer() func(ctx context.Context, network,
+	addr string) (net.Conn, error) {
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Exported Functions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+// SetBackend sets the backend for the DB connections created after
+// this call. Existing DB connections are not affected. A nil backend
+// restores the default (redis) backend.
+func SetBackend(b Backend) {
+	if b == nil {
+		b = &RedisBackend{}
+	}
+
+	glog.Infof("SetBackend: %s", b.Name())
+
+	mutexBackend.Lock()
+	backend = b
+	mutexBackend.Unlock()
+
+	// CVL has its own CONFIG_DB connection; only its Dialer is changed.
+	cvlOpts := cvl.RedisOptions()
+	cvlOpts.Dialer = b.Dialer()
+	cvl.ReconfigureRedisOptions(cvlOpts)
+}
+
+// GetBackend returns the current backend
+func GetBackend() Backend {
+	mutexBackend.Lock()
+	defer mutexBackend.Unlock()
+	return backend
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Internal Functions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+var backend Backend = &RedisBackend{}
+
+var mutexBackend sync.Mutex
+
+// backendDialer returns the go-redis dialer of the current backend
+func backendDialer() func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return GetBackend().Dialer()
+}
+
+func init() {
+	switch name := os.Getenv(BackendEnv); name {
+	case "", "redis":
+	case "memory":
+		SetBackend(NewMemoryBackend())
+	default:
+		glog.Errorf("Unknown %s: %s; using redis", BackendEnv, name)
+	}
+}
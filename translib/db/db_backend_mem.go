@@ -0,0 +1,1196 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/golang/glog"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+//  Exported Types                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+// MemoryBackend is an in-process, in-memory redis-protocol server. It
+// serves the redis commands listed by Backend on net.Pipe connections,
+// with the same transaction (MULTI/EXEC/WATCH), pub/sub, and keyspace
+// notification semantics as the redis-server. It does not run Lua; the
+// scripts of the DB layer are served by registered Go equivalents (see
+// memScripts); other scripts, and the ScanCursor and CVL Lua predicates,
+// are not supported.
+// Each redis instance of the DB config (per namespace) gets its own store;
+// its unix socket and TCP addresses reach the same store.
+type MemoryBackend struct {
+	mu   sync.Mutex
+	srvs map[string]*memServer // Instance (or address) -> store
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Exported Functions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+// NewMemoryBackend creates an empty in-memory redis-protocol server
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{srvs: make(map[string]*memServer)}
+}
+
+func (b *MemoryBackend) Name() string {
+	return "memory"
+}
+
+func (b *MemoryBackend) Dialer() func(ctx context.Context, network,
+	addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		client, server := net.Pipe()
+		go b.getServer(addr).serve(server)
+		return client, nil
+	}
+}
+
+// FlushAll removes the data of all the databases.
+func (b *MemoryBackend) FlushAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, srv := range b.srvs {
+		srv.mu.Lock()
+		for _, mdb := range srv.dbs {
+			mdb.flush(srv)
+		}
+		srv.mu.Unlock()
+	}
+}
+
+// Restart drops all the connections, and the CONFIG SET parameters, as a
+// redis-server restart would. The data is retained.
+func (b *MemoryBackend) Restart() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, srv := range b.srvs {
+		srv.mu.Lock()
+		srv.config = make(map[string]string)
+		for c := range srv.conns {
+			c.conn.Close()
+		}
+		srv.mu.Unlock()
+	}
+}
+
+// getServer returns the store of the redis instance of the address
+func (b *MemoryBackend) getServer(addr string) *memServer {
+	inst := getDbInstOfAddr(addr)
+	if len(inst) == 0 {
+		inst = addr
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	srv, ok := b.srvs[inst]
+	if !ok {
+		srv = newMemServer()
+		b.srvs[inst] = srv
+	}
+	return srv
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Internal Types                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+// memServer is the in-memory store, and the command processor
+type memServer struct {
+	mu      sync.Mutex
+	dbs     map[int]*memDB
+	version uint64                // Modification counter, for WATCH
+	config  map[string]string     // CONFIG SET parameters
+	subs    map[*memConn]struct{} // Connections in the subscribe mode
+	conns   map[*memConn]struct{} // All the connections
+	cursors map[uint64]string     // SCAN/HSCAN cursor -> last returned
+	cursor  uint64                // Last allocated cursor
+}
+
+// memDB is one database (selection) of the memServer
+type memDB struct {
+	id       int
+	strs     map[string]string
+	hashes   map[string]map[string]string
+	streams  map[string]*memStream
+	versions map[string]uint64 // Last modification of the key
+}
+
+type memWatchKey struct {
+	db  int
+	key string
+}
+
+// memConn is a client connection to the memServer
+type memConn struct {
+	srv  *memServer
+	conn net.Conn
+	db   int
+
+	inMulti bool
+	txAbort bool       // Error while queuing a tx command
+	txCmds  [][]string // Queued tx commands
+	watched map[memWatchKey]uint64
+
+	channels map[string]struct{}
+	patterns map[string]struct{}
+
+	// Outbound queue. Replies and pub/sub messages are written from a
+	// separate goroutine, so that a slow reader never blocks the server.
+	outMu     sync.Mutex
+	outCond   *sync.Cond
+	out       [][]byte
+	outClosed bool
+}
+
+// memReply is a RESP encoded reply
+type memReply []byte
+
+type memCmd struct {
+	fn      func(c *memConn, args []string) memReply
+	minArgs int  // Including the command name
+	evenArg bool // Args after the key must be in pairs
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Internal Functions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+var errMemSyntax = errors.New("ERR syntax error")
+
+var memTxErrAbort = "EXECABORT Transaction discarded because of previous errors."
+
+var memCmds map[string]memCmd
+
+func init() {
+	memCmds = map[string]memCmd{
+		"PING":     {fn: memPing, minArgs: 1},
+		"ECHO":     {fn: memEcho, minArgs: 2},
+		"AUTH":     {fn: memOK, minArgs: 2},
+		"CLIENT":   {fn: memOK, minArgs: 2},
+		"READONLY": {fn: memOK, minArgs: 1},
+		"SELECT":   {fn: memSelect, minArgs: 2},
+		"CONFIG":   {fn: memConfig, minArgs: 2},
+		"FLUSHDB":  {fn: memFlushDB, minArgs: 1},
+		"FLUSHALL": {fn: memFlushAll, minArgs: 1},
+		"DBSIZE":   {fn: memDBSize, minArgs: 1},
+		"GET":      {fn: memGet, minArgs: 2},
+		"SET":      {fn: memSet, minArgs: 3},
+		"INCR":     {fn: memIncr, minArgs: 2},
+		"DEL":      {fn: memDel, minArgs: 2},
+		"EXISTS":   {fn: memExists, minArgs: 2},
+		"TYPE":     {fn: memType, minArgs: 2},
+		"KEYS":     {fn: memKeys, minArgs: 2},
+		"SCAN":     {fn: memScan, minArgs: 2},
+		"HGET":     {fn: memHGet, minArgs: 3},
+		"HMGET":    {fn: memHMGet, minArgs: 3},
+		"HGETALL":  {fn: memHGetAll, minArgs: 2},
+		"HSET":     {fn: memHSet, minArgs: 4, evenArg: true},
+		"HMSET":    {fn: memHMSet, minArgs: 4, evenArg: true},
+		"HSETNX":   {fn: memHSetNX, minArgs: 4},
+		"HDEL":     {fn: memHDel, minArgs: 3},
+		"HEXISTS":  {fn: memHExists, minArgs: 3},
+		"HKEYS":    {fn: memHKeys, minArgs: 2},
+		"HLEN":     {fn: memHLen, minArgs: 2},
+		"HSCAN":    {fn: memHScan, minArgs: 3},
+		"PUBLISH":  {fn: memPublish, minArgs: 3},
+		"XADD":     {fn: memXAdd, minArgs: 5},
+		"XRANGE":   {fn: memXRange, minArgs: 4},
+		"XLEN":     {fn: memXLen, minArgs: 2},
+		"EVAL":     {fn: memEval, minArgs: 3},
+		"EVALSHA":  {fn: memEvalSha, minArgs: 3},
+		"SCRIPT":   {fn: memScript, minArgs: 2},
+	}
+}
+
+func newMemServer() *memServer {
+	return &memServer{
+		dbs:     make(map[int]*memDB),
+		config:  make(map[string]string),
+		subs:    make(map[*memConn]struct{}),
+		conns:   make(map[*memConn]struct{}),
+		cursors: make(map[uint64]string),
+	}
+}
+
+// getDB returns the database of the id, creating it if needed.
+// Must be called with the server lock held.
+func (s *memServer) getDB(id int) *memDB {
+	mdb, ok := s.dbs[id]
+	if !ok {
+		mdb = &memDB{
+			id:       id,
+			strs:     make(map[string]string),
+			hashes:   make(map[string]map[string]string),
+			streams:  make(map[string]*memStream),
+			versions: make(map[string]uint64),
+		}
+		s.dbs[id] = mdb
+	}
+	return mdb
+}
+
+// serve processes the commands received on the connection, until it is
+// closed.
+func (s *memServer) serve(conn net.Conn) {
+	c := &memConn{srv: s, conn: conn}
+	c.outCond = sync.NewCond(&c.outMu)
+	go c.writer()
+
+	s.mu.Lock()
+	s.conns[c] = struct{}{}
+	s.mu.Unlock()
+
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readMemCommand(r)
+		if err != nil {
+			if err != io.EOF && glog.V(4) {
+				glog.Infof("memServer: read: %v", err)
+			}
+			break
+		}
+		if len(args) == 0 {
+			continue
+		}
+		if strings.EqualFold(args[0], "QUIT") {
+			c.send(memSimple("OK"))
+			break
+		}
+		c.send(c.process(args))
+	}
+
+	s.mu.Lock()
+	delete(s.subs, c)
+	delete(s.conns, c)
+	s.mu.Unlock()
+	c.closeOut()
+}
+
+// readMemCommand reads a RESP command (an array of bulk strings), or an
+// inline command.
+func readMemCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readMemLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 || line[0] != '*' {
+		return strings.Fields(line), nil
+	}
+
+	n, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid multibulk length: %s", line)
+	}
+
+	args := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		if line, err = readMemLine(r); err != nil {
+			return nil, err
+		}
+		if len(line) == 0 || line[0] != '$' {
+			return nil, fmt.Errorf("expected '$', got: %s", line)
+		}
+		blen, err := strconv.Atoi(line[1:])
+		if err != nil || blen < 0 {
+			return nil, fmt.Errorf("invalid bulk length: %s", line)
+		}
+		buf := make([]byte, blen+2)
+		if _, err = io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args = append(args, string(buf[:blen]))
+	}
+
+	return args, nil
+}
+
+func readMemLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// process executes a command, and returns its reply
+func (c *memConn) process(args []string) memReply {
+	name := strings.ToUpper(args[0])
+	s := c.srv
+
+	// Subscribe mode allows only the (un)subscribe commands, and PING
+	if len(c.channels) != 0 || len(c.patterns) != 0 {
+		switch name {
+		case "SUBSCRIBE", "PSUBSCRIBE", "UNSUBSCRIBE", "PUNSUBSCRIBE":
+		case "PING":
+			return memArray(memBulk("pong"), memBulk(""))
+		default:
+			return memError("ERR only (P)SUBSCRIBE / (P)UNSUBSCRIBE / " +
+				"PING / QUIT allowed in this context")
+		}
+	}
+
+	switch name {
+	case "SUBSCRIBE", "PSUBSCRIBE", "UNSUBSCRIBE", "PUNSUBSCRIBE":
+		if c.inMulti {
+			break
+		}
+		if len(args) < 2 && (name == "SUBSCRIBE" || name == "PSUBSCRIBE") {
+			return memArgsError(name)
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return c.subscribe(name, args[1:])
+
+	case "MULTI":
+		if c.inMulti {
+			return memError("ERR MULTI calls can not be nested")
+		}
+		c.inMulti = true
+		c.txAbort = false
+		c.txCmds = nil
+		return memSimple("OK")
+
+	case "EXEC":
+		if !c.inMulti {
+			return memError("ERR EXEC without MULTI")
+		}
+		return c.exec()
+
+	case "DISCARD":
+		if !c.inMulti {
+			return memError("ERR DISCARD without MULTI")
+		}
+		c.inMulti = false
+		c.txCmds = nil
+		c.watched = nil
+		return memSimple("OK")
+
+	case "WATCH":
+		if c.inMulti {
+			return memError("ERR WATCH inside MULTI is not allowed")
+		}
+		if len(args) < 2 {
+			return memArgsError(name)
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if c.watched == nil {
+			c.watched = make(map[memWatchKey]uint64)
+		}
+		mdb := s.getDB(c.db)
+		for _, k := range args[1:] {
+			wk := memWatchKey{db: c.db, key: k}
+			if _, ok := c.watched[wk]; !ok {
+				c.watched[wk] = mdb.versions[k]
+			}
+		}
+		return memSimple("OK")
+
+	case "UNWATCH":
+		c.watched = nil
+		return memSimple("OK")
+	}
+
+	cmd, ok := memCmds[name]
+	if !ok || len(args) < cmd.minArgs ||
+		(cmd.evenArg && (len(args)-2)%2 != 0) {
+		if c.inMulti {
+			c.txAbort = true
+		}
+		if !ok {
+			return memError(fmt.Sprintf("ERR unknown command '%s'", args[0]))
+		}
+		return memArgsError(name)
+	}
+
+	if c.inMulti {
+		c.txCmds = append(c.txCmds, args)
+		return memSimple("QUEUED")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return cmd.fn(c, args)
+}
+
+// exec executes the queued tx commands atomically, if none of the watched
+// keys have been modified.
+func (c *memConn) exec() memReply {
+	s := c.srv
+	txCmds, txAbort, watched := c.txCmds, c.txAbort, c.watched
+	c.inMulti, c.txAbort, c.txCmds, c.watched = false, false, nil, nil
+
+	if txAbort {
+		return memError(memTxErrAbort)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for wk, ver := range watched {
+		if s.getDB(wk.db).versions[wk.key] != ver {
+			glog.Infof("memServer: EXEC aborted; %v modified", wk)
+			return memNilArray()
+		}
+	}
+
+	replies := make([]memReply, 0, len(txCmds))
+	for _, args := range txCmds {
+		cmd := memCmds[strings.ToUpper(args[0])]
+		replies = append(replies, cmd.fn(c, args))
+	}
+
+	return memArray(replies...)
+}
+
+// subscribe handles the (P)(UN)SUBSCRIBE commands. Each channel/pattern
+// gets its own reply; all except the last are sent from here.
+// Must be called with the server lock held.
+func (c *memConn) subscribe(name string, names []string) memReply {
+	pattern := strings.HasPrefix(name, "P")
+	set := &c.channels
+	if pattern {
+		set = &c.patterns
+	}
+	if *set == nil {
+		*set = make(map[string]struct{})
+	}
+
+	kind := strings.ToLower(name)
+	if len(names) == 0 { // UNSUBSCRIBE all
+		for n := range *set {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return memArray(memBulk(kind), memNilBulk(),
+				memInt(int64(len(c.channels)+len(c.patterns))))
+		}
+	}
+
+	var reply memReply
+	for i, n := range names {
+		if strings.HasPrefix(kind, "un") || strings.HasPrefix(kind, "pun") {
+			delete(*set, n)
+		} else {
+			(*set)[n] = struct{}{}
+		}
+		reply = memArray(memBulk(kind), memBulk(n),
+			memInt(int64(len(c.channels)+len(c.patterns))))
+		if i != len(names)-1 {
+			c.send(reply)
+		}
+	}
+
+	if len(c.channels) != 0 || len(c.patterns) != 0 {
+		c.srv.subs[c] = struct{}{}
+	} else {
+		delete(c.srv.subs, c)
+	}
+
+	return reply
+}
+
+// publish sends the message to the subscribers of the channel, and returns
+// the number of receivers. Must be called with the server lock held.
+func (s *memServer) publish(channel, message string) int64 {
+	var n int64
+	for c := range s.subs {
+		if _, ok := c.channels[channel]; ok {
+			c.send(memArray(memBulk("message"), memBulk(channel),
+				memBulk(message)))
+			n++
+		}
+		for p := range c.patterns {
+			if patternMatch(channel, 0, p, 0) {
+				c.send(memArray(memBulk("pmessage"), memBulk(p),
+					memBulk(channel), memBulk(message)))
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// notify publishes the keyspace/keyevent notification, as per the
+// notify-keyspace-events config. class is the redis event class (Eg:
+// 'h' for hash, 'g' for generic, '$' for string).
+// Must be called with the server lock held.
+func (s *memServer) notify(dbId int, class byte, event, key string) {
+	flags := s.config["notify-keyspace-events"]
+	if flags == "" || len(s.subs) == 0 {
+		return
+	}
+	if !strings.ContainsRune(flags, 'A') &&
+		!strings.ContainsRune(flags, rune(class)) {
+		return
+	}
+	if strings.ContainsRune(flags, 'K') {
+		s.publish(fmt.Sprintf("__keyspace@%d__:%s", dbId, key), event)
+	}
+	if strings.ContainsRune(flags, 'E') {
+		s.publish(fmt.Sprintf("__keyevent@%d__:%s", dbId, event), key)
+	}
+}
+
+// touch marks the key as modified. Must be called with the server lock held.
+func (mdb *memDB) touch(s *memServer, key string) {
+	s.version++
+	mdb.versions[key] = s.version
+}
+
+// del removes the key, and returns true if it existed.
+// Must be called with the server lock held.
+func (mdb *memDB) del(s *memServer, key string) bool {
+	_, isStr := mdb.strs[key]
+	_, isHash := mdb.hashes[key]
+	_, isStream := mdb.streams[key]
+	if !isStr && !isHash && !isStream {
+		return false
+	}
+	delete(mdb.strs, key)
+	delete(mdb.hashes, key)
+	delete(mdb.streams, key)
+	mdb.touch(s, key)
+	return true
+}
+
+func (mdb *memDB) flush(s *memServer) {
+	for k := range mdb.strs {
+		mdb.touch(s, k)
+	}
+	for k := range mdb.hashes {
+		mdb.touch(s, k)
+	}
+	for k := range mdb.streams {
+		mdb.touch(s, k)
+	}
+	mdb.strs = make(map[string]string)
+	mdb.hashes = make(map[string]map[string]string)
+	mdb.streams = make(map[string]*memStream)
+}
+
+// keys returns the sorted keys matching the pattern
+func (mdb *memDB) keys(pattern string) []string {
+	keys := make([]string, 0)
+	for k := range mdb.strs {
+		if patternMatch(k, 0, pattern, 0) {
+			keys = append(keys, k)
+		}
+	}
+	for k := range mdb.hashes {
+		if patternMatch(k, 0, pattern, 0) {
+			keys = append(keys, k)
+		}
+	}
+	for k := range mdb.streams {
+		if patternMatch(k, 0, pattern, 0) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// hash returns the hash of the key. wrongType is true if the key exists,
+// and is not a hash.
+func (mdb *memDB) hash(key string) (h map[string]string, wrongType bool) {
+	if _, ok := mdb.strs[key]; ok {
+		return nil, true
+	}
+	if _, ok := mdb.streams[key]; ok {
+		return nil, true
+	}
+	return mdb.hashes[key], false
+}
+
+// memMaxCursors is the number of SCAN/HSCAN cursors remembered by a
+// memServer. Older cursors expire, so that abandoned scans do not grow
+// the cursors map; continuing an expired cursor fails.
+const memMaxCursors = 1024
+
+// scan returns a page of at most count items (after the item remembered
+// by the cursor), and the next cursor (0 when done). Items present for the
+// whole scan are returned exactly once, even if others are added/removed.
+// Must be called with the server lock held.
+func (s *memServer) scan(items []string, cursor uint64, count int) ([]string, uint64, error) {
+	sort.Strings(items)
+	start := 0
+	if cursor != 0 {
+		last, ok := s.cursors[cursor]
+		if !ok {
+			return nil, 0, errors.New("ERR invalid cursor")
+		}
+		delete(s.cursors, cursor)
+		start = sort.SearchStrings(items, last)
+		if start < len(items) && items[start] == last {
+			start++
+		}
+	}
+
+	end := start + count
+	if end >= len(items) {
+		return items[start:], 0, nil
+	}
+
+	s.cursor++
+	s.cursors[s.cursor] = items[end-1]
+	if s.cursor > memMaxCursors {
+		delete(s.cursors, s.cursor-memMaxCursors)
+	}
+	return items[start:end], s.cursor, nil
+}
+
+// scanArgs parses the SCAN/HSCAN cursor, MATCH and COUNT arguments
+func scanArgs(args []string) (cursor uint64, pattern string, count int, err error) {
+	if cursor, err = strconv.ParseUint(args[0], 10, 64); err != nil {
+		return 0, "", 0, errors.New("ERR invalid cursor")
+	}
+	pattern, count = "*", 10
+	for i := 1; i < len(args); i += 2 {
+		if i+1 >= len(args) {
+			return 0, "", 0, errMemSyntax
+		}
+		switch strings.ToUpper(args[i]) {
+		case "MATCH":
+			pattern = args[i+1]
+		case "COUNT":
+			if count, err = strconv.Atoi(args[i+1]); err != nil || count < 1 {
+				return 0, "", 0, errMemSyntax
+			}
+		case "TYPE":
+		default:
+			return 0, "", 0, errMemSyntax
+		}
+	}
+	return cursor, pattern, count, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Commands                                                                  //
+////////////////////////////////////////////////////////////////////////////////
+
+// All the command functions are called with the server lock held.
+
+func memOK(c *memConn, args []string) memReply {
+	return memSimple("OK")
+}
+
+func memPing(c *memConn, args []string) memReply {
+	if len(args) > 1 {
+		return memBulk(args[1])
+	}
+	return memSimple("PONG")
+}
+
+func memEcho(c *memConn, args []string) memReply {
+	return memBulk(args[1])
+}
+
+func memSelect(c *memConn, args []string) memReply {
+	id, err := strconv.Atoi(args[1])
+	if err != nil || id < 0 {
+		return memError("ERR DB index is out of range")
+	}
+	c.db = id
+	return memSimple("OK")
+}
+
+func memConfig(c *memConn, args []string) memReply {
+	switch strings.ToUpper(args[1]) {
+	case "SET":
+		if len(args) != 4 {
+			return memArgsError("CONFIG")
+		}
+		c.srv.config[strings.ToLower(args[2])] = args[3]
+		return memSimple("OK")
+	case "GET":
+		if len(args) != 3 {
+			return memArgsError("CONFIG")
+		}
+		var replies []memReply
+		for k, v := range c.srv.config {
+			if patternMatch(k, 0, strings.ToLower(args[2]), 0) {
+				replies = append(replies, memBulk(k), memBulk(v))
+			}
+		}
+		return memArray(replies...)
+	}
+	return memOK(c, args)
+}
+
+func memFlushDB(c *memConn, args []string) memReply {
+	c.srv.getDB(c.db).flush(c.srv)
+	return memSimple("OK")
+}
+
+func memFlushAll(c *memConn, args []string) memReply {
+	for _, mdb := range c.srv.dbs {
+		mdb.flush(c.srv)
+	}
+	return memSimple("OK")
+}
+
+func memDBSize(c *memConn, args []string) memReply {
+	mdb := c.srv.getDB(c.db)
+	return memInt(int64(len(mdb.strs) + len(mdb.hashes) + len(mdb.streams)))
+}
+
+func memGet(c *memConn, args []string) memReply {
+	mdb := c.srv.getDB(c.db)
+	if _, ok := mdb.hashes[args[1]]; ok {
+		return memWrongType()
+	}
+	if _, ok := mdb.streams[args[1]]; ok {
+		return memWrongType()
+	}
+	if v, ok := mdb.strs[args[1]]; ok {
+		return memBulk(v)
+	}
+	return memNilBulk()
+}
+
+func memSet(c *memConn, args []string) memReply {
+	mdb := c.srv.getDB(c.db)
+	key := args[1]
+	_, exists := mdb.strs[key]
+	if _, ok := mdb.hashes[key]; ok {
+		exists = true
+	}
+	if _, ok := mdb.streams[key]; ok {
+		exists = true
+	}
+	for _, opt := range args[3:] {
+		switch strings.ToUpper(opt) {
+		case "NX":
+			if exists {
+				return memNilBulk()
+			}
+		case "XX":
+			if !exists {
+				return memNilBulk()
+			}
+		}
+	}
+	delete(mdb.hashes, key)
+	delete(mdb.streams, key)
+	mdb.strs[key] = args[2]
+	mdb.touch(c.srv, key)
+	c.srv.notify(c.db, '$', "set", key)
+	return memSimple("OK")
+}
+
+func memIncr(c *memConn, args []string) memReply {
+	n, errReply := c.incr(args[1])
+	if errReply != nil {
+		return errReply
+	}
+	return memInt(n)
+}
+
+func memDel(c *memConn, args []string) memReply {
+	mdb := c.srv.getDB(c.db)
+	var n int64
+	for _, k := range args[1:] {
+		if mdb.del(c.srv, k) {
+			n++
+			c.srv.notify(c.db, 'g', "del", k)
+		}
+	}
+	return memInt(n)
+}
+
+func memExists(c *memConn, args []string) memReply {
+	mdb := c.srv.getDB(c.db)
+	var n int64
+	for _, k := range args[1:] {
+		_, isStr := mdb.strs[k]
+		_, isHash := mdb.hashes[k]
+		_, isStream := mdb.streams[k]
+		if isStr || isHash || isStream {
+			n++
+		}
+	}
+	return memInt(n)
+}
+
+func memType(c *memConn, args []string) memReply {
+	mdb := c.srv.getDB(c.db)
+	if _, ok := mdb.strs[args[1]]; ok {
+		return memSimple("string")
+	}
+	if _, ok := mdb.hashes[args[1]]; ok {
+		return memSimple("hash")
+	}
+	if _, ok := mdb.streams[args[1]]; ok {
+		return memSimple("stream")
+	}
+	return memSimple("none")
+}
+
+func memKeys(c *memConn, args []string) memReply {
+	return memStrings(c.srv.getDB(c.db).keys(args[1]))
+}
+
+func memScan(c *memConn, args []string) memReply {
+	cursor, pattern, count, err := scanArgs(args[1:])
+	if err != nil {
+		return memError(err.Error())
+	}
+	keys, next, err := c.srv.scan(c.srv.getDB(c.db).keys(pattern), cursor, count)
+	if err != nil {
+		return memError(err.Error())
+	}
+	return memArray(memBulk(strconv.FormatUint(next, 10)), memStrings(keys))
+}
+
+func memHGet(c *memConn, args []string) memReply {
+	h, wrongType := c.srv.getDB(c.db).hash(args[1])
+	if wrongType {
+		return memWrongType()
+	}
+	if v, ok := h[args[2]]; ok {
+		return memBulk(v)
+	}
+	return memNilBulk()
+}
+
+func memHMGet(c *memConn, args []string) memReply {
+	h, wrongType := c.srv.getDB(c.db).hash(args[1])
+	if wrongType {
+		return memWrongType()
+	}
+	replies := make([]memReply, 0, len(args)-2)
+	for _, f := range args[2:] {
+		if v, ok := h[f]; ok {
+			replies = append(replies, memBulk(v))
+		} else {
+			replies = append(replies, memNilBulk())
+		}
+	}
+	return memArray(replies...)
+}
+
+func memHGetAll(c *memConn, args []string) memReply {
+	h, wrongType := c.srv.getDB(c.db).hash(args[1])
+	if wrongType {
+		return memWrongType()
+	}
+	return memStrings(hashFlatten(h))
+}
+
+// hashFlatten returns the field, value pairs of the hash, sorted by field
+func hashFlatten(h map[string]string) []string {
+	fields := make([]string, 0, len(h))
+	for f := range h {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	fv := make([]string, 0, 2*len(h))
+	for _, f := range fields {
+		fv = append(fv, f, h[f])
+	}
+	return fv
+}
+
+// incr increments the integer value of the string key, and returns it. The
+// error reply is nil on success.
+func (c *memConn) incr(key string) (int64, memReply) {
+	mdb := c.srv.getDB(c.db)
+	if _, isHash := mdb.hashes[key]; isHash {
+		return 0, memWrongType()
+	}
+	if _, isStream := mdb.streams[key]; isStream {
+		return 0, memWrongType()
+	}
+	var n int64
+	if v, ok := mdb.strs[key]; ok {
+		var err error
+		if n, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return 0, memError("ERR value is not an integer or out of range")
+		}
+	}
+	n++
+	mdb.strs[key] = strconv.FormatInt(n, 10)
+	mdb.touch(c.srv, key)
+	c.srv.notify(c.db, '$', "incrby", key)
+	return n, nil
+}
+
+// hset sets the field value pairs, and returns the number of new fields
+func (c *memConn) hset(key string, fvs []string) (int64, bool) {
+	mdb := c.srv.getDB(c.db)
+	h, wrongType := mdb.hash(key)
+	if wrongType {
+		return 0, false
+	}
+	if h == nil {
+		h = make(map[string]string)
+		mdb.hashes[key] = h
+	}
+	var n int64
+	for i := 0; i+1 < len(fvs); i += 2 {
+		if _, ok := h[fvs[i]]; !ok {
+			n++
+		}
+		h[fvs[i]] = fvs[i+1]
+	}
+	mdb.touch(c.srv, key)
+	c.srv.notify(c.db, 'h', "hset", key)
+	return n, true
+}
+
+func memHSet(c *memConn, args []string) memReply {
+	n, ok := c.hset(args[1], args[2:])
+	if !ok {
+		return memWrongType()
+	}
+	return memInt(n)
+}
+
+func memHMSet(c *memConn, args []string) memReply {
+	if _, ok := c.hset(args[1], args[2:]); !ok {
+		return memWrongType()
+	}
+	return memSimple("OK")
+}
+
+func memHSetNX(c *memConn, args []string) memReply {
+	h, wrongType := c.srv.getDB(c.db).hash(args[1])
+	if wrongType {
+		return memWrongType()
+	}
+	if _, ok := h[args[2]]; ok {
+		return memInt(0)
+	}
+	c.hset(args[1], args[2:4])
+	return memInt(1)
+}
+
+// hdel deletes the fields, and the key if no fields remain. Returns the
+// number of fields deleted.
+func (c *memConn) hdel(key string, fields []string) (int64, bool) {
+	mdb := c.srv.getDB(c.db)
+	h, wrongType := mdb.hash(key)
+	if wrongType {
+		return 0, false
+	}
+	var n int64
+	for _, f := range fields {
+		if _, ok := h[f]; ok {
+			delete(h, f)
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, true
+	}
+	mdb.touch(c.srv, key)
+	c.srv.notify(c.db, 'h', "hdel", key)
+	if len(h) == 0 {
+		mdb.del(c.srv, key)
+		c.srv.notify(c.db, 'g', "del", key)
+	}
+	return n, true
+}
+
+func memHDel(c *memConn, args []string) memReply {
+	n, ok := c.hdel(args[1], args[2:])
+	if !ok {
+		return memWrongType()
+	}
+	return memInt(n)
+}
+
+func memHExists(c *memConn, args []string) memReply {
+	h, wrongType := c.srv.getDB(c.db).hash(args[1])
+	if wrongType {
+		return memWrongType()
+	}
+	if _, ok := h[args[2]]; ok {
+		return memInt(1)
+	}
+	return memInt(0)
+}
+
+func memHKeys(c *memConn, args []string) memReply {
+	h, wrongType := c.srv.getDB(c.db).hash(args[1])
+	if wrongType {
+		return memWrongType()
+	}
+	fields := make([]string, 0, len(h))
+	for f := range h {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return memStrings(fields)
+}
+
+func memHLen(c *memConn, args []string) memReply {
+	h, wrongType := c.srv.getDB(c.db).hash(args[1])
+	if wrongType {
+		return memWrongType()
+	}
+	return memInt(int64(len(h)))
+}
+
+func memHScan(c *memConn, args []string) memReply {
+	h, wrongType := c.srv.getDB(c.db).hash(args[1])
+	if wrongType {
+		return memWrongType()
+	}
+	cursor, pattern, count, err := scanArgs(args[2:])
+	if err != nil {
+		return memError(err.Error())
+	}
+	fields := make([]string, 0, len(h))
+	for f := range h {
+		if patternMatch(f, 0, pattern, 0) {
+			fields = append(fields, f)
+		}
+	}
+	fields, next, err := c.srv.scan(fields, cursor, count)
+	if err != nil {
+		return memError(err.Error())
+	}
+	fv := make([]string, 0, 2*len(fields))
+	for _, f := range fields {
+		fv = append(fv, f, h[f])
+	}
+	return memArray(memBulk(strconv.FormatUint(next, 10)), memStrings(fv))
+}
+
+func memPublish(c *memConn, args []string) memReply {
+	return memInt(c.srv.publish(args[1], args[2]))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Outbound Queue                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+// send queues the reply to be written to the connection
+func (c *memConn) send(reply memReply) {
+	c.outMu.Lock()
+	if !c.outClosed {
+		c.out = append(c.out, reply)
+		c.outCond.Signal()
+	}
+	c.outMu.Unlock()
+}
+
+func (c *memConn) closeOut() {
+	c.outMu.Lock()
+	c.outClosed = true
+	c.outCond.Signal()
+	c.outMu.Unlock()
+}
+
+// writer writes the queued replies to the connection, and closes the
+// connection when the queue is closed.
+func (c *memConn) writer() {
+	defer c.conn.Close()
+	for {
+		c.outMu.Lock()
+		for len(c.out) == 0 && !c.outClosed {
+			c.outCond.Wait()
+		}
+		out, closed := c.out, c.outClosed
+		c.out = nil
+		c.outMu.Unlock()
+
+		for _, reply := range out {
+			if _, err := c.conn.Write(reply); err != nil {
+				c.closeOut()
+				return
+			}
+		}
+		if closed {
+			return
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  RESP Encoding                                                             //
+////////////////////////////////////////////////////////////////////////////////
+
+func memSimple(s string) memReply {
+	return memReply("+" + s + "\r\n")
+}
+
+func memError(s string) memReply {
+	return memReply("-" + s + "\r\n")
+}
+
+func memArgsError(name string) memReply {
+	return memError("ERR wrong number of arguments for '" +
+		strings.ToLower(name) + "' command")
+}
+
+func memWrongType() memReply {
+	return memError("WRONGTYPE Operation against a key holding the " +
+		"wrong kind of value")
+}
+
+func memInt(n int64) memReply {
+	return memReply(":" + strconv.FormatInt(n, 10) + "\r\n")
+}
+
+func memBulk(s string) memReply {
+	return memReply("$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n")
+}
+
+func memNilBulk() memReply {
+	return memReply("$-1\r\n")
+}
+
+func memNilArray() memReply {
+	return memReply("*-1\r\n")
+}
+
+func memArray(items ...memReply) memReply {
+	var b bytes.Buffer
+	b.WriteString("*" + strconv.Itoa(len(items)) + "\r\n")
+	for _, item := range items {
+		b.Write(item)
+	}
+	return b.Bytes()
+}
+
+func memStrings(items []string) memReply {
+	replies := make([]memReply, 0, len(items))
+	for _, item := range items {
+		replies = append(replies, memBulk(item))
+	}
+	return memArray(replies...)
+}
@@ -0,0 +1,502 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Azure/sonic-mgmt-common/cvl"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+//  Internal Types                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+// memScriptFunc is the Go equivalent of a Lua script, for the MemoryBackend.
+// It is called with the server lock held, which makes it atomic, like the
+// Lua scripts in the redis-server.
+type memScriptFunc func(c *memConn, keys []string, argv []string) memReply
+
+////////////////////////////////////////////////////////////////////////////////
+//  Internal Functions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+var memScripts map[string]memScriptFunc // SHA1 of the Lua script -> Go func
+
+var memScriptsOnce sync.Once
+
+// getMemScript returns the Go equivalent of the Lua script of the SHA1
+// digest. The table is built on first use, since the scripts are created
+// in the package init()s.
+func getMemScript(sha string) memScriptFunc {
+	memScriptsOnce.Do(func() {
+		memScripts = map[string]memScriptFunc{
+			luaScriptExistsKeysPatterns.Hash(): memLuaExistsKeysPatterns,
+			luaScriptGetTable.Hash():           memLuaGetTable,
+			luaScriptUnlock.Hash():             memLuaUnlock,
+			luaScriptLock.Hash():               memLuaLock,
+			luaScriptRenewLock.Hash():          memLuaRenewLock,
+			luaScriptCheckConds.Hash():         memLuaCheckConds,
+			luaScriptScanEntries.Hash():        memLuaScanEntries,
+			memScriptSha(luaScriptJournal):     memLuaJournal,
+		}
+
+		// CVL scripts, run via cvl.RunLuaOn()
+		for name, fn := range map[string]memScriptFunc{
+			"count_entries":  memLuaCountEntries,
+			"filter_entries": memLuaFilterEntries,
+		} {
+			if sha := cvl.LuaScriptHash(name); sha != "" {
+				memScripts[sha] = fn
+			}
+		}
+	})
+
+	return memScripts[strings.ToLower(sha)]
+}
+
+func memScriptSha(script string) string {
+	sum := sha1.Sum([]byte(script))
+	return hex.EncodeToString(sum[:])
+}
+
+func memEval(c *memConn, args []string) memReply {
+	fn := getMemScript(memScriptSha(args[1]))
+	if fn == nil {
+		return memError("ERR script not supported by the memory backend")
+	}
+	return memRunScript(c, fn, args[2:])
+}
+
+func memEvalSha(c *memConn, args []string) memReply {
+	fn := getMemScript(args[1])
+	if fn == nil {
+		return memError("NOSCRIPT No matching script. Please use EVAL.")
+	}
+	return memRunScript(c, fn, args[2:])
+}
+
+// memRunScript parses the numkeys, keys, and args of the EVAL(SHA), and
+// calls the script function.
+func memRunScript(c *memConn, fn memScriptFunc, args []string) memReply {
+	numKeys, err := strconv.Atoi(args[0])
+	if err != nil || numKeys < 0 {
+		return memError("ERR value is not an integer or out of range")
+	}
+	if numKeys > len(args)-1 {
+		return memError("ERR Number of keys can't be greater than number of args")
+	}
+	return fn(c, args[1:1+numKeys], args[1+numKeys:])
+}
+
+func memScript(c *memConn, args []string) memReply {
+	switch strings.ToUpper(args[1]) {
+	case "LOAD":
+		if len(args) != 3 {
+			return memArgsError("SCRIPT")
+		}
+		sha := memScriptSha(args[2])
+		if getMemScript(sha) == nil {
+			return memError("ERR script not supported by the memory backend")
+		}
+		return memBulk(sha)
+	case "EXISTS":
+		replies := make([]memReply, 0, len(args)-2)
+		for _, sha := range args[2:] {
+			if getMemScript(sha) != nil {
+				replies = append(replies, memInt(1))
+			} else {
+				replies = append(replies, memInt(0))
+			}
+		}
+		return memArray(replies...)
+	}
+	return memSimple("OK")
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Lua Script Equivalents                                                    //
+////////////////////////////////////////////////////////////////////////////////
+
+// memLuaExistsKeysPatterns is luaScriptExistsKeysPatterns
+func memLuaExistsKeysPatterns(c *memConn, keys []string, argv []string) memReply {
+	if len(keys) == 0 {
+		return memError("ERR missing KEYS[1]")
+	}
+	if len(c.srv.getDB(c.db).keys(keys[0])) != 0 {
+		return memBulk("true")
+	}
+	return memBulk("false")
+}
+
+// memLuaGetTable is luaScriptGetTable
+func memLuaGetTable(c *memConn, keys []string, argv []string) memReply {
+	if len(keys) == 0 {
+		return memError("ERR missing KEYS[1]")
+	}
+	mdb := c.srv.getDB(c.db)
+	var tkNv []memReply
+	for _, k := range mdb.keys(keys[0]) {
+		h, wrongType := mdb.hash(k)
+		if wrongType {
+			return memWrongType()
+		}
+		tkNv = append(tkNv, memBulk(k), memStrings(hashFlatten(h)))
+	}
+	return memArray(tkNv...)
+}
+
+// memLuaUnlock is luaScriptUnlock
+func memLuaUnlock(c *memConn, keys []string, argv []string) memReply {
+	if len(keys) == 0 || len(argv) < 3 {
+		return memError("ERR missing KEYS[1] or ARGV[1..3]")
+	}
+	h, wrongType := c.srv.getDB(c.db).hash(keys[0])
+	if wrongType {
+		return memWrongType()
+	}
+	fieldVal, ok := h[argv[0]]
+	if !ok {
+		return memInt(0)
+	}
+	comm, id := fieldVal, ""
+	if colon := strings.Index(fieldVal, ":"); colon >= 0 {
+		comm, id = fieldVal[:colon], fieldVal[colon+1:]
+	}
+	if (argv[1] == "*" || argv[1] == comm) && (argv[2] == "*" || argv[2] == id) {
+		c.hdel(keys[0], []string{argv[0] + lockLeaseSuffix})
+		n, _ := c.hdel(keys[0], argv[:1])
+		return memInt(n)
+	}
+	return memInt(0)
+}
+
+// memLuaLock is luaScriptLock
+func memLuaLock(c *memConn, keys []string, argv []string) memReply {
+	if len(keys) < 2 || len(argv) < 9 {
+		return memError("ERR missing KEYS[1..2] or ARGV[1..9]")
+	}
+	mdb := c.srv.getDB(c.db)
+	name, waiter := argv[0], argv[4]
+	now, _ := strconv.ParseInt(argv[3], 10, 64)
+	since, _ := strconv.ParseInt(argv[5], 10, 64)
+	stale, _ := strconv.ParseInt(argv[6], 10, 64)
+
+	waits, wrongType := mdb.hash(keys[1])
+	if wrongType {
+		return memWrongType()
+	}
+	ahead := false
+	var staleWaiters []string
+	for f, v := range waits {
+		if f == waiter || !strings.HasPrefix(f, name+"|") {
+			continue
+		}
+		var fSince, fSeen int64
+		times := strings.SplitN(v, ":", 2)
+		if len(times) == 2 {
+			fSince, _ = strconv.ParseInt(times[0], 10, 64)
+			fSeen, _ = strconv.ParseInt(times[1], 10, 64)
+		}
+		if fSeen == 0 || fSeen+stale < now {
+			staleWaiters = append(staleWaiters, f)
+		} else if waiter == "" || fSince < since || (fSince == since && f < waiter) {
+			ahead = true
+		}
+	}
+	c.hdel(keys[1], staleWaiters)
+
+	locks, wrongType := mdb.hash(keys[0])
+	if wrongType {
+		return memWrongType()
+	}
+	var result int64 = 1
+	if cur, ok := locks[name]; ok {
+		result = 0
+		lease := strings.SplitN(locks[name+lockLeaseSuffix], ":", 2)
+		expiry, err := strconv.ParseInt(lease[0], 10, 64)
+		if (err == nil && expiry < now) ||
+			(argv[7] != "" && cur == argv[7] && len(lease) == 2 && lease[1] == argv[8]) {
+			result = 2
+		}
+	}
+	if result > 0 && ahead {
+		result = -1
+	}
+
+	if result > 0 {
+		c.hset(keys[0], []string{name, argv[1], name + lockLeaseSuffix, argv[2]})
+		if waiter != "" {
+			c.hdel(keys[1], []string{waiter})
+		}
+	} else if waiter != "" {
+		c.hset(keys[1], []string{waiter, argv[5] + ":" + argv[3]})
+	}
+	return memInt(result)
+}
+
+// memLuaRenewLock is luaScriptRenewLock
+func memLuaRenewLock(c *memConn, keys []string, argv []string) memReply {
+	if len(keys) == 0 || len(argv) < 4 {
+		return memError("ERR missing KEYS[1] or ARGV[1..4]")
+	}
+	h, wrongType := c.srv.getDB(c.db).hash(keys[0])
+	if wrongType {
+		return memWrongType()
+	}
+	if cur, ok := h[argv[0]]; !ok || cur != argv[1] {
+		return memInt(0)
+	}
+	lease := strings.SplitN(h[argv[0]+lockLeaseSuffix], ":", 2)
+	if _, err := strconv.ParseUint(lease[0], 10, 64); err != nil ||
+		len(lease) != 2 || lease[1] != argv[3] {
+		return memInt(0)
+	}
+	c.hset(keys[0], []string{argv[0] + lockLeaseSuffix, argv[2]})
+	return memInt(1)
+}
+
+// memLuaJournal is luaScriptJournal
+func memLuaJournal(c *memConn, keys []string, argv []string) memReply {
+	if len(keys) == 0 || len(argv) < 2 {
+		return memError("ERR missing KEYS[1] or ARGV[1..2]")
+	}
+	seq, errReply := c.incr(keys[0])
+	if errReply != nil {
+		return errReply
+	}
+
+	if len(keys) > 1 {
+		maxLen, _ := strconv.Atoi(argv[1])
+		fvs := []string{"seq", strconv.FormatInt(seq, 10), "record", argv[0]}
+		if _, ok := c.xadd(keys[1], maxLen, fvs); !ok {
+			return memWrongType()
+		}
+	}
+	return memInt(seq)
+}
+
+// memLuaCheckConds is luaScriptCheckConds
+func memLuaCheckConds(c *memConn, keys []string, argv []string) memReply {
+	if len(argv) < len(keys) {
+		return memError("ERR missing ARGV for KEYS")
+	}
+	mdb := c.srv.getDB(c.db)
+	for i, k := range keys {
+		var cond txCondArg
+		if err := json.Unmarshal([]byte(argv[i]), &cond); err != nil {
+			return memError("ERR invalid condition: " + err.Error())
+		}
+		h, wrongType := mdb.hash(k)
+		if wrongType {
+			return memWrongType()
+		}
+		satisfied := !cond.Exact || len(h) == len(cond.Fields)
+		for f, v := range cond.Fields {
+			if hv, ok := h[f]; !ok || hv != v {
+				satisfied = false
+			}
+		}
+		if !satisfied {
+			return memInt(int64(i + 1))
+		}
+	}
+	return memInt(0)
+}
+
+// memLuaTxEntries is the common part of count_entries and filter_entries.
+// It merges the keys of the pattern in the db with the tx entries (a JSON
+// of key -> fields, with null for deleted keys), and returns the (sorted)
+// entries, and the table name separator position in the keys.
+// Lua predicates are not supported.
+func memLuaTxEntries(c *memConn, pattern, predicate, txJson string) (
+	[]string, map[string]map[string]string, int, memReply) {
+
+	if len(predicate) != 0 {
+		return nil, nil, -1, memError(
+			"ERR lua predicates are not supported by the memory backend")
+	}
+
+	txEntries := make(map[string]map[string]string)
+	if err := json.Unmarshal([]byte(txJson), &txEntries); err != nil {
+		return nil, nil, -1, memError("ERR invalid tx entries: " + err.Error())
+	}
+
+	for _, k := range c.srv.getDB(c.db).keys(pattern) {
+		if _, ok := txEntries[k]; !ok {
+			txEntries[k] = map[string]string{}
+		}
+	}
+
+	keys := make([]string, 0, len(txEntries))
+	for k, v := range txEntries {
+		if v != nil {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	sep := -1
+	if len(keys) != 0 {
+		sep = strings.Index(keys[0], "|")
+	}
+
+	return keys, txEntries, sep, nil
+}
+
+// memLuaRow returns the tx entry's fields, or the db fields if the tx
+// entry has none.
+func memLuaRow(c *memConn, key string, txFields map[string]string) map[string]string {
+	if len(txFields) != 0 {
+		return txFields
+	}
+	h, _ := c.srv.getDB(c.db).hash(key)
+	if h == nil {
+		h = map[string]string{}
+	}
+	return h
+}
+
+// memLuaCountEntries is the CVL "count_entries", without predicates.
+// ARGV: key pattern, key names, predicate, field, tx entries.
+func memLuaCountEntries(c *memConn, keys []string, argv []string) memReply {
+	if len(argv) < 5 {
+		return memError("ERR missing ARGV[1..5]")
+	}
+	field := argv[3]
+
+	entries, txEntries, sep, errReply := memLuaTxEntries(c, argv[0], argv[2],
+		argv[4])
+	if errReply != nil {
+		return errReply
+	} else if len(entries) == 0 {
+		return memInt(0)
+	} else if sep < 0 {
+		return memNilBulk()
+	}
+
+	var cnt int64
+	for _, key := range entries {
+		if len(field) == 0 {
+			cnt++
+			continue
+		}
+		row := memLuaRow(c, key, txEntries[key])
+		if _, ok := row[field]; ok {
+			cnt++
+		} else if list, ok := row[field+"@"]; ok {
+			for _, v := range strings.Split(list, ",") {
+				if len(v) != 0 {
+					cnt++
+				}
+			}
+		} else if strings.Contains(argv[1], field) {
+			cnt++
+		}
+	}
+
+	return memInt(cnt)
+}
+
+// memLuaFilterEntries is the CVL "filter_entries", without predicates.
+// ARGV: key pattern, key names, predicate, fields, count, tx entries.
+func memLuaFilterEntries(c *memConn, keys []string, argv []string) memReply {
+	if len(argv) < 6 {
+		return memError("ERR missing ARGV[1..6]")
+	}
+	count := -1
+	if len(argv[4]) != 0 {
+		count, _ = strconv.Atoi(argv[4])
+	}
+
+	entries, txEntries, sep, errReply := memLuaTxEntries(c, argv[0], argv[2],
+		argv[5])
+	if errReply != nil {
+		return errReply
+	} else if len(entries) == 0 || sep < 0 {
+		return memNilBulk()
+	}
+
+	tbl := make(map[string]map[string]string)
+	for _, key := range entries {
+		tbl[key[sep+1:]] = memLuaRow(c, key, txEntries[key])
+		if count != -1 && len(tbl) >= count {
+			break
+		}
+	}
+
+	tableData, err := json.Marshal(map[string]interface{}{entries[0][:sep]: tbl})
+	if err != nil {
+		return memError("ERR " + err.Error())
+	}
+
+	return memBulk(string(tableData))
+}
+
+// memLuaScanEntries is luaScriptScanEntries, without predicates.
+// ARGV: cursor, key pattern, count, predicate, key names, key separator,
+// prefix length, with entries.
+func memLuaScanEntries(c *memConn, keys []string, argv []string) memReply {
+	if len(argv) < 8 {
+		return memError("ERR missing ARGV[1..8]")
+	}
+	if len(argv[3]) != 0 {
+		return memError("ERR lua predicates are not supported by the memory backend")
+	}
+
+	mdb := c.srv.getDB(c.db)
+	cursor := "0"
+	if len(argv[0]) != 0 {
+		start, pattern, count, err := scanArgs([]string{argv[0],
+			"MATCH", argv[1], "COUNT", argv[2]})
+		if err != nil {
+			return memError(err.Error())
+		}
+		var next uint64
+		if keys, next, err = c.srv.scan(mdb.keys(pattern), start, count); err != nil {
+			return memError(err.Error())
+		}
+		cursor = strconv.FormatUint(next, 10)
+	}
+
+	result := []memReply{memBulk(cursor)}
+	for _, key := range keys {
+		if argv[7] != "1" {
+			result = append(result, memBulk(key))
+			continue
+		}
+		h, wrongType := mdb.hash(key)
+		if wrongType {
+			return memWrongType()
+		}
+		if len(h) == 0 { // Deleted since SCAN or KEYS
+			continue
+		}
+		result = append(result, memBulk(key), memStrings(hashFlatten(h)))
+	}
+
+	return memArray(result...)
+}
@@ -0,0 +1,366 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/Azure/sonic-mgmt-common/cvl"
+	"github.com/go-redis/redis/v7"
+)
+
+// memScriptCase is a run of a Lua script, on the given hashes, to compare
+// the MemoryBackend Go equivalent with the redis-server.
+type memScriptCase struct {
+	name string
+	data map[string]map[string]interface{}
+	run  func(client *redis.Client) (interface{}, error)
+	norm func(reply interface{}) interface{} // Normalizes the reply order
+	cvl  string                              // Name of the CVL script run
+}
+
+// memScriptResult is the outcome of a memScriptCase; the errors are not
+// compared by the message.
+type memScriptResult struct {
+	Reply interface{}
+	Err   bool
+	Data  map[string]interface{}
+}
+
+const (
+	parityLocks = "LUA_PARITY|LOCKS"
+	parityWaits = "LUA_PARITY|WAITS"
+)
+
+func parityLockCase(name string, locks, waits map[string]interface{}, argv ...interface{}) memScriptCase {
+	return memScriptCase{
+		name: name,
+		data: map[string]map[string]interface{}{parityLocks: locks, parityWaits: waits},
+		run: func(client *redis.Client) (interface{}, error) {
+			return luaScriptLock.Run(client, []string{parityLocks, parityWaits}, argv...).Result()
+		},
+	}
+}
+
+func parityScriptCase(name string, script *redis.Script, data map[string]map[string]interface{},
+	keys []string, argv ...interface{}) memScriptCase {
+	return memScriptCase{
+		name: name,
+		data: data,
+		run: func(client *redis.Client) (interface{}, error) {
+			return script.Run(client, keys, argv...).Result()
+		},
+	}
+}
+
+func parityCvlCase(name string, script string, argv ...interface{}) memScriptCase {
+	return memScriptCase{
+		name: name,
+		data: parityTable,
+		run: func(client *redis.Client) (interface{}, error) {
+			return cvl.RunLuaOn(client, script, argv...)
+		},
+		norm: normJSONReply,
+		cvl:  script,
+	}
+}
+
+const (
+	parityJournalSeq    = "LUA_PARITY|JOURNAL_SEQ"
+	parityJournalStream = "LUA_PARITY|JOURNAL"
+)
+
+// parityJournalCase runs luaScriptJournal n times, with the maxLen; the
+// stream entry ids are not compared.
+func parityJournalCase(name string, n int, maxLen string, keys ...string) memScriptCase {
+	return memScriptCase{
+		name: name,
+		run: func(client *redis.Client) (interface{}, error) {
+			var seqs []interface{}
+			for i := 1; i <= n; i++ {
+				seq, err := client.Eval(luaScriptJournal, keys, fmt.Sprintf("rec%d", i), maxLen).Result()
+				if err != nil {
+					return nil, err
+				}
+				seqs = append(seqs, seq)
+			}
+			return seqs, nil
+		},
+	}
+}
+
+var parityTable = map[string]map[string]interface{}{
+	"LUA_PARITY|T|Ethernet0": {"mtu": "9100", "ports@": "p1,p2"},
+	"LUA_PARITY|T|Ethernet4": {"mtu": "1500"},
+	"LUA_PARITY|U|Ethernet8": {"mtu": "9100"},
+}
+
+var memScriptParityCases = []memScriptCase{
+	// luaScriptExistsKeysPatterns
+	parityScriptCase("existsKeysPatterns", luaScriptExistsKeysPatterns, parityTable,
+		[]string{"LUA_PARITY|T|*"}),
+	parityScriptCase("existsKeysPatternsNone", luaScriptExistsKeysPatterns, parityTable,
+		[]string{"LUA_PARITY|X|*"}),
+
+	// luaScriptGetTable
+	{
+		name: "getTable",
+		data: parityTable,
+		run: func(client *redis.Client) (interface{}, error) {
+			return luaScriptGetTable.Run(client, []string{"LUA_PARITY|T|*"}).Result()
+		},
+		norm: func(reply interface{}) interface{} { return normEntriesReply(reply, 0) },
+	},
+
+	// luaScriptUnlock
+	parityScriptCase("unlock", luaScriptUnlock,
+		map[string]map[string]interface{}{parityLocks: {"L": "comm:1-2", "L.lease": "5000:7:ns"}},
+		[]string{parityLocks}, "L", "comm", "1-2"),
+	parityScriptCase("unlockAny", luaScriptUnlock,
+		map[string]map[string]interface{}{parityLocks: {"L": "comm:1-2", "L.lease": "5000:7:ns"}},
+		[]string{parityLocks}, "L", "*", "*"),
+	parityScriptCase("unlockOther", luaScriptUnlock,
+		map[string]map[string]interface{}{parityLocks: {"L": "comm:1-2", "L.lease": "5000:7:ns"}},
+		[]string{parityLocks}, "L", "comm", "1-3"),
+	parityScriptCase("unlockNoId", luaScriptUnlock,
+		map[string]map[string]interface{}{parityLocks: {"L": "comm"}},
+		[]string{parityLocks}, "L", "comm", ""),
+	parityScriptCase("unlockNone", luaScriptUnlock, nil,
+		[]string{parityLocks}, "L", "*", "*"),
+
+	// luaScriptLock; now is 1000, and waiters are stale after 500.
+	parityLockCase("lock", nil, nil,
+		"L", "x:1", "2000:1:ns", "1000", "", "1000", "500", "", ""),
+	parityLockCase("lockHeld",
+		map[string]interface{}{"L": "o:1", "L.lease": "5000:7:ns"}, nil,
+		"L", "x:1", "2000:1:ns", "1000", "", "1000", "500", "", ""),
+	parityLockCase("lockNoLease",
+		map[string]interface{}{"L": "o:1"}, nil,
+		"L", "x:1", "2000:1:ns", "1000", "", "1000", "500", "", ""),
+	parityLockCase("lockExpired",
+		map[string]interface{}{"L": "o:1", "L.lease": "900:7:ns"}, nil,
+		"L", "x:1", "2000:1:ns", "1000", "", "1000", "500", "", ""),
+	parityLockCase("lockDeadHolder",
+		map[string]interface{}{"L": "o:1", "L.lease": "5000:7:ns"}, nil,
+		"L", "x:1", "2000:1:ns", "1000", "", "1000", "500", "o:1", "7:ns"),
+	parityLockCase("lockDeadHolderReplaced",
+		map[string]interface{}{"L": "o:1", "L.lease": "5000:8:ns"}, nil,
+		"L", "x:1", "2000:1:ns", "1000", "", "1000", "500", "o:1", "7:ns"),
+	parityLockCase("lockWaiterAhead", nil,
+		map[string]interface{}{"L|1:1:a": "900:990", "M|1:1:a": "900:990"},
+		"L", "x:1", "2000:1:ns", "1000", "", "1000", "500", "", ""),
+	parityLockCase("lockStaleWaiters", nil,
+		map[string]interface{}{"L|1:1:a": "100:100", "L|1:1:b": "bad"},
+		"L", "x:1", "2000:1:ns", "1000", "", "1000", "500", "", ""),
+	parityLockCase("lockWait",
+		map[string]interface{}{"L": "o:1", "L.lease": "5000:7:ns"}, nil,
+		"L", "x:1", "2000:1:ns", "1000", "L|2:2:b", "950", "500", "", ""),
+	parityLockCase("lockWaitBehind", nil,
+		map[string]interface{}{"L|1:1:a": "900:990"},
+		"L", "x:1", "2000:1:ns", "1000", "L|2:2:b", "950", "500", "", ""),
+	parityLockCase("lockWaitFirst", nil,
+		map[string]interface{}{"L|1:1:a": "960:990", "L|2:2:b": "950:990"},
+		"L", "x:1", "2000:1:ns", "1000", "L|2:2:b", "950", "500", "", ""),
+
+	// luaScriptRenewLock
+	parityScriptCase("renewLock", luaScriptRenewLock,
+		map[string]map[string]interface{}{parityLocks: {"L": "x:1", "L.lease": "2000:1:ns"}},
+		[]string{parityLocks}, "L", "x:1", "3000:1:ns", "1:ns"),
+	parityScriptCase("renewLockLost", luaScriptRenewLock,
+		map[string]map[string]interface{}{parityLocks: {"L": "o:1", "L.lease": "2000:7:ns"}},
+		[]string{parityLocks}, "L", "x:1", "3000:1:ns", "1:ns"),
+	parityScriptCase("renewLockOtherOwner", luaScriptRenewLock,
+		map[string]map[string]interface{}{parityLocks: {"L": "x:1", "L.lease": "2000:7:ns"}},
+		[]string{parityLocks}, "L", "x:1", "3000:1:ns", "1:ns"),
+	parityScriptCase("renewLockNoLease", luaScriptRenewLock,
+		map[string]map[string]interface{}{parityLocks: {"L": "x:1"}},
+		[]string{parityLocks}, "L", "x:1", "3000:1:ns", "1:ns"),
+
+	// luaScriptCheckConds
+	parityScriptCase("checkConds", luaScriptCheckConds, parityTable,
+		[]string{"LUA_PARITY|T|Ethernet0", "LUA_PARITY|T|Ethernet4", "LUA_PARITY|T|Ethernet9"},
+		`{"fields":{"mtu":"9100"},"exact":false}`,
+		`{"fields":{"mtu":"1500"},"exact":true}`,
+		`{"fields":{},"exact":true}`),
+	parityScriptCase("checkCondsField", luaScriptCheckConds, parityTable,
+		[]string{"LUA_PARITY|T|Ethernet0", "LUA_PARITY|T|Ethernet4"},
+		`{"fields":{"mtu":"9100"},"exact":false}`,
+		`{"fields":{"mtu":"9100"},"exact":false}`),
+	parityScriptCase("checkCondsExact", luaScriptCheckConds, parityTable,
+		[]string{"LUA_PARITY|T|Ethernet0"},
+		`{"fields":{"mtu":"9100"},"exact":true}`),
+	parityScriptCase("checkCondsMissing", luaScriptCheckConds, parityTable,
+		[]string{"LUA_PARITY|T|Ethernet9"},
+		`{"fields":{"mtu":"9100"},"exact":false}`),
+
+	// luaScriptScanEntries
+	parityScriptCase("scanEntriesKeys", luaScriptScanEntries, parityTable,
+		[]string{"LUA_PARITY|T|Ethernet4", "LUA_PARITY|T|Ethernet9", "LUA_PARITY|T|Ethernet0"},
+		"", "", "", "", "", "|", "13", "0"),
+	{
+		name: "scanEntriesKeysWithEntries",
+		data: parityTable,
+		run: func(client *redis.Client) (interface{}, error) {
+			return luaScriptScanEntries.Run(client, []string{"LUA_PARITY|T|Ethernet4",
+				"LUA_PARITY|T|Ethernet9", "LUA_PARITY|T|Ethernet0"},
+				"", "", "", "", "", "|", "13", "1").Result()
+		},
+		norm: func(reply interface{}) interface{} { return normEntriesReply(reply, 1) },
+	},
+	{
+		name: "scanEntriesScan",
+		data: parityTable,
+		run: func(client *redis.Client) (interface{}, error) {
+			return luaScriptScanEntries.Run(client, nil,
+				"0", "LUA_PARITY|T|*", "1000", "", "", "|", "13", "1").Result()
+		},
+		norm: func(reply interface{}) interface{} { return normEntriesReply(reply, 1) },
+	},
+
+	// luaScriptJournal
+	parityJournalCase("journal", 3, "0", parityJournalSeq, parityJournalStream),
+	parityJournalCase("journalMaxLen", 3, "2", parityJournalSeq, parityJournalStream),
+	parityJournalCase("journalSeqOnly", 2, "0", parityJournalSeq),
+
+	// CVL count_entries and filter_entries
+	parityCvlCase("countEntries", "count_entries",
+		"LUA_PARITY|T|*", "name", "", "", "{}"),
+	parityCvlCase("countEntriesTx", "count_entries",
+		"LUA_PARITY|T|*", "name", "", "", `{"LUA_PARITY|T|Ethernet4":null,"LUA_PARITY|T|Ethernet8":{"mtu":"1"}}`),
+	parityCvlCase("countEntriesField", "count_entries",
+		"LUA_PARITY|T|*", "name", "", "ports", "{}"),
+	parityCvlCase("countEntriesKeyField", "count_entries",
+		"LUA_PARITY|T|*", "name", "", "name", "{}"),
+	parityCvlCase("countEntriesNone", "count_entries",
+		"LUA_PARITY|X|*", "name", "", "", "{}"),
+	parityCvlCase("filterEntries", "filter_entries",
+		"LUA_PARITY|T|*", "name", "", "", "", "{}"),
+	parityCvlCase("filterEntriesTx", "filter_entries",
+		"LUA_PARITY|T|*", "name", "", "", "", `{"LUA_PARITY|T|Ethernet4":null,"LUA_PARITY|T|Ethernet8":{"mtu":"1"}}`),
+	parityCvlCase("filterEntriesNone", "filter_entries",
+		"LUA_PARITY|X|*", "name", "", "", "", "{}"),
+}
+
+// normEntriesReply returns the { [cursor,] key1, hash1, ... } reply of the
+// scripts, from the first key, as a map of key to hash.
+func normEntriesReply(reply interface{}, first int) interface{} {
+	r, ok := reply.([]interface{})
+	if !ok || len(r) < first {
+		return reply
+	}
+	entries := make(map[string]interface{})
+	for i := first; i < len(r); i++ {
+		key, _ := r[i].(string)
+		if i++; i < len(r) {
+			entries[key] = normHashReply(r[i])
+		}
+	}
+	return entries
+}
+
+// normHashReply returns the field value list of HGETALL as a map
+func normHashReply(reply interface{}) interface{} {
+	r, ok := reply.([]interface{})
+	if !ok {
+		return reply
+	}
+	h := make(map[string]interface{})
+	for i := 0; i+1 < len(r); i += 2 {
+		f, _ := r[i].(string)
+		h[f] = r[i+1]
+	}
+	return h
+}
+
+// normJSONReply decodes a JSON string reply
+func normJSONReply(reply interface{}) interface{} {
+	var v interface{}
+	if s, ok := reply.(string); !ok || json.Unmarshal([]byte(s), &v) != nil {
+		return reply
+	}
+	return v
+}
+
+// runMemScriptCase runs the case, on the current backend
+func runMemScriptCase(t *testing.T, tc memScriptCase) memScriptResult {
+	client := openTestClient(t, ConfigDB)
+	if keys, _ := client.Keys("LUA_PARITY|*").Result(); len(keys) != 0 {
+		client.Del(keys...)
+	}
+	for k, h := range tc.data {
+		if len(h) != 0 {
+			client.HMSet(k, h)
+		}
+	}
+
+	var res memScriptResult
+	reply, err := tc.run(client)
+	if err != nil && err != redis.Nil {
+		res.Err = true
+	} else if res.Reply = reply; tc.norm != nil {
+		res.Reply = tc.norm(reply)
+	}
+
+	keys, _ := client.Keys("LUA_PARITY|*").Result()
+	sort.Strings(keys)
+	res.Data = make(map[string]interface{}, len(keys))
+	for _, k := range keys {
+		switch typ, _ := client.Type(k).Result(); typ {
+		case "hash":
+			res.Data[k], _ = client.HGetAll(k).Result()
+		case "string":
+			res.Data[k], _ = client.Get(k).Result()
+		case "stream":
+			msgs, _ := client.XRange(k, "-", "+").Result()
+			values := make([]map[string]interface{}, 0, len(msgs))
+			for _, m := range msgs {
+				values = append(values, m.Values)
+			}
+			res.Data[k] = values
+		default:
+			res.Data[k] = typ
+		}
+	}
+	return res
+}
+
+// TestMemoryBackendScriptParity runs the Lua scripts on the MemoryBackend
+// and the redis-server (if reachable), and compares the replies and the
+// resulting data.
+func TestMemoryBackendScriptParity(t *testing.T) {
+	for _, tc := range memScriptParityCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if len(tc.cvl) != 0 && cvl.LuaScriptHash(tc.cvl) == "" {
+				t.Skip("CVL is not initialized")
+			}
+			var results []memScriptResult
+			onTestBackends(t, func(t *testing.T) {
+				results = append(results, runMemScriptCase(t, tc))
+			})
+			if len(results) == 2 && !reflect.DeepEqual(results[0], results[1]) {
+				t.Errorf("memory: %+v\nredis:  %+v", results[0], results[1])
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+//  Internal Types                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+// memStream is a redis stream. Only the XADD (with auto generated IDs),
+// XRANGE, and XLEN commands are supported.
+type memStream struct {
+	entries []memStreamEntry
+	last    memStreamID // Last generated ID
+}
+
+type memStreamEntry struct {
+	id     memStreamID
+	fields []string // field, value pairs
+}
+
+type memStreamID struct {
+	ms  int64
+	seq int64
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Internal Functions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+func (id memStreamID) String() string {
+	return strconv.FormatInt(id.ms, 10) + "-" + strconv.FormatInt(id.seq, 10)
+}
+
+func (id memStreamID) less(o memStreamID) bool {
+	return id.ms < o.ms || (id.ms == o.ms && id.seq < o.seq)
+}
+
+// parseMemStreamID parses the XRANGE start/end ID. A missing sequence is
+// defSeq. "-" and "+" are the min and the max IDs.
+func parseMemStreamID(s string, defSeq int64) (memStreamID, bool) {
+	switch s {
+	case "-":
+		return memStreamID{0, 0}, true
+	case "+":
+		return memStreamID{1<<63 - 1, 1<<63 - 1}, true
+	}
+	var id memStreamID
+	var err error
+	parts := strings.SplitN(s, "-", 2)
+	if id.ms, err = strconv.ParseInt(parts[0], 10, 64); err != nil {
+		return id, false
+	}
+	id.seq = defSeq
+	if len(parts) == 2 {
+		if id.seq, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
+			return id, false
+		}
+	}
+	return id, true
+}
+
+// xadd appends the entry to the stream, and trims it to maxLen (if > 0).
+// Returns the ID of the entry. Must be called with the server lock held.
+func (c *memConn) xadd(key string, maxLen int, fvs []string) (memStreamID, bool) {
+	mdb := c.srv.getDB(c.db)
+	if _, ok := mdb.strs[key]; ok {
+		return memStreamID{}, false
+	}
+	if _, ok := mdb.hashes[key]; ok {
+		return memStreamID{}, false
+	}
+	st := mdb.streams[key]
+	if st == nil {
+		st = &memStream{}
+		mdb.streams[key] = st
+	}
+
+	id := memStreamID{ms: time.Now().UnixNano() / int64(time.Millisecond)}
+	if id.ms <= st.last.ms {
+		id = memStreamID{ms: st.last.ms, seq: st.last.seq + 1}
+	}
+	st.last = id
+	st.entries = append(st.entries,
+		memStreamEntry{id: id, fields: append([]string(nil), fvs...)})
+	if maxLen > 0 && len(st.entries) > maxLen {
+		st.entries = append([]memStreamEntry(nil),
+			st.entries[len(st.entries)-maxLen:]...)
+	}
+
+	mdb.touch(c.srv, key)
+	c.srv.notify(c.db, 't', "xadd", key)
+	return id, true
+}
+
+// memXAdd is XADD key [MAXLEN [=|~] count] * field value [field value ...]
+func memXAdd(c *memConn, args []string) memReply {
+	i, maxLen := 2, 0
+	if strings.ToUpper(args[i]) == "MAXLEN" {
+		i++
+		if args[i] == "=" || args[i] == "~" {
+			i++
+		}
+		if i >= len(args) {
+			return memError(errMemSyntax.Error())
+		}
+		n, err := strconv.Atoi(args[i])
+		if err != nil || n < 0 {
+			return memError("ERR The MAXLEN argument must be >= 0.")
+		}
+		maxLen = n
+		i++
+	}
+	if i >= len(args) || args[i] != "*" {
+		return memError("ERR Only auto generated stream IDs are supported")
+	}
+	fvs := args[i+1:]
+	if len(fvs) == 0 || len(fvs)%2 != 0 {
+		return memArgsError("XADD")
+	}
+	id, ok := c.xadd(args[1], maxLen, fvs)
+	if !ok {
+		return memWrongType()
+	}
+	return memBulk(id.String())
+}
+
+// memXRange is XRANGE key start end [COUNT count]
+func memXRange(c *memConn, args []string) memReply {
+	mdb := c.srv.getDB(c.db)
+	if _, ok := mdb.strs[args[1]]; ok {
+		return memWrongType()
+	}
+	if _, ok := mdb.hashes[args[1]]; ok {
+		return memWrongType()
+	}
+	start, ok1 := parseMemStreamID(args[2], 0)
+	end, ok2 := parseMemStreamID(args[3], 1<<63-1)
+	if !ok1 || !ok2 {
+		return memError("ERR Invalid stream ID specified as stream command argument")
+	}
+	count := -1
+	if len(args) > 4 {
+		if len(args) != 6 || strings.ToUpper(args[4]) != "COUNT" {
+			return memError(errMemSyntax.Error())
+		}
+		n, err := strconv.Atoi(args[5])
+		if err != nil {
+			return memError("ERR value is not an integer or out of range")
+		}
+		count = n
+	}
+
+	var replies []memReply
+	if st := mdb.streams[args[1]]; st != nil {
+		for _, e := range st.entries {
+			if count >= 0 && len(replies) >= count {
+				break
+			}
+			if e.id.less(start) || end.less(e.id) {
+				continue
+			}
+			replies = append(replies,
+				memArray(memBulk(e.id.String()), memStrings(e.fields)))
+		}
+	}
+	return memArray(replies...)
+}
+
+func memXLen(c *memConn, args []string) memReply {
+	mdb := c.srv.getDB(c.db)
+	if _, ok := mdb.strs[args[1]]; ok {
+		return memWrongType()
+	}
+	if _, ok := mdb.hashes[args[1]]; ok {
+		return memWrongType()
+	}
+	if st := mdb.streams[args[1]]; st != nil {
+		return memInt(int64(len(st.entries)))
+	}
+	return memInt(0)
+}
@@ -0,0 +1,256 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Azure/sonic-mgmt-common/translib/tlerr"
+)
+
+func TestMemoryBackendEntry(t *testing.T) {
+	useMemoryBackend(t)
+	d := openTestDB(t, ConfigDB, false)
+	ts := &TableSpec{Name: "MEM_TEST"}
+	key := *NewKey("k1")
+
+	if err := d.SetEntry(ts, key, Value{Field: map[string]string{"a": "1", "b": "2"}}); err != nil {
+		t.Fatalf("SetEntry() fails e = %v", err)
+	}
+	if err := d.DeleteEntryFields(ts, key, Value{Field: map[string]string{"a": ""}}); err != nil {
+		t.Fatalf("DeleteEntryFields() fails e = %v", err)
+	}
+	if v, err := d.GetEntry(ts, key); err != nil || !reflect.DeepEqual(v.Field, map[string]string{"b": "2"}) {
+		t.Fatalf("GetEntry() = %v, %v", v, err)
+	}
+
+	// Other databases are separate
+	if v, err := openTestDB(t, StateDB, true).GetEntry(ts, key); err == nil {
+		t.Fatalf("STATE_DB GetEntry() = %v", v)
+	}
+
+	if err := d.DeleteEntry(ts, key); err != nil {
+		t.Fatalf("DeleteEntry() fails e = %v", err)
+	}
+	if _, err := d.GetEntry(ts, key); !isNotExist(err) {
+		t.Fatalf("GetEntry() after delete; e = %v", err)
+	}
+}
+
+func isNotExist(err error) bool {
+	_, ok := err.(tlerr.TranslibRedisClientEntryNotExist)
+	return ok
+}
+
+func TestMemoryBackendTransaction(t *testing.T) {
+	useMemoryBackend(t)
+	d1 := openTestDB(t, ConfigDB, false)
+	d2 := openTestDB(t, ConfigDB, true)
+	c2 := openTestClient(t, ConfigDB) // other writer
+	ts := &TableSpec{Name: "MEM_TEST"}
+	key := *NewKey("k1")
+	d1.SetEntry(ts, key, Value{Field: map[string]string{"a": "1"}})
+
+	// Tx commits if the watched key is not modified by others
+	watch := []WatchKeys{{Ts: ts, Key: &key}}
+	if err := d1.StartTx(watch, nil); err != nil {
+		t.Fatalf("StartTx() fails e = %v", err)
+	}
+	d1.ModEntry(ts, key, Value{Field: map[string]string{"b": "2"}})
+	if err := d1.CommitTx(); err != nil {
+		t.Fatalf("CommitTx() fails e = %v", err)
+	}
+
+	// Tx fails if the watched key is modified by others
+	if err := d1.StartTx(watch, nil); err != nil {
+		t.Fatalf("StartTx() fails e = %v", err)
+	}
+	d1.ModEntry(ts, key, Value{Field: map[string]string{"c": "3"}})
+	c2.HSet("MEM_TEST|k1", "d", "4")
+	if err := d1.CommitTx(); !reflect.DeepEqual(err, tlerr.TranslibTransactionFail{}) {
+		t.Fatalf("CommitTx() with conflict; e = %v", err)
+	}
+
+	v, _ := d2.GetEntry(ts, key)
+	exp := map[string]string{"a": "1", "b": "2", "d": "4"}
+	if !reflect.DeepEqual(v.Field, exp) {
+		t.Fatalf("GetEntry() = %v; expected %v", v.Field, exp)
+	}
+}
+
+func TestMemoryBackendScripts(t *testing.T) {
+	useMemoryBackend(t)
+	d := openTestDB(t, ConfigDB, false)
+	ts := &TableSpec{Name: "MEM_TEST"}
+	for i := 0; i < 5; i++ {
+		d.SetEntry(ts, *NewKey("k" + strconv.Itoa(i)), Value{Field: map[string]string{"i": strconv.Itoa(i)}})
+	}
+
+	rd := openTestDB(t, ConfigDB, true)
+	if ok, err := rd.ExistKeysPattern(ts, *NewKey("k*")); err != nil || !ok {
+		t.Errorf("ExistKeysPattern(k*) = %v, %v", ok, err)
+	}
+	if ok, err := rd.ExistKeysPattern(ts, *NewKey("x*")); err != nil || ok {
+		t.Errorf("ExistKeysPattern(x*) = %v, %v", ok, err)
+	}
+
+	tbl, err := rd.GetTablePattern(ts, *NewKey("*"))
+	if err != nil {
+		t.Fatalf("GetTablePattern() fails e = %v", err)
+	}
+	if keys, _ := tbl.GetKeys(); len(keys) != 5 {
+		t.Errorf("GetTablePattern() keys = %v", keys)
+	}
+	if v, _ := tbl.GetEntry(*NewKey("k3")); v.Get("i") != "3" {
+		t.Errorf("GetTablePattern() k3 = %v", v)
+	}
+
+	// Scripts which do not have a Go equivalent
+	client := openTestClient(t, ConfigDB)
+	if _, err := client.Eval("return 1", nil).Result(); err == nil {
+		t.Errorf("Eval() of unknown script did not fail")
+	}
+}
+
+func TestMemoryBackendScan(t *testing.T) {
+	useMemoryBackend(t)
+	client := openTestClient(t, ConfigDB)
+	var exp []string
+	for i := 0; i < 25; i++ {
+		k := "MEM_TEST|k" + strconv.Itoa(100+i)
+		client.HSet(k, "f", "v")
+		exp = append(exp, k)
+	}
+
+	// Keys present for the whole scan are returned once, even though
+	// others are deleted and added while scanning.
+	var keys []string
+	var cursor uint64
+	for i := 0; ; i++ {
+		page, next, err := client.Scan(cursor, "MEM_TEST|*", 10).Result()
+		if err != nil {
+			t.Fatalf("Scan() fails e = %v", err)
+		}
+		keys = append(keys, page...)
+		if cursor = next; cursor == 0 {
+			break
+		}
+		if i == 0 {
+			client.Del(page[0], exp[20])
+			client.HSet("MEM_TEST|k000", "f", "v")
+			exp = exp[:20]
+		}
+	}
+
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, exp) {
+		t.Errorf("Scan() = %v; expected %v", keys, exp)
+	}
+}
+
+func TestMemoryBackendScanCursors(t *testing.T) {
+	mb := useMemoryBackend(t)
+	client := openTestClient(t, ConfigDB)
+	client.HMSet("MEM_TEST|k1", map[string]interface{}{"f1": "v", "f2": "v"})
+
+	// Abandoned scans are forgotten after memMaxCursors more scans
+	_, first, err := client.HScan("MEM_TEST|k1", 0, "*", 1).Result()
+	if err != nil || first == 0 {
+		t.Fatalf("HScan() = %v, %v; expected a cursor", first, err)
+	}
+	for i := 0; i < memMaxCursors; i++ {
+		client.HScan("MEM_TEST|k1", 0, "*", 1)
+	}
+
+	var n int
+	for _, srv := range mb.srvs {
+		srv.mu.Lock()
+		n += len(srv.cursors)
+		srv.mu.Unlock()
+	}
+	if n != memMaxCursors {
+		t.Errorf("Found %d cursors; expected %d", n, memMaxCursors)
+	}
+	if _, _, err := client.HScan("MEM_TEST|k1", first, "*", 1).Result(); err == nil {
+		t.Errorf("HScan() with an expired cursor did not fail")
+	}
+}
+
+func TestMemoryBackendSubscribe(t *testing.T) {
+	useMemoryBackend(t)
+	d := openTestDB(t, ConfigDB, false)
+	ts := &TableSpec{Name: "MEM_TEST"}
+	key := *NewKey("k1")
+
+	events := make(chan SEvent, 10)
+	skeys := []*SKey{{Ts: ts, Key: &key,
+		SEMap: map[SEvent]bool{SEventHSet: true, SEventHDel: true, SEventDel: true}}}
+	s, err := SubscribeDB(Options{
+		DBNo:               ConfigDB,
+		TableNameSeparator: "|",
+		KeySeparator:       "|",
+		DisableCVLCheck:    true,
+	}, skeys, func(s *DB, skey *SKey, key *Key, event SEvent) error {
+		events <- event
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("SubscribeDB() fails e = %v", err)
+	}
+	defer s.UnsubscribeDB()
+
+	d.SetEntry(ts, key, Value{Field: map[string]string{"a": "1"}})
+	d.DeleteEntryFields(ts, key, Value{Field: map[string]string{"a": ""}})
+
+	for _, exp := range []SEvent{SEventHSet, SEventHDel, SEventDel} {
+		select {
+		case e := <-events:
+			if e != exp {
+				t.Fatalf("SubscribeDB() event = %v; expected %v", e, exp)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("SubscribeDB() event %v not received", exp)
+		}
+	}
+}
+
+// TestMemoryBackendPredicate checks that the ScanCursor predicates fail,
+// rather than being ignored, on the memory backend. They are tested on the
+// redis-server, by TestScanCursorPredicate*.
+func TestMemoryBackendPredicate(t *testing.T) {
+	useMemoryBackend(t)
+	d := openTestDB(t, ConfigDB, false)
+	ts := &TableSpec{Name: "SC_PRED_TEST"}
+	d.SetEntry(ts, *NewKey("Vlan1", "Ethernet1"), Value{Field: map[string]string{"tagging_mode": "tagged"}})
+
+	sc, err := d.NewScanCursor(ts, *NewKey("*", "*"), &ScanCursorOpts{
+		Predicate: "return h['tagging_mode'] == 'tagged'", KeyNames: []string{"vlan", "port"}})
+	if err != nil {
+		t.Fatalf("NewScanCursor() fails e = %v", err)
+	}
+	defer sc.DeleteScanCursor()
+	if k, _, err := sc.GetNextKeys(nil); err == nil {
+		t.Errorf("GetNextKeys() with predicate = %v; expected to fail", k)
+	}
+}
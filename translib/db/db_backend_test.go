@@ -0,0 +1,153 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/sonic-mgmt-common/cvl"
+	"github.com/go-redis/redis/v7"
+)
+
+// Test fixture for the DB tests which select the backend. The tests of the
+// Lua scripts run on both backends (onTestBackends), since the
+// MemoryBackend has Go equivalents of the scripts.
+
+// useMemoryBackend switches to a new MemoryBackend for the duration of
+// the test.
+func useMemoryBackend(t *testing.T) *MemoryBackend {
+	prev := GetBackend()
+	mb := NewMemoryBackend()
+	SetBackend(mb)
+	t.Cleanup(func() { SetBackend(prev) })
+	return mb
+}
+
+// useRedisServer switches to the redis-server for the duration of the
+// test; the test is skipped if the redis-server is not reachable. The keys
+// of CONFIG_DB and STATE_DB changed by the test are restored at the end.
+func useRedisServer(t *testing.T) {
+	prev := GetBackend()
+	SetBackend(&RedisBackend{})
+	t.Cleanup(func() { SetBackend(prev) })
+
+	for _, dbNo := range []DBNum{ConfigDB, StateDB} {
+		client := openTestClient(t, dbNo)
+		dump, err := dumpRedisDB(client)
+		if err != nil {
+			t.Skipf("redis-server is not reachable; e = %v", err)
+		}
+		t.Cleanup(func() { restoreRedisDB(t, client, dump) })
+	}
+}
+
+// onTestBackends runs f as the "memory" and "redis" subtests, on the
+// MemoryBackend and on the redis-server (if reachable).
+func onTestBackends(t *testing.T, f func(t *testing.T)) {
+	t.Run("memory", func(t *testing.T) {
+		useMemoryBackend(t)
+		f(t)
+	})
+	t.Run("redis", func(t *testing.T) {
+		useRedisServer(t)
+		f(t)
+	})
+}
+
+// openTestDB opens a DB, with "|" separators and without CVL, on the
+// current backend. It is closed at the end of the test.
+func openTestDB(t *testing.T, dbNo DBNum, writeDisabled bool) *DB {
+	d, err := NewDB(Options{
+		DBNo:               dbNo,
+		TableNameSeparator: "|",
+		KeySeparator:       "|",
+		IsWriteDisabled:    writeDisabled,
+		DisableCVLCheck:    true,
+	})
+	if err != nil {
+		t.Fatalf("NewDB() fails e = %v", err)
+	}
+	t.Cleanup(func() { d.DeleteDB() })
+	return d
+}
+
+// openTestClient opens a go-redis client of the DB on the current backend.
+// It is closed at the end of the test.
+func openTestClient(t *testing.T, dbNo DBNum) *redis.Client {
+	client := redis.NewClient(adjustRedisOpts(&Options{DBNo: dbNo}))
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// dumpRedisDB returns the DUMP of each key of the DB
+func dumpRedisDB(client *redis.Client) (map[string]string, error) {
+	keys, err := client.Keys("*").Result()
+	if err != nil {
+		return nil, err
+	}
+	dump := make(map[string]string, len(keys))
+	for _, k := range keys {
+		if v, err := client.Dump(k).Result(); err == nil {
+			dump[k] = v
+		}
+	}
+	return dump, nil
+}
+
+// restoreRedisDB deletes the keys added since the dump, and restores the
+// keys changed or deleted since.
+func restoreRedisDB(t *testing.T, client *redis.Client, dump map[string]string) {
+	cur, err := dumpRedisDB(client)
+	if err != nil {
+		t.Errorf("Cannot restore DB %d; e = %v", client.Options().DB, err)
+		return
+	}
+	for k := range cur {
+		if _, ok := dump[k]; !ok {
+			client.Del(k)
+		}
+	}
+	for k, v := range dump {
+		if cur[k] != v {
+			client.RestoreReplace(k, 0, v)
+		}
+	}
+}
+
+func TestSetBackendCVLOptions(t *testing.T) {
+	prevOpts := cvl.RedisOptions()
+	t.Cleanup(func() { cvl.ReconfigureRedisOptions(prevOpts) })
+	opts := prevOpts
+	opts.ReadTimeout = 7 * time.Second
+	cvl.ReconfigureRedisOptions(opts)
+
+	// Only the Dialer of the CVL options is changed by SetBackend
+	useMemoryBackend(t)
+	if o := cvl.RedisOptions(); o.Dialer == nil || o.ReadTimeout != opts.ReadTimeout {
+		t.Errorf("CVL options after SetBackend(memory): Dialer = %p, ReadTimeout = %v",
+			o.Dialer, o.ReadTimeout)
+	}
+	SetBackend(nil)
+	if o := cvl.RedisOptions(); o.Dialer != nil || o.ReadTimeout != opts.ReadTimeout {
+		t.Errorf("CVL options after SetBackend(nil): Dialer = %p, ReadTimeout = %v",
+			o.Dialer, o.ReadTimeout)
+	}
+}
@@ -42,13 +42,9 @@ type dbCache struct {
 	Maps   map[string]MAP
 }
 
-type DBGlobalCache struct {
-	Databases [MaxDB]dbCache
-}
-
 type DBCacheConfig struct {
 	PerConnection bool            // Enable per DB conn cache
-	Global        bool            // Enable global cache (TBD)
+	Global        bool            // Enable global cache (CONFIG_DB)
 	CacheTables   map[string]bool // Only cache these tables.
 	// Empty == Cache all tables
 	NoCacheTables map[string]bool // Do not cache these tables.
@@ -67,8 +63,11 @@ func ReconfigureCache() error {
 	return dbCacheConfig.reconfigure()
 }
 
+// ClearCache drops the global cache, and its keyspace notification
+// subscriptions. They are restored on next use.
 func ClearCache() error {
-	return nil // TBD for Global Cache
+	dbGlobalCache.clearAll()
+	return nil
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -89,6 +88,17 @@ func init() {
 	dbCacheConfig = &DBCacheConfig{}
 	dbCacheConfig.handleReconfigureSignal()
 	dbCacheConfig.reconfigure()
+
+	RegisterRuntimeConfig(RuntimeConfigComponent[DBCacheConfig]{
+		Name: "db_cache",
+		Parse: func(fields map[string]string) DBCacheConfig {
+			var config DBCacheConfig
+			config.parseFields(fields)
+			return config
+		},
+		OnChange: func(_, config DBCacheConfig) { setDBCacheConfig(config) },
+		Current:  getDBCacheConfig,
+	})
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -143,20 +153,25 @@ func (config *DBCacheConfig) reconfigure() error {
 	if doReconfigure {
 		var readDBCacheConfig DBCacheConfig
 		readDBCacheConfig.readFromDB()
+		setDBCacheConfig(readDBCacheConfig)
+	}
+	return nil
+}
 
-		mutexCacheConfig.Lock()
-		configChanged := !reflect.DeepEqual(*dbCacheConfig, readDBCacheConfig)
-		mutexCacheConfig.Unlock()
-
-		if configChanged {
-			ClearCache()
-		}
+// setDBCacheConfig makes the config effective. The global cache is cleared,
+// if the config has changed.
+func setDBCacheConfig(config DBCacheConfig) {
+	mutexCacheConfig.Lock()
+	configChanged := !reflect.DeepEqual(*dbCacheConfig, config)
+	mutexCacheConfig.Unlock()
 
-		mutexCacheConfig.Lock()
-		dbCacheConfig = &readDBCacheConfig
-		mutexCacheConfig.Unlock()
+	if configChanged {
+		ClearCache()
 	}
-	return nil
+
+	mutexCacheConfig.Lock()
+	dbCacheConfig = &config
+	mutexCacheConfig.Unlock()
 }
 
 func (config *DBCacheConfig) handleReconfigureSignal() error {
@@ -171,7 +186,7 @@ func (config *DBCacheConfig) handleReconfigureSignal() error {
 ////////////////////////////////////////////////////////////////////////////////
 
 func (config *DBCacheConfig) readFromDB() error {
-	fields, e := readRedis("TRANSLIB_DB|default")
+	fields, e := readRuntimeConfig()
 	if e != nil {
 
 		config.PerConnection = defaultDBCacheConfig.PerConnection
@@ -201,44 +216,49 @@ func (config *DBCacheConfig) readFromDB() error {
 		}
 
 	} else {
-		for k, v := range fields {
-			switch {
-			case k == "per_connection_cache" && v == "True":
-				config.PerConnection = true
-			case k == "per_connection_cache" && v == "False":
-				config.PerConnection = false
-			case k == "global_cache" && v == "True":
-				config.Global = true
-			case k == "global_cache" && v == "False":
-				config.Global = false
-			case k == "@tables_cache":
-				l := strings.Split(v, ",")
-				config.CacheTables = make(map[string]bool, len(l))
-				for _, t := range l {
-					config.CacheTables[t] = true
-				}
-			case k == "@no_tables_cache":
-				l := strings.Split(v, ",")
-				config.NoCacheTables = make(map[string]bool, len(l))
-				for _, t := range l {
-					config.NoCacheTables[t] = true
-				}
-			case k == "@maps_cache":
-				l := strings.Split(v, ",")
-				config.CacheMaps = make(map[string]bool, len(l))
-				for _, t := range l {
-					config.CacheMaps[t] = true
-				}
-			case k == "@no_maps_cache":
-				l := strings.Split(v, ",")
-				config.NoCacheMaps = make(map[string]bool, len(l))
-				for _, t := range l {
-					config.NoCacheMaps[t] = true
-				}
+		config.parseFields(fields)
+	}
+	return e
+}
+
+// parseFields sets the config from the TRANSLIB_DB|default fields
+func (config *DBCacheConfig) parseFields(fields map[string]string) {
+	for k, v := range fields {
+		switch {
+		case k == "per_connection_cache" && v == "True":
+			config.PerConnection = true
+		case k == "per_connection_cache" && v == "False":
+			config.PerConnection = false
+		case k == "global_cache" && v == "True":
+			config.Global = true
+		case k == "global_cache" && v == "False":
+			config.Global = false
+		case k == "@tables_cache":
+			l := strings.Split(v, ",")
+			config.CacheTables = make(map[string]bool, len(l))
+			for _, t := range l {
+				config.CacheTables[t] = true
+			}
+		case k == "@no_tables_cache":
+			l := strings.Split(v, ",")
+			config.NoCacheTables = make(map[string]bool, len(l))
+			for _, t := range l {
+				config.NoCacheTables[t] = true
+			}
+		case k == "@maps_cache":
+			l := strings.Split(v, ",")
+			config.CacheMaps = make(map[string]bool, len(l))
+			for _, t := range l {
+				config.CacheMaps[t] = true
+			}
+		case k == "@no_maps_cache":
+			l := strings.Split(v, ",")
+			config.NoCacheMaps = make(map[string]bool, len(l))
+			for _, t := range l {
+				config.NoCacheMaps[t] = true
 			}
 		}
 	}
-	return e
 }
 
 func (config *DBCacheConfig) isCacheTable(name string) bool {
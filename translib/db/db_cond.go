@@ -0,0 +1,192 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/Azure/sonic-mgmt-common/translib/tlerr"
+	"github.com/go-redis/redis/v7"
+	"github.com/golang/glog"
+)
+
+// EntryCond is the condition for the conditional write APIs -- SetEntryIf,
+// ModEntryIf and DeleteEntryIf. It is satisfied if the DB entry has all the
+// fields of Value, with the same values. If Exact is true, the entry should
+// not have any other fields; i.e, an empty Value with Exact=true checks that
+// the entry does not exist. Field values are compared as is, hence the order
+// of leaf-list (field@) items is significant.
+type EntryCond struct {
+	Value Value
+	Exact bool
+}
+
+// SetEntryIf is similar to SetEntry, but the write is performed only if the
+// DB entry satisfies the condition. The condition is evaluated atomically at
+// CommitTx() against the DB contents (excluding the changes made by this
+// transaction). The whole transaction fails with tlerr.TranslibDBCondFail
+// error if any of the conditions are not satisfied. Hence the conditional
+// writes are supported only within a transaction.
+func (d *DB) SetEntryIf(ts *TableSpec, key Key, value Value, cond EntryCond) error {
+	if e := d.checkTxForCond(); e != nil {
+		return e
+	}
+	e := d.SetEntry(ts, key, value)
+	if e == nil {
+		d.addTxCond(ts, key, cond)
+	}
+	return e
+}
+
+// ModEntryIf is similar to ModEntry, but the write is performed only if the
+// DB entry satisfies the condition. Refer SetEntryIf for details.
+func (d *DB) ModEntryIf(ts *TableSpec, key Key, value Value, cond EntryCond) error {
+	if e := d.checkTxForCond(); e != nil {
+		return e
+	}
+	e := d.ModEntry(ts, key, value)
+	if e == nil {
+		d.addTxCond(ts, key, cond)
+	}
+	return e
+}
+
+// DeleteEntryIf is similar to DeleteEntry, but the entry is deleted only if
+// it satisfies the condition. Refer SetEntryIf for details.
+func (d *DB) DeleteEntryIf(ts *TableSpec, key Key, cond EntryCond) error {
+	if e := d.checkTxForCond(); e != nil {
+		return e
+	}
+	e := d.DeleteEntry(ts, key)
+	if e == nil {
+		d.addTxCond(ts, key, cond)
+	}
+	return e
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Internal Types                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+type _txCond struct {
+	redisKey string
+	cond     EntryCond
+}
+
+// txCondArg is the JSON encoded condition passed to luaScriptCheckConds
+type txCondArg struct {
+	Fields map[string]string `json:"fields"`
+	Exact  bool              `json:"exact"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Internal Functions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+var luaScriptCheckConds *redis.Script
+
+func init() {
+	// Register the Lua Script. ARGV[i] is the JSON encoded condition for
+	// KEYS[i]. Returns the index of the first key which does not satisfy
+	// its condition; 0 if all are satisfied.
+	luaScriptCheckConds = redis.NewScript(`
+		for i, key in ipairs(KEYS) do
+			local cond = cjson.decode(ARGV[i])
+			local n = 0
+			for f, v in pairs(cond.fields) do
+				if redis.call('HGET', key, f) ~= v then
+					return i
+				end
+				n = n + 1
+			end
+			if cond.exact and redis.call('HLEN', key) ~= n then
+				return i
+			end
+		end
+		return 0
+	`)
+}
+
+func (d *DB) checkTxForCond() error {
+	if !d.IsOpen() {
+		return ConnectionClosed
+	}
+	if d.txState == txStateNone {
+		glog.Error("checkTxForCond: Conditional write without a transaction")
+		return errors.New("Conditional write is supported only within a transaction")
+	}
+	return nil
+}
+
+func (d *DB) addTxCond(ts *TableSpec, key Key, cond EntryCond) {
+	d.txConds = append(d.txConds, _txCond{
+		redisKey: d.key2redis(ts, key),
+		cond:     EntryCond{Value: cond.Value.Copy(), Exact: cond.Exact},
+	})
+}
+
+// checkTxConds evaluates the conditions of the conditional writes in this
+// transaction. Should be called by commitTx() before MULTI. The keys are
+// WATCHed before evaluating the conditions, so that the EXEC fails if they
+// are modified after the check.
+func (d *DB) checkTxConds() error {
+	if len(d.txConds) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(d.txConds))
+	argv := make([]interface{}, len(d.txConds))
+	watch := make([]interface{}, 0, len(d.txConds)+1)
+	watch = append(watch, "WATCH")
+	for i, tc := range d.txConds {
+		arg := txCondArg{Fields: tc.cond.Value.Field, Exact: tc.cond.Exact}
+		if arg.Fields == nil {
+			arg.Fields = map[string]string{}
+		}
+		data, _ := json.Marshal(&arg)
+		keys[i] = tc.redisKey
+		argv[i] = string(data)
+		watch = append(watch, tc.redisKey)
+	}
+
+	glog.Info("CommitTx: Do: ", watch)
+	if _, e := d.client.Do(watch...).Result(); e != nil {
+		glog.Warning("CommitTx: Do: WATCH e: ", e.Error())
+		return e
+	}
+
+	glog.Info("CommitTx: RunScript: CheckConds: ", keys)
+	index, e := d.runScript(luaScriptCheckConds, keys, argv...).Int()
+	if e == nil && index != 0 {
+		if index < 0 || index > len(keys) {
+			e = tlerr.TranslibDBScriptFail{Description: "CheckConds: unexpected result"}
+		} else {
+			glog.Infof("CommitTx: Condition not satisfied for %s", keys[index-1])
+			e = tlerr.TranslibDBCondFail{Entry: keys[index-1]}
+		}
+	}
+
+	if e != nil {
+		glog.Warning("CommitTx: CheckConds e: ", e.Error())
+		d.client.Do("UNWATCH")
+	}
+	return e
+}
@@ -0,0 +1,93 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Azure/sonic-mgmt-common/translib/tlerr"
+)
+
+func TestConditionalWrites(t *testing.T) {
+	onTestBackends(t, testConditionalWrites)
+}
+
+func testConditionalWrites(t *testing.T) {
+	d := openTestDB(t, ConfigDB, false)
+	ts := &TableSpec{Name: "COND_TEST"}
+	k1, k2 := *NewKey("k1"), *NewKey("k2")
+	d.SetEntry(ts, k1, Value{Field: map[string]string{"a": "1", "b": "2"}})
+
+	if err := d.ModEntryIf(ts, k1, Value{Field: map[string]string{"c": "3"}}, EntryCond{}); err == nil {
+		t.Fatalf("ModEntryIf() without transaction did not fail")
+	}
+
+	commit := func(ops func()) error {
+		t.Helper()
+		if err := d.StartTx(nil, nil); err != nil {
+			t.Fatalf("StartTx() failed; err=%v", err)
+		}
+		ops()
+		return d.CommitTx()
+	}
+	verify := func(key Key, exp map[string]string) {
+		t.Helper()
+		v, _ := d.GetEntry(ts, key)
+		if !reflect.DeepEqual(v.Field, exp) && (len(exp) != 0 || len(v.Field) != 0) {
+			t.Fatalf("GetEntry(%v) = %v; expected %v", key, v.Field, exp)
+		}
+	}
+
+	// Conditions satisfied
+	err := commit(func() {
+		d.ModEntryIf(ts, k1, Value{Field: map[string]string{"c": "3"}},
+			EntryCond{Value: Value{Field: map[string]string{"a": "1"}}})
+		d.SetEntryIf(ts, k2, Value{Field: map[string]string{"x": "1"}},
+			EntryCond{Exact: true}) // k2 should not exist
+	})
+	if err != nil {
+		t.Fatalf("CommitTx() failed; err=%v", err)
+	}
+	verify(k1, map[string]string{"a": "1", "b": "2", "c": "3"})
+	verify(k2, map[string]string{"x": "1"})
+
+	// Exact match fails; none of the tx changes should be applied
+	err = commit(func() {
+		d.ModEntryIf(ts, k2, Value{Field: map[string]string{"y": "2"}},
+			EntryCond{Value: Value{Field: map[string]string{"x": "1"}}})
+		d.DeleteEntryIf(ts, k1,
+			EntryCond{Value: Value{Field: map[string]string{"a": "1", "b": "2"}}, Exact: true})
+	})
+	if !reflect.DeepEqual(err, tlerr.TranslibDBCondFail{Entry: "COND_TEST|k1"}) {
+		t.Fatalf("CommitTx() with unsatisfied condition returned %v", err)
+	}
+	verify(k1, map[string]string{"a": "1", "b": "2", "c": "3"})
+	verify(k2, map[string]string{"x": "1"})
+
+	// Entry deleted if the condition is satisfied
+	err = commit(func() {
+		d.DeleteEntryIf(ts, k2, EntryCond{Value: Value{Field: map[string]string{"x": "1"}}, Exact: true})
+	})
+	if err != nil {
+		t.Fatalf("CommitTx() failed; err=%v", err)
+	}
+	verify(k2, nil)
+}
@@ -24,6 +24,8 @@ import (
 	"fmt"
 	io "io/ioutil"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 
 	"github.com/golang/glog"
@@ -31,6 +33,15 @@ import (
 
 var dbConfigMap = make(map[string]interface{})
 
+// dbNsConfigMap holds the database_config.json of the namespaces (Eg:
+// "asic0", "asic1") listed in the database_global.json of multi-ASIC
+// platforms. The default namespace ("") config is the dbConfigMap.
+var dbNsConfigMap = make(map[string]map[string]interface{})
+
+// dbGlobalConfigFile is the multi-ASIC config, in the directory of the
+// database_config.json.
+const dbGlobalConfigFile = "database_global.json"
+
 func dbConfigInit() {
 	dbConfigPath := "/var/run/redis/sonic-db/database_config.json"
 	if path, ok := os.LookupEnv("DB_CONFIG_PATH"); ok {
@@ -57,27 +68,95 @@ func dbConfigInit() {
 			assert(err)
 		}
 	}
+
+	dbGlobalConfigInit(filepath.Join(filepath.Dir(dbConfigPath),
+		dbGlobalConfigFile))
+}
+
+// dbGlobalConfigInit reads the database_config.json of each namespace
+// included in the database_global.json. Eg:
+//
+//	{ "INCLUDES" : [
+//	    { "include" : "../../redis/sonic-db/database_config.json" },
+//	    { "namespace" : "asic0",
+//	      "include" : "../../redis0/sonic-db/database_config.json" } ] }
+//
+// The include paths are relative to the database_global.json directory.
+// The database_global.json is absent on single ASIC platforms.
+func dbGlobalConfigInit(dbGlobalConfigPath string) {
+	if path, ok := os.LookupEnv("DB_GLOBAL_CONFIG_PATH"); ok {
+		dbGlobalConfigPath = path
+	}
+
+	data, err := io.ReadFile(dbGlobalConfigPath)
+	if err != nil {
+		glog.V(3).Infof("dbGlobalConfigInit: %v; single namespace", err)
+		return
+	}
+
+	var globalConfig struct {
+		Includes []struct {
+			Namespace string `json:"namespace"`
+			Include   string `json:"include"`
+		} `json:"INCLUDES"`
+	}
+	if err = json.Unmarshal(data, &globalConfig); err != nil {
+		glog.Errorf("dbGlobalConfigInit: %s: %v", dbGlobalConfigPath, err)
+		return
+	}
+
+	for _, inc := range globalConfig.Includes {
+		if len(inc.Namespace) == 0 {
+			continue // Default namespace is the dbConfigMap
+		}
+		incPath := inc.Include
+		if !filepath.IsAbs(incPath) {
+			incPath = filepath.Join(filepath.Dir(dbGlobalConfigPath), incPath)
+		}
+		nsConfigMap := make(map[string]interface{})
+		if data, err = io.ReadFile(incPath); err == nil {
+			err = json.Unmarshal(data, &nsConfigMap)
+		}
+		if err != nil {
+			glog.Errorf("dbGlobalConfigInit: namespace %s: %v",
+				inc.Namespace, err)
+			continue
+		}
+		dbNsConfigMap[inc.Namespace] = nsConfigMap
+	}
+}
+
+// getDbConfig returns the DB config of the namespace; nil if the namespace
+// is unknown.
+func getDbConfig(ns string) map[string]interface{} {
+	if len(ns) == 0 {
+		return dbConfigMap
+	}
+	return dbNsConfigMap[ns]
 }
 
 func assert(msg error) {
 	panic(msg)
 }
 
-func getDbList() map[string]interface{} {
-	dbEntries, ok := dbConfigMap["DATABASES"].(map[string]interface{})
+func getDbList(ns string) map[string]interface{} {
+	dbEntries, ok := getDbConfig(ns)["DATABASES"].(map[string]interface{})
 	if !ok {
 		assert(fmt.Errorf("DATABASES is invalid key."))
 	}
 	return dbEntries
 }
 
-func isDbInstPresent(dbName string) bool {
-	_, ok := getDbList()[dbName]
+func isDbInstPresent(ns string, dbName string) bool {
+	if getDbConfig(ns) == nil {
+		return false
+	}
+	_, ok := getDbList(ns)[dbName]
 	return ok
 }
 
-func getDbInst(dbName string) map[string]interface{} {
-	db, ok := dbConfigMap["DATABASES"].(map[string]interface{})[dbName]
+func getDbInst(ns string, dbName string) map[string]interface{} {
+	db, ok := getDbList(ns)[dbName]
 	if !ok {
 		assert(fmt.Errorf("database name '%v' is not found", dbName))
 	}
@@ -85,15 +164,15 @@ func getDbInst(dbName string) map[string]interface{} {
 	if !ok {
 		assert(fmt.Errorf("'instance' is not a valid field"))
 	}
-	inst, ok := dbConfigMap["INSTANCES"].(map[string]interface{})[instName.(string)]
+	inst, ok := getDbConfig(ns)["INSTANCES"].(map[string]interface{})[instName.(string)]
 	if !ok {
 		assert(fmt.Errorf("instance name '%v' is not found", instName))
 	}
 	return inst.(map[string]interface{})
 }
 
-func getDbSeparator(dbName string) string {
-	dbEntries := getDbList()
+func getDbSeparator(ns string, dbName string) string {
+	dbEntries := getDbList(ns)
 	separator, ok := dbEntries[dbName].(map[string]interface{})["separator"]
 	if !ok {
 		assert(fmt.Errorf("'separator' is not a valid field"))
@@ -101,8 +180,8 @@ func getDbSeparator(dbName string) string {
 	return separator.(string)
 }
 
-func getDbId(dbName string) int {
-	dbEntries := getDbList()
+func getDbId(ns string, dbName string) int {
+	dbEntries := getDbList(ns)
 	id, ok := dbEntries[dbName].(map[string]interface{})["id"]
 	if !ok {
 		assert(fmt.Errorf("'id' is not a valid field"))
@@ -110,8 +189,8 @@ func getDbId(dbName string) int {
 	return int(id.(float64))
 }
 
-func getDbHostName(dbName string) string {
-	inst := getDbInst(dbName)
+func getDbHostName(ns string, dbName string) string {
+	inst := getDbInst(ns, dbName)
 	hostname, ok := inst["hostname"]
 	if !ok {
 		assert(fmt.Errorf("'hostname' is not a valid field"))
@@ -119,8 +198,8 @@ func getDbHostName(dbName string) string {
 	return hostname.(string)
 }
 
-func getDbPort(dbName string) int {
-	inst := getDbInst(dbName)
+func getDbPort(ns string, dbName string) int {
+	inst := getDbInst(ns, dbName)
 	port, ok := inst["port"]
 	if !ok {
 		assert(fmt.Errorf("'port' is not a valid field"))
@@ -128,14 +207,14 @@ func getDbPort(dbName string) int {
 	return int(port.(float64))
 }
 
-func getDbTcpAddr(dbName string) string {
-	hostname := getDbHostName(dbName)
-	port := getDbPort(dbName)
+func getDbTcpAddr(ns string, dbName string) string {
+	hostname := getDbHostName(ns, dbName)
+	port := getDbPort(ns, dbName)
 	return hostname + ":" + strconv.Itoa(port)
 }
 
-func getDbSock(dbName string) string {
-	inst := getDbInst(dbName)
+func getDbSock(ns string, dbName string) string {
+	inst := getDbInst(ns, dbName)
 	if unix_socket_path, ok := inst["unix_socket_path"]; ok {
 		return unix_socket_path.(string)
 	} else {
@@ -144,8 +223,8 @@ func getDbSock(dbName string) string {
 	}
 }
 
-func getDbPassword(dbName string) string {
-	inst := getDbInst(dbName)
+func getDbPassword(ns string, dbName string) string {
+	inst := getDbInst(ns, dbName)
 	password := ""
 	password_path, ok := inst["password_path"]
 	if !ok {
@@ -160,6 +239,47 @@ func getDbPassword(dbName string) string {
 	return password
 }
 
+// getDbInstOfAddr returns the "namespace/instance" of a redis instance
+// address (unix socket path, or TCP address); empty if not found.
+func getDbInstOfAddr(addr string) string {
+	for _, ns := range GetNamespaces() {
+		insts, _ := getDbConfig(ns)["INSTANCES"].(map[string]interface{})
+		for instName, inst := range insts {
+			instMap, _ := inst.(map[string]interface{})
+			if sock, _ := instMap["unix_socket_path"].(string); sock == addr {
+				return ns + "/" + instName
+			}
+			if fmt.Sprintf("%v:%v", instMap["hostname"], instMap["port"]) == addr {
+				return ns + "/" + instName
+			}
+		}
+	}
+	return ""
+}
+
 func GetDbConfigMap() map[string]interface{} {
 	return dbConfigMap
 }
+
+// GetNamespaces returns the DB namespaces. The default namespace ("") is
+// the first. The others (Eg: "asic0", "asic1") are present on multi-ASIC
+// platforms only, as listed in the database_global.json.
+func GetNamespaces() []string {
+	nsList := make([]string, 0, len(dbNsConfigMap))
+	for ns := range dbNsConfigMap {
+		nsList = append(nsList, ns)
+	}
+	sort.Strings(nsList)
+	return append([]string{""}, nsList...)
+}
+
+// IsNamespacePresent returns true if the namespace is the default namespace
+// (""), or a known multi-ASIC namespace.
+func IsNamespacePresent(ns string) bool {
+	return getDbConfig(ns) != nil
+}
+
+// IsMultiNamespace returns true on multi-ASIC platforms
+func IsMultiNamespace() bool {
+	return len(dbNsConfigMap) != 0
+}
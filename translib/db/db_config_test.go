@@ -0,0 +1,245 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	ctypes "github.com/Azure/sonic-mgmt-common/cvl/common"
+	"github.com/Azure/sonic-mgmt-common/internal/dbauth"
+	"github.com/go-redis/redis/v7"
+)
+
+// setupTestNamespaces creates a database_global.json with namespaces asic0
+// and asic1, each with a CONFIG_DB and STATE_DB on its own redis instance,
+// and loads it for the duration of the test.
+func setupTestNamespaces(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, data string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatalf("WriteFile(%s) fails e = %v", name, err)
+		}
+	}
+
+	for i, ns := range []string{"asic0", "asic1"} {
+		writeFile(ns+".json", fmt.Sprintf(`{
+			"INSTANCES": { "redis": { "hostname": "127.0.0.1", "port": %d,
+				"unix_socket_path": "/var/run/redis%d/redis.sock" } },
+			"DATABASES": {
+				"CONFIG_DB": { "id": 4, "separator": "|", "instance": "redis" },
+				"STATE_DB": { "id": 6, "separator": "|", "instance": "redis" }
+			} }`, 6380+i, i))
+	}
+	writeFile(dbGlobalConfigFile, `{ "INCLUDES": [
+		{ "include": "database_config.json" },
+		{ "namespace": "asic0", "include": "asic0.json" },
+		{ "namespace": "asic1", "include": "`+filepath.Join(dir, "asic1.json")+`" },
+		{ "namespace": "asic2", "include": "missing.json" } ] }`)
+
+	saved := dbNsConfigMap
+	dbNsConfigMap = make(map[string]map[string]interface{})
+	t.Cleanup(func() { dbNsConfigMap = saved })
+	dbGlobalConfigInit(filepath.Join(dir, dbGlobalConfigFile))
+}
+
+func TestNamespaces(t *testing.T) {
+	setupTestNamespaces(t)
+
+	if ns := GetNamespaces(); !reflect.DeepEqual(ns, []string{"", "asic0", "asic1"}) {
+		t.Errorf("GetNamespaces() = %v", ns)
+	}
+	if !IsMultiNamespace() || !IsNamespacePresent("asic1") || IsNamespacePresent("asic2") {
+		t.Errorf("IsMultiNamespace() = %v, IsNamespacePresent(asic1) = %v, "+
+			"IsNamespacePresent(asic2) = %v", IsMultiNamespace(),
+			IsNamespacePresent("asic1"), IsNamespacePresent("asic2"))
+	}
+
+	if addr := getDbTcpAddr("asic1", "CONFIG_DB"); addr != "127.0.0.1:6381" {
+		t.Errorf("getDbTcpAddr(asic1) = %s", addr)
+	}
+	if isDbInstPresent("asic0", "APPL_DB") || !isDbInstPresent("", "APPL_DB") {
+		t.Errorf("isDbInstPresent(APPL_DB) wrong")
+	}
+	if inst := getDbInstOfAddr("/var/run/redis0/redis.sock"); inst != "asic0/redis" {
+		t.Errorf("getDbInstOfAddr(asic0 sock) = %s", inst)
+	}
+
+	opts := adjustRedisOpts(&Options{DBNo: StateDB, Namespace: "asic1"})
+	if opts.Addr != "/var/run/redis1/redis.sock" || opts.DB != 6 {
+		t.Errorf("adjustRedisOpts(asic1) = %s, %d", opts.Addr, opts.DB)
+	}
+
+	if _, err := NewDB(Options{DBNo: ConfigDB, Namespace: "asic2"}); err == nil {
+		t.Errorf("NewDB() with unknown namespace did not fail")
+	}
+}
+
+func TestNamespacesMemoryBackend(t *testing.T) {
+	setupTestNamespaces(t)
+	useMemoryBackend(t)
+	ts := &TableSpec{Name: "PORT"}
+	key := *NewKey("Ethernet0")
+
+	d0 := newMemTestNsDB(t, "asic0")
+	d1 := newMemTestNsDB(t, "asic1")
+	d0.SetEntry(ts, key, Value{Field: map[string]string{"asic": "0"}})
+
+	if v, err := d0.GetEntry(ts, key); err != nil || v.Get("asic") != "0" {
+		t.Errorf("asic0 GetEntry() = %v, %v", v, err)
+	}
+	if v, err := d1.GetEntry(ts, key); err == nil {
+		t.Errorf("asic1 GetEntry() = %v", v)
+	}
+
+	// CVL searches run in the namespace of the DB
+	for d, exp := range map[*DB]int64{d0: 1, d1: 0} {
+		s := ctypes.Search{Pattern: "PORT|*"}
+		if n, err := (&cvlDBAccess{d}).Count(s).Result(); err != nil || n != exp {
+			t.Errorf("%s Count() = %v, %v; expected %d", d.Opts.Namespace, n, err, exp)
+		}
+	}
+}
+
+func newMemTestNsDB(t *testing.T, ns string) *DB {
+	d, err := NewDB(Options{
+		DBNo:               ConfigDB,
+		Namespace:          ns,
+		TableNameSeparator: "|",
+		KeySeparator:       "|",
+		DisableCVLCheck:    true,
+	})
+	if err != nil {
+		t.Fatalf("NewDB(%s) fails e = %v", ns, err)
+	}
+	t.Cleanup(func() { d.DeleteDB() })
+	return d
+}
+
+// setupTestAuthNamespace adds the namespace ns, whose CONFIG_DB instance
+// (TCP only) has the instance fields inst, for the duration of the test.
+func setupTestAuthNamespace(t *testing.T, ns string, inst map[string]interface{}) {
+	inst["hostname"] = "redis.example.com"
+	inst["port"] = float64(6390)
+	saved := dbNsConfigMap
+	dbNsConfigMap = map[string]map[string]interface{}{ns: {
+		"INSTANCES": map[string]interface{}{"redis": inst},
+		"DATABASES": map[string]interface{}{
+			"CONFIG_DB": map[string]interface{}{
+				"id": float64(4), "separator": "|", "instance": "redis"}},
+	}}
+	t.Cleanup(func() {
+		dbNsConfigMap = saved
+		dbauth.ForgetTLSConfig(ns + "/CONFIG_DB")
+	})
+}
+
+// writeTestCert writes a self-signed certificate and its key PEM files.
+func writeTestCert(t *testing.T, dir string) (certPath, keyPath string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() fails e = %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "redis.example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() fails e = %v", err)
+	}
+	keyDer, _ := x509.MarshalECPrivateKey(key)
+
+	certPath = filepath.Join(dir, "cert.pem")
+	keyPath = filepath.Join(dir, "key.pem")
+	os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)
+	os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+	return
+}
+
+func TestDbAuthTLS(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := writeTestCert(t, dir)
+	setupTestAuthNamespace(t, "tls0", map[string]interface{}{
+		"tls_ca_path":   certPath,
+		"tls_cert_path": certPath,
+		"tls_key_path":  keyPath,
+		"username":      "mgmt",
+	})
+
+	opts := adjustRedisOpts(&Options{DBNo: ConfigDB, Namespace: "tls0"})
+	if opts.Network != "tcp" || opts.TLSConfig == nil {
+		t.Fatalf("adjustRedisOpts() Network = %s, TLSConfig = %v",
+			opts.Network, opts.TLSConfig)
+	}
+	if opts.TLSConfig.ServerName != "redis.example.com" ||
+		opts.TLSConfig.RootCAs == nil || len(opts.TLSConfig.Certificates) != 1 {
+		t.Errorf("adjustRedisOpts() TLSConfig = %+v", opts.TLSConfig)
+	}
+	// The ACL user AUTH, and the SELECT, are done by the OnConnect hook
+	if opts.OnConnect == nil || opts.Password != "" || opts.DB != 0 {
+		t.Errorf("adjustRedisOpts() OnConnect = %v, Password = %q, DB = %d",
+			opts.OnConnect != nil, opts.Password, opts.DB)
+	}
+
+	// Bad TLS config fails the connections
+	setupTestAuthNamespace(t, "tls1", map[string]interface{}{
+		"tls_ca_path":     keyPath,
+		"tls_server_name": "redis0",
+	})
+	client := redis.NewClient(adjustRedisOpts(&Options{DBNo: ConfigDB, Namespace: "tls1"}))
+	defer client.Close()
+	if err := client.Ping().Err(); err == nil {
+		t.Errorf("Ping() with bad TLS config did not fail")
+	}
+}
+
+func TestDbAuthUsernameMemoryBackend(t *testing.T) {
+	useMemoryBackend(t)
+	setupTestAuthNamespace(t, "acl0", map[string]interface{}{"username": "mgmt"})
+	ts := &TableSpec{Name: "PORT"}
+
+	d := newMemTestNsDB(t, "acl0")
+	if err := d.SetEntry(ts, *NewKey("Ethernet0"), Value{Field: map[string]string{"mtu": "9100"}}); err != nil {
+		t.Fatalf("SetEntry() fails e = %v", err)
+	}
+
+	// The entry is in the CONFIG_DB (4), SELECTed after the AUTH
+	client := redis.NewClient(&redis.Options{
+		Addr: "redis.example.com:6390", DB: 4, Dialer: backendDialer()})
+	defer client.Close()
+	if mtu, err := client.HGet("PORT|Ethernet0", "mtu").Result(); err != nil || mtu != "9100" {
+		t.Errorf("HGet() = %s, %v", mtu, err)
+	}
+}
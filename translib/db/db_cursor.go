@@ -57,6 +57,7 @@ type ScanCursor struct {
 	lookAhead    []string // (TBD) For exactly CountHint # of keys
 	db           *DB
 	scnr         scanner
+	values       map[string]Value // Entries of the last scan; WithEntries
 }
 
 // ScanType type indicates the type of scan (Eg: KeyScanType, FieldScanType).
@@ -74,6 +75,18 @@ type ScanCursorOpts struct {
 	ScanType               // To mention the type of scan; default is KeyScanType
 	FldScanPatt     string // Field pattern to scan
 	AllowWritable   bool   // Allow on write enabled DB object; ignores tx cache
+
+	// The following are KeyScanType options, for the NewScanCursor() only.
+
+	// Predicate is the body of a Lua function of the key 'k' and hash 'h'
+	// of an entry, which returns true to select the entry, as the CVL
+	// Search.Predicate. Eg: `return k['type'] == 'L3' and h['mtu'] == '9100'`.
+	// Evaluated in the redis-server; not supported by the memory backend.
+	Predicate   string
+	KeyNames    []string // Key component names, in order, for the 'k'
+	WithEntries bool     // GetNextEntries() returns the entries with keys
+	Sorted      bool     // Iterate in the natural order (Eg: Ethernet2 < Ethernet10) of keys
+	StartAfter  *Key     // Sorted iteration starts after this key. Eg: last key of the previous page
 }
 
 type scanner interface {
@@ -132,7 +145,22 @@ func (d *DB) NewScanCursor(ts *TableSpec, pattern Key, scOpts *ScanCursorOpts) (
 	}
 
 	var scnr scanner
-	if scnType == KeyScanType { // Key Scanner
+	if scnType == KeyScanType && scOpts != nil && (len(scOpts.Predicate) != 0 ||
+		scOpts.WithEntries || scOpts.Sorted || scOpts.StartAfter != nil) {
+
+		if len(scOpts.Predicate) != 0 && d.cpDs != nil {
+			err := fmt.Errorf("ScanCursor Predicate is not supported for checkpoint datastore")
+			glog.Error("NewScanCursor: error: ", err)
+			return nil, err
+		}
+		scnr = newEntryScanner(d, scOpts)
+	} else if d.cpDs != nil { // Checkpoint Datastore
+		if scnType == KeyScanType {
+			scnr = &cpKeyScanner{}
+		} else if scnType == FieldScanType {
+			scnr = &cpFieldScanner{scOpts.FldScanPatt}
+		}
+	} else if scnType == KeyScanType { // Key Scanner
 		scnr = &keyScanner{}
 	} else if scnType == FieldScanType { // Field Scanner
 		scnr = &fieldScanner{scOpts.FldScanPatt}
@@ -147,11 +175,11 @@ func (d *DB) NewScanCursor(ts *TableSpec, pattern Key, scOpts *ScanCursorOpts) (
 		scnr:    scnr,
 	}
 
-	if !scOpts.AllowDuplicates {
+	if scOpts == nil || !scOpts.AllowDuplicates {
 		scanCursor.seenKeys = make(map[string]bool, initialSCSeenKeysCacheSize)
 	}
 
-	if scOpts.ReturnFixed {
+	if scOpts != nil && scOpts.ReturnFixed {
 		glog.Info("NewScanCursor: ReturnFixed is not implemented")
 		scanCursor.lookAhead = make([]string, 0, initialSCLookAheadBufferSize)
 	}
@@ -175,6 +203,10 @@ func (sc *ScanCursor) DeleteScanCursor() error {
 	sc.scanComplete = true
 	sc.seenKeys = nil
 	sc.lookAhead = nil
+	sc.values = nil
+	if scnr, ok := sc.scnr.(*entryScanner); ok {
+		scnr.sortedKeys = nil
+	}
 
 	return nil
 }
@@ -186,7 +218,7 @@ func (sc *ScanCursor) GetNextKeys(scOpts *ScanCursorOpts) ([]Key, bool, error) {
 		return keys, true, tlerr.TranslibDBConnectionReset{}
 	}
 
-	if _, ok := sc.scnr.(*keyScanner); !ok {
+	if !isKeyScanner(sc.scnr) {
 		err := fmt.Errorf("Invalid scanner interface %v; exepcted is keyScanner", reflect.TypeOf(sc.scnr))
 		glog.Error("ScanCursor: GetNextKeys: error: ", err)
 		return keys, false, err
@@ -205,7 +237,7 @@ func (sc *ScanCursor) GetNextRedisKeys(scOpts *ScanCursorOpts) ([]string, bool,
 	var redisKeys []string
 	var scnComplete bool
 
-	if _, ok := sc.scnr.(*keyScanner); !ok {
+	if !isKeyScanner(sc.scnr) {
 		err := fmt.Errorf("Invalid scanner interface %v; expected keyScanner",
 			reflect.TypeOf(sc.scnr))
 		glog.Error("ScanCursor: GetNextRedisKeys: error: ", err)
@@ -222,6 +254,35 @@ func (sc *ScanCursor) GetNextRedisKeys(scOpts *ScanCursorOpts) ([]string, bool,
 	return redisKeys, scnComplete, err
 }
 
+// GetNextEntries retrieves a few keys, and their entries. The ScanCursor
+// should have been created with the WithEntries option. bool returns true
+// if the scan is complete.
+func (sc *ScanCursor) GetNextEntries(scOpts *ScanCursorOpts) ([]Key, []Value, bool, error) {
+	if (sc == nil) || (sc.db == nil) || (sc.db.client == nil) {
+		return nil, nil, true, tlerr.TranslibDBConnectionReset{}
+	}
+
+	if scnr, ok := sc.scnr.(*entryScanner); !ok || !scnr.withEntries {
+		err := fmt.Errorf("Invalid scan cursor; WithEntries option is not set")
+		glog.Error("ScanCursor: GetNextEntries: error: ", err)
+		return nil, nil, false, err
+	}
+	if scOpts != nil && scOpts.ScanType == FieldScanType {
+		err := fmt.Errorf("Invalid scan cursor option: given scan type is %v; exepcted is %v", scOpts.ScanType, KeyScanType)
+		glog.Error("ScanCursor: GetNextEntries: error: ", err)
+		return nil, nil, false, err
+	}
+
+	_, redisKeys, _, scnComplete, err := sc.getNext(scOpts, true)
+	keys := make([]Key, len(redisKeys))
+	values := make([]Value, len(redisKeys))
+	for i, redisKey := range redisKeys {
+		keys[i] = sc.db.redis2key(sc.ts, redisKey)
+		values[i] = sc.values[redisKey]
+	}
+	return keys, values, scnComplete, err
+}
+
 // GetNextFields retrieves a few matching fields. bool returns true if the scan is complete.
 func (sc *ScanCursor) GetNextFields(scOpts *ScanCursorOpts) (Value, bool, error) {
 	var val Value
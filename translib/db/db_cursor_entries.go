@@ -0,0 +1,312 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/sonic-mgmt-common/translib/tlerr"
+	"github.com/go-redis/redis/v7"
+)
+
+// entryScanner is the KeyScanType scanner for the Predicate, WithEntries,
+// Sorted, and StartAfter ScanCursorOpts.
+//
+// Unsorted, each scan is a single luaScriptScanEntries round trip, which
+// SCANs, filters the keys with the predicate, and returns the entries.
+//
+// Sorted, the matching keys are read and sorted by the first scan. Each
+// scan then returns the next countHint of them; filtered with the predicate,
+// and with the entries, by a luaScriptScanEntries round trip of those keys.
+// The order is stable across ScanCursors (Eg: pages of a GET), since the
+// next page starts after the (StartAfter) last key of the previous page,
+// even if keys are added or deleted in between.
+type entryScanner struct {
+	predicate   string
+	keyNames    string // Key component names, joined by the KeySeparator
+	withEntries bool
+	sorted      bool
+	startAfter  *Key
+
+	sortedKeys []string // Sorted redis keys; nil until the first scan
+	next       int      // Index of the next sortedKeys to return
+}
+
+func newEntryScanner(d *DB, scOpts *ScanCursorOpts) *entryScanner {
+	return &entryScanner{
+		predicate:   scOpts.Predicate,
+		keyNames:    strings.Join(scOpts.KeyNames, d.Opts.KeySeparator),
+		withEntries: scOpts.WithEntries,
+		sorted:      scOpts.Sorted || scOpts.StartAfter != nil,
+		startAfter:  scOpts.StartAfter,
+	}
+}
+
+func (scnr *entryScanner) scan(sc *ScanCursor, countHint int64) ([]string, uint64, error) {
+	sc.values = nil
+
+	// The checkpoint datastore has the whole data in memory; hence it is
+	// always iterated sorted.
+	if !scnr.sorted && sc.db.cpDs == nil {
+		return scnr.scanLua(sc, strconv.FormatUint(sc.cursor, 10), nil, countHint)
+	}
+
+	if scnr.sortedKeys == nil {
+		if err := scnr.sortKeys(sc); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	end := scnr.next + int(countHint)
+	if end > len(scnr.sortedKeys) {
+		end = len(scnr.sortedKeys)
+	}
+	page := scnr.sortedKeys[scnr.next:end]
+	scnr.next = end
+
+	var cursor uint64
+	if end < len(scnr.sortedKeys) {
+		cursor = uint64(end)
+	}
+
+	if cpDs := sc.db.cpDs; cpDs != nil {
+		if scnr.withEntries {
+			sc.values = make(map[string]Value, len(page))
+			for _, redisKey := range page {
+				sc.values[redisKey] = cpDs.getEntry(redisKey)
+			}
+		}
+		return page, cursor, nil
+	}
+
+	if len(scnr.predicate) == 0 && !scnr.withEntries {
+		return page, cursor, nil
+	}
+
+	keys, _, err := scnr.scanLua(sc, "", page, countHint)
+	return keys, cursor, err
+}
+
+// sortKeys reads the matching keys, after the startAfter key, in natural
+// order. All the matching keys are read (with a SCAN loop, which does not
+// block the redis-server, unlike KEYS), and held in the cursor, on the first
+// GetNext call. So a Sorted cursor costs O(table size) time and space, even
+// if only a page is read.
+func (scnr *entryScanner) sortKeys(sc *ScanCursor) error {
+	d := sc.db
+	pattern := d.key2redis(sc.ts, sc.pattern)
+	var redisKeys []string
+	if d.cpDs != nil {
+		redisKeys = d.cpDs.keys(pattern)
+	} else {
+		seen := make(map[string]bool)
+		var cursor uint64
+		for {
+			keys, next, err := d.client.Scan(cursor, pattern, sortScanCount).Result()
+			if err != nil {
+				return err
+			}
+			for _, redisKey := range keys {
+				if !seen[redisKey] { // SCAN may return a key more than once
+					seen[redisKey] = true
+					redisKeys = append(redisKeys, redisKey)
+				}
+			}
+			if cursor = next; cursor == 0 {
+				break
+			}
+		}
+	}
+
+	keys := make([]Key, len(redisKeys))
+	for i, redisKey := range redisKeys {
+		keys[i] = d.redis2key(sc.ts, redisKey)
+	}
+	sort.Sort(natSortedKeys{keys, redisKeys})
+
+	if scnr.startAfter != nil {
+		scnr.next = sort.Search(len(keys), func(i int) bool {
+			return natCompareList(keys[i].Comp, scnr.startAfter.Comp) > 0
+		})
+	}
+	scnr.sortedKeys = redisKeys
+	return nil
+}
+
+// scanLua runs the luaScriptScanEntries, either to SCAN from the cursor,
+// or to filter the redisKeys if the cursor is "". Sets the sc.values if
+// withEntries.
+func (scnr *entryScanner) scanLua(sc *ScanCursor, cursor string,
+	redisKeys []string, countHint int64) ([]string, uint64, error) {
+
+	d := sc.db
+	withEntries := ""
+	if scnr.withEntries {
+		withEntries = "1"
+	}
+	prefixLen := len(sc.ts.Name) + len(d.Opts.TableNameSeparator)
+
+	res, err := luaScriptScanEntries.Run(d.client, redisKeys, cursor,
+		d.key2redis(sc.ts, sc.pattern), countHint, scnr.predicate,
+		scnr.keyNames, d.Opts.KeySeparator, prefixLen, withEntries).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	list, ok := res.([]interface{})
+	if !ok || len(list) == 0 {
+		return nil, 0, tlerr.TranslibDBScriptFail{Description: "Unexpected list"}
+	}
+	next, _ := list[0].(string)
+	nextCursor, err := strconv.ParseUint(next, 10, 64)
+	if err != nil {
+		return nil, 0, tlerr.TranslibDBScriptFail{Description: "Unexpected cursor"}
+	}
+
+	step := 1
+	if scnr.withEntries {
+		step = 2
+		sc.values = make(map[string]Value, (len(list)-1)/2)
+	}
+	keys := make([]string, 0, (len(list)-1)/step)
+	for i := 1; i+step-1 < len(list); i += step {
+		redisKey, ok := list[i].(string)
+		if !ok {
+			return nil, 0, tlerr.TranslibDBScriptFail{Description: "Unexpected key"}
+		}
+		keys = append(keys, redisKey)
+		if !scnr.withEntries {
+			continue
+		}
+		hash, _ := list[i+1].([]interface{})
+		value := Value{Field: make(map[string]string, len(hash)/2)}
+		for j := 0; j+1 < len(hash); j += 2 {
+			f, _ := hash[j].(string)
+			v, _ := hash[j+1].(string)
+			value.Field[f] = v
+		}
+		sc.values[redisKey] = value
+	}
+
+	return keys, nextCursor, nil
+}
+
+// natSortedKeys sorts the keys, and the corresponding redisKeys, in the
+// natural order of the key components.
+type natSortedKeys struct {
+	keys      []Key
+	redisKeys []string
+}
+
+func (s natSortedKeys) Len() int { return len(s.keys) }
+
+func (s natSortedKeys) Less(i, j int) bool {
+	return natCompareList(s.keys[i].Comp, s.keys[j].Comp) < 0
+}
+
+func (s natSortedKeys) Swap(i, j int) {
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+	s.redisKeys[i], s.redisKeys[j] = s.redisKeys[j], s.redisKeys[i]
+}
+
+// isKeyScanner returns true if the scanner returns keys
+func isKeyScanner(scnr scanner) bool {
+	switch scnr.(type) {
+	case *keyScanner, *cpKeyScanner, *entryScanner:
+		return true
+	}
+	return false
+}
+
+// sortScanCount is the SCAN count hint of sortKeys
+const sortScanCount int64 = 1000
+
+var luaScriptScanEntries *redis.Script
+
+func init() {
+	// Register the Lua Script
+	luaScriptScanEntries = redis.NewScript(`
+		-- KEYS    => Keys to filter, if ARGV[1] is ""
+		-- ARGV[1] => SCAN cursor; "" to filter the KEYS instead
+		-- ARGV[2] => SCAN key pattern
+		-- ARGV[3] => SCAN count hint
+		-- ARGV[4] => Predicate on the key 'k', and hash 'h'
+		-- ARGV[5] => Key names, separated by the key separator
+		-- ARGV[6] => Key separator
+		-- ARGV[7] => Length of the table name, and separator
+		-- ARGV[8] => "1" to return the hashes
+		-- Returns { cursor, key1, [hash1,] key2, [hash2,] ... }
+
+		local function split(str, sep)
+			local t = {}; local i = 1
+			if sep == "" then return { str } end
+			while true do
+				local j = string.find(str, sep, i, true)
+				if j == nil then table.insert(t, string.sub(str, i)); return t end
+				table.insert(t, string.sub(str, i, j-1)); i = j + #sep
+			end
+		end
+
+		local cursor = "0"
+		local keys = KEYS
+		if ARGV[1] ~= "" then
+			local r = redis.call('SCAN', ARGV[1], 'MATCH', ARGV[2], 'COUNT', ARGV[3])
+			cursor = r[1]; keys = r[2]
+		end
+
+		local predicate = nil
+		if ARGV[4] ~= "" then
+			local f, err = loadstring("return function (k,h) " .. ARGV[4] .. " end")
+			if f == nil then return redis.error_reply("Predicate: " .. err) end
+			predicate = f()
+		end
+
+		local keyNames = {}
+		if ARGV[5] ~= "" then keyNames = split(ARGV[5], ARGV[6]) end
+		local prefixLen = tonumber(ARGV[7])
+
+		local result = { cursor }
+		for _, key in ipairs(keys) do
+			local hash = nil
+			local match = true
+			if predicate ~= nil or ARGV[8] == "1" then
+				hash = redis.call('HGETALL', key)
+				-- Deleted since SCAN or KEYS
+				match = #hash ~= 0
+			end
+			if match and predicate ~= nil then
+				local h = {}
+				for i = 1, #hash, 2 do h[hash[i]] = hash[i+1] end
+				local k = {}
+				local keyVal = split(string.sub(key, prefixLen+1), ARGV[6])
+				for i, name in ipairs(keyNames) do k[name] = keyVal[i] end
+				match = (predicate(k, h) == true)
+			end
+			if match then
+				table.insert(result, key)
+				if ARGV[8] == "1" then table.insert(result, hash) end
+			end
+		end
+
+		return result
+	`)
+}
@@ -28,9 +28,9 @@ import (
 	// "github.com/Azure/sonic-mgmt-common/translib/tlerr"
 	// "os/exec"
 	"os"
+	"reflect"
 	"strconv"
 	"testing"
-	// "reflect"
 )
 
 func testSCAddDelKeys(t *testing.T, d *DB, ts *TableSpec, prefix string, count int, delete bool) {
@@ -117,3 +117,188 @@ func TestNewScanCursor(t *testing.T) {
 	t.Run("pattern=NOTALIKELYKEY", testSCGetNextKeys(d, &ts, "NOTALIKELYKEY", 0))
 	d.Opts.IsWriteDisabled = false
 }
+
+// testSCGetAllEntries returns the keys and values of all the
+// GetNextEntries() of the ScanCursor.
+func testSCGetAllEntries(t *testing.T, d *DB, ts *TableSpec, pattern Key,
+	scOpts *ScanCursorOpts) ([]string, map[string]Value) {
+
+	sc, e := d.NewScanCursor(ts, pattern, scOpts)
+	if e != nil {
+		t.Fatalf("NewScanCursor() fails e = %v", e)
+	}
+	defer sc.DeleteScanCursor()
+
+	var keys []string
+	values := make(map[string]Value)
+	for scanComplete := false; !scanComplete; {
+		var k []Key
+		var v []Value
+		if k, v, scanComplete, e = sc.GetNextEntries(nil); e != nil {
+			t.Fatalf("GetNextEntries() fails e = %v", e)
+		}
+		for i := range k {
+			keys = append(keys, k[i].Get(0))
+			values[k[i].Get(0)] = v[i]
+		}
+	}
+	return keys, values
+}
+
+func TestScanCursorSortedEntries(t *testing.T) {
+	onTestBackends(t, testScanCursorSortedEntries)
+}
+
+func testScanCursorSortedEntries(t *testing.T) {
+	d := openTestDB(t, ConfigDB, false)
+	ts := &TableSpec{Name: "SC_SORT_TEST"}
+	var exp []string
+	for _, port := range []int{0, 1, 2, 4, 8, 10, 16, 32, 100} {
+		name := "Ethernet" + strconv.Itoa(port)
+		d.SetEntry(ts, *NewKey(name), Value{Field: map[string]string{"index": strconv.Itoa(port)}})
+		exp = append(exp, name)
+	}
+	rd := openTestDB(t, ConfigDB, true)
+
+	keys, values := testSCGetAllEntries(t, rd, ts, *NewKey("*"),
+		&ScanCursorOpts{CountHint: 4, Sorted: true, WithEntries: true})
+	if !reflect.DeepEqual(keys, exp) {
+		t.Errorf("Sorted keys = %v; expected %v", keys, exp)
+	}
+	if v := values["Ethernet16"]; v.Get("index") != "16" {
+		t.Errorf("Ethernet16 entry = %v", v)
+	}
+
+	// Next page, after a key added and deleted
+	d.SetEntry(ts, *NewKey("Ethernet9"), Value{Field: map[string]string{"index": "9"}})
+	d.DeleteEntry(ts, *NewKey("Ethernet10"))
+	keys, _ = testSCGetAllEntries(t, rd, ts, *NewKey("*"), &ScanCursorOpts{
+		CountHint: 2, WithEntries: true, StartAfter: NewKey("Ethernet8")})
+	if exp := []string{"Ethernet9", "Ethernet16", "Ethernet32", "Ethernet100"}; !reflect.DeepEqual(keys, exp) {
+		t.Errorf("StartAfter keys = %v; expected %v", keys, exp)
+	}
+
+	// Unsorted, with entries
+	keys, values = testSCGetAllEntries(t, rd, ts, *NewKey("Ethernet1*"),
+		&ScanCursorOpts{CountHint: 2, WithEntries: true})
+	if len(keys) != 3 || values["Ethernet100"].Field["index"] != "100" {
+		t.Errorf("Unsorted keys = %v, values = %v", keys, values)
+	}
+
+	// GetNextEntries needs the WithEntries option
+	sc, _ := rd.NewScanCursor(ts, *NewKey("*"), &ScanCursorOpts{Sorted: true})
+	defer sc.DeleteScanCursor()
+	if _, _, _, e := sc.GetNextEntries(nil); e == nil {
+		t.Errorf("GetNextEntries() without WithEntries did not fail")
+	}
+	if k, _, e := sc.GetNextKeys(nil); e != nil || len(k) != 9 || k[0].Get(0) != "Ethernet0" {
+		t.Errorf("Sorted GetNextKeys() = %v, %v", k, e)
+	}
+}
+
+func TestScanCursorPredicate(t *testing.T) {
+	d, e := NewDB(Options{
+		DBNo:               ConfigDB,
+		TableNameSeparator: "|",
+		KeySeparator:       "|",
+		DisableCVLCheck:    true,
+	})
+	if e != nil {
+		t.Fatalf("NewDB() fails e = %v", e)
+	}
+	defer d.DeleteDB()
+
+	ts := &TableSpec{Name: "TESTSC_PRED_" + strconv.Itoa(os.Getpid())}
+	for i := 0; i < 20; i++ {
+		d.SetEntry(ts, *NewKey("Vlan"+strconv.Itoa(i), "Ethernet"+strconv.Itoa(i%4)),
+			Value{Field: map[string]string{"tagging_mode": []string{"tagged", "untagged"}[i%2]}})
+	}
+	defer func() {
+		for i := 0; i < 20; i++ {
+			d.DeleteEntry(ts, *NewKey("Vlan"+strconv.Itoa(i), "Ethernet"+strconv.Itoa(i%4)))
+		}
+	}()
+	d.Opts.IsWriteDisabled = true
+	defer func() { d.Opts.IsWriteDisabled = false }()
+
+	// Ethernet1 and Ethernet3 members are untagged
+	keys, values := testSCGetAllEntries(t, d, ts, *NewKey("*", "*"), &ScanCursorOpts{
+		CountHint:   3,
+		Predicate:   "return (k['port'] == 'Ethernet1' and h['tagging_mode'] == 'untagged')",
+		KeyNames:    []string{"vlan", "port"},
+		WithEntries: true,
+		Sorted:      true,
+	})
+	exp := []string{"Vlan1", "Vlan5", "Vlan9", "Vlan13", "Vlan17"}
+	if !reflect.DeepEqual(keys, exp) || values["Vlan9"].Field["tagging_mode"] != "untagged" {
+		t.Errorf("Predicate keys = %v, values = %v; expected %v", keys, values, exp)
+	}
+
+	// Unsorted
+	keys, _ = testSCGetAllEntries(t, d, ts, *NewKey("*", "*"), &ScanCursorOpts{
+		Predicate: "return h['tagging_mode'] == 'tagged'", WithEntries: true})
+	if len(keys) != 10 {
+		t.Errorf("Unsorted Predicate keys = %v", keys)
+	}
+
+	// Invalid predicate
+	sc, _ := d.NewScanCursor(ts, *NewKey("*", "*"), &ScanCursorOpts{Predicate: "return ("})
+	defer sc.DeleteScanCursor()
+	if _, _, e = sc.GetNextKeys(nil); e == nil {
+		t.Errorf("GetNextKeys() with invalid predicate did not fail")
+	}
+}
+
+// TestScanCursorPredicateForms tests the predicates, in the documented form,
+// and other Lua expressions, on the redis-server.
+func TestScanCursorPredicateForms(t *testing.T) {
+	useRedisServer(t)
+	d := openTestDB(t, ConfigDB, false)
+	ts := &TableSpec{Name: "SC_PRED_TEST"}
+	for i := 0; i < 20; i++ {
+		d.SetEntry(ts, *NewKey("Vlan"+strconv.Itoa(i), "Ethernet"+strconv.Itoa(i%4)),
+			Value{Field: map[string]string{"tagging_mode": []string{"tagged", "untagged"}[i%2]}})
+	}
+	rd := openTestDB(t, ConfigDB, true)
+
+	keys, values := testSCGetAllEntries(t, rd, ts, *NewKey("*", "*"), &ScanCursorOpts{
+		CountHint:   3,
+		Predicate:   "return k['port'] == 'Ethernet1' and h['tagging_mode'] == 'untagged'",
+		KeyNames:    []string{"vlan", "port"},
+		WithEntries: true,
+		Sorted:      true,
+	})
+	exp := []string{"Vlan1", "Vlan5", "Vlan9", "Vlan13", "Vlan17"}
+	if !reflect.DeepEqual(keys, exp) || values["Vlan9"].Field["tagging_mode"] != "untagged" {
+		t.Errorf("Predicate keys = %v, values = %v; expected %v", keys, values, exp)
+	}
+
+	// Unsorted, keys only
+	sc, e := rd.NewScanCursor(ts, *NewKey("*", "*"), &ScanCursorOpts{
+		Predicate: "return not (h.tagging_mode ~= 'tagged' or k.vlan == 'Vlan0')",
+		KeyNames:  []string{"vlan", "port"}})
+	if e != nil {
+		t.Fatalf("NewScanCursor() fails e = %v", e)
+	}
+	defer sc.DeleteScanCursor()
+	var n int
+	for scanComplete := false; !scanComplete; {
+		var k []Key
+		if k, scanComplete, e = sc.GetNextKeys(nil); e != nil {
+			t.Fatalf("GetNextKeys() fails e = %v", e)
+		}
+		n += len(k)
+	}
+	if n != 9 {
+		t.Errorf("Unsorted Predicate #keys = %d; expected 9", n)
+	}
+
+	// Invalid predicate
+	for _, pred := range []string{"return (", "k['port'] == 'Ethernet1'"} {
+		sc, _ := rd.NewScanCursor(ts, *NewKey("*", "*"), &ScanCursorOpts{Predicate: pred})
+		if _, _, e = sc.GetNextKeys(nil); e == nil {
+			t.Errorf("GetNextKeys() with predicate %q did not fail", pred)
+		}
+		sc.DeleteScanCursor()
+	}
+}
@@ -19,19 +19,16 @@
 
 package db
 
-// import (
-// "errors"
-// "flag"
-// "reflect"
-// "strconv"
-// "strings"
-// "sync"
-// "time"
-
-// "github.com/Azure/sonic-mgmt-common/cvl"
-// "github.com/go-redis/redis/v7"
-// "github.com/golang/glog"
-// )
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Azure/sonic-mgmt-common/translib/tlerr"
+	"github.com/golang/glog"
+)
 
 ////////////////////////////////////////////////////////////////////////////////
 //  Exported Types                                                            //
@@ -64,3 +61,220 @@ type DefaultDbDs struct {
 func (ds *DefaultDbDs) Attributes() map[string]string {
 	return map[string]string{}
 }
+
+////////////////////////////////////////////////////////////////////////////////
+//  Internal Types                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+// CHECKPOINTS_DIR holds the checkpoint files; one file per commit-id
+var CHECKPOINTS_DIR = "/etc/sonic/checkpoints"
+
+// CHECKPOINT_EXT is the extension of the checkpoint files
+const CHECKPOINT_EXT = ".cp.json"
+
+// cpDatastore holds the CONFIG_DB contents loaded from a checkpoint file.
+// Entries are indexed by their redis keys (Eg: "VLAN|Vlan10").
+type cpDatastore struct {
+	commitID string
+	entries  map[string]Value
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Internal Functions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+// isCommitIdDs checks if the Datastore is a CommitIdDbDs
+func isCommitIdDs(ds DBDatastore) bool {
+	cpDs, ok := ds.(*CommitIdDbDs)
+	return ok && cpDs != nil
+}
+
+// checkpointFile returns the checkpoint file path for a commit-id
+func checkpointFile(commitID string) (string, error) {
+	if len(commitID) == 0 || commitID != filepath.Base(commitID) ||
+		strings.HasPrefix(commitID, ".") {
+		return "", tlerr.TranslibDBNotSupported{
+			Description: fmt.Sprintf("Invalid commit-id \"%s\"", commitID)}
+	}
+	return filepath.Join(CHECKPOINTS_DIR, commitID+CHECKPOINT_EXT), nil
+}
+
+// openCommitIdDs loads the checkpoint file of a CommitIdDbDs into the DB.
+// Such DBs are read only.
+func (d *DB) openCommitIdDs() error {
+	ds := d.Opts.Datastore.(*CommitIdDbDs)
+
+	if d.Opts.DBNo != ConfigDB {
+		return SupportsCfgDBOnly
+	}
+	if !d.Opts.IsWriteDisabled || d.Opts.IsSession || d.Opts.IsOnChangeEnabled {
+		return SupportsReadOnly
+	}
+
+	fileName, err := checkpointFile(ds.CommitID)
+	if err != nil {
+		return err
+	}
+
+	cpDs, err := loadCheckpoint(fileName, d.Opts.TableNameSeparator)
+	if err != nil {
+		glog.Errorf("openCommitIdDs: %s: %v", fileName, err)
+		return tlerr.TranslibDBCannotOpen{}
+	}
+
+	cpDs.commitID = ds.CommitID
+	d.cpDs = cpDs
+	d.dbCacheConfig.PerConnection = false
+
+	if glog.V(3) {
+		glog.Infof("openCommitIdDs: %s: %d entries", fileName, len(cpDs.entries))
+	}
+	return nil
+}
+
+// loadCheckpoint parses a config_db.json style checkpoint file.
+// List values are translated to the redis "field@" encoding.
+func loadCheckpoint(fileName, tableSeparator string) (*cpDatastore, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg map[string]map[string]map[string]interface{}
+	if err = json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+
+	mData := make(MigrationData, len(cfg))
+	for table, tData := range cfg {
+		mData[table] = make(map[string]Value, len(tData))
+		for key, fields := range tData {
+			value := Value{Field: make(map[string]string, len(fields))}
+			for name, v := range fields {
+				switch v := v.(type) {
+				case string:
+					value.Field[name] = v
+				case []interface{}:
+					items := make([]string, len(v))
+					for i, item := range v {
+						items[i] = fmt.Sprint(item)
+					}
+					value.Field[name+"@"] = strings.Join(items, ",")
+				default:
+					value.Field[name] = fmt.Sprint(v)
+				}
+			}
+			// Entries without fields are stored with "NULL" field in redis
+			if len(value.Field) == 0 {
+				value.Field["NULL"] = "NULL"
+			}
+			mData[table][key] = value
+		}
+	}
+
+	// Older checkpoints are migrated to the current schema, so that they
+	// can still be rolled back to. Only in memory; the file is rewritten
+	// by MigrateCheckpoints().
+	report, err := mData.migrate(&MigrateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fileName, err)
+	}
+	if len(report.Applied) != 0 && glog.V(2) {
+		glog.Infof("loadCheckpoint: %s: migrated %s -> %s", fileName,
+			report.FromVersion, report.ToVersion)
+	}
+
+	cpDs := &cpDatastore{entries: make(map[string]Value, len(cfg)*InitialTableEntryCount)}
+	for table, entries := range mData {
+		for key, value := range entries {
+			cpDs.entries[table+tableSeparator+key] = value
+		}
+	}
+
+	return cpDs, nil
+}
+
+// getEntry returns a copy of the entry for a redis key
+func (cpDs *cpDatastore) getEntry(redisKey string) Value {
+	if v, ok := cpDs.entries[redisKey]; ok {
+		return v.Copy()
+	}
+	return Value{}
+}
+
+// keys returns redis keys matching a redis glob pattern
+func (cpDs *cpDatastore) keys(pattern string) []string {
+	var redisKeys []string
+	for k := range cpDs.entries {
+		if patternMatch(k, 0, pattern, 0) {
+			redisKeys = append(redisKeys, k)
+		}
+	}
+	return redisKeys
+}
+
+// cpKeyScanner is the KeyScanType scanner for a cpDatastore. The whole
+// data is in memory; hence the scan completes in a single iteration.
+type cpKeyScanner struct {
+}
+
+func (scnr *cpKeyScanner) scan(sc *ScanCursor, countHint int64) ([]string, uint64, error) {
+	return sc.db.cpDs.keys(sc.db.key2redis(sc.ts, sc.pattern)), 0, nil
+}
+
+// cpFieldScanner is the FieldScanType scanner for a cpDatastore. Returns
+// the matching field names and values, like HSCAN.
+type cpFieldScanner struct {
+	fldNamePattern string // pattern to match field name
+}
+
+func (scnr *cpFieldScanner) scan(sc *ScanCursor, countHint int64) ([]string, uint64, error) {
+	key := sc.ts.Name
+	if len(sc.pattern.Comp) > 0 {
+		key = sc.db.key2redis(sc.ts, sc.pattern)
+	}
+
+	var fldNameVals []string
+	for name, val := range sc.db.cpDs.entries[key].Field {
+		if len(scnr.fldNamePattern) == 0 || patternMatch(name, 0, scnr.fldNamePattern, 0) {
+			fldNameVals = append(fldNameVals, name, val)
+		}
+	}
+	return fldNameVals, 0, nil
+}
+
+// getConfig is the GetConfig() implementation for a cpDatastore.
+func (cpDs *cpDatastore) getConfig(d *DB, tables []*TableSpec) map[TableSpec]Table {
+	var tsM map[string]*TableSpec
+	if len(tables) != 0 {
+		tsM = make(map[string]*TableSpec, len(tables))
+		for _, ts := range tables {
+			tsM[ts.Name] = ts
+		}
+	}
+
+	tblM := make(map[TableSpec]Table, InitialTablesCount)
+	for redisKey, value := range cpDs.entries {
+		rKts, _ := d.redis2ts_key(redisKey)
+		if tsM != nil {
+			ts, ok := tsM[rKts.Name]
+			if !ok {
+				continue
+			}
+			rKts = *ts
+		}
+
+		if _, ok := tblM[rKts]; !ok {
+			ts := rKts
+			tblM[rKts] = Table{
+				ts:       &ts,
+				entry:    make(map[string]Value, InitialTableEntryCount),
+				complete: true,
+				db:       d,
+			}
+		}
+		tblM[rKts].entry[redisKey] = value.Copy()
+	}
+
+	return tblM
+}
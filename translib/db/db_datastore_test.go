@@ -0,0 +1,179 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var testCheckpoint = `{
+  "VLAN": {
+    "Vlan10": {"vlanid": "10", "members": ["Ethernet0", "Ethernet4"]},
+    "Vlan20": {"vlanid": "20"}
+  },
+  "VLAN_MEMBER": {
+    "Vlan10|Ethernet0": {"tagging_mode": "untagged"},
+    "Vlan10|Ethernet4": {"tagging_mode": "tagged"}
+  },
+  "LOOPBACK_INTERFACE": {
+    "Loopback0": {}
+  }
+}`
+
+func newTestCommitIdDB(t *testing.T, commitID string) (*DB, error) {
+	t.Helper()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "cp1"+CHECKPOINT_EXT)
+	if err := os.WriteFile(file, []byte(testCheckpoint), 0644); err != nil {
+		t.Fatalf("WriteFile(%s) failed; err=%v", file, err)
+	}
+
+	saved := CHECKPOINTS_DIR
+	CHECKPOINTS_DIR = dir
+	t.Cleanup(func() { CHECKPOINTS_DIR = saved })
+
+	return NewDB(Options{
+		DBNo:               ConfigDB,
+		TableNameSeparator: "|",
+		KeySeparator:       "|",
+		IsWriteDisabled:    true,
+		Datastore:          &CommitIdDbDs{CommitID: commitID},
+	})
+}
+
+func TestCommitIdDbDs(t *testing.T) {
+	d, err := newTestCommitIdDB(t, "cp1")
+	if err != nil {
+		t.Fatalf("NewDB() failed; err=%v", err)
+	}
+	defer d.DeleteDB()
+
+	vlanTs := &TableSpec{Name: "VLAN"}
+	memberTs := &TableSpec{Name: "VLAN_MEMBER"}
+
+	t.Run("GetEntry", func(t *testing.T) {
+		v, err := d.GetEntry(vlanTs, *NewKey("Vlan10"))
+		exp := Value{Field: map[string]string{"vlanid": "10", "members@": "Ethernet0,Ethernet4"}}
+		if err != nil || !reflect.DeepEqual(v, exp) {
+			t.Errorf("GetEntry(Vlan10) = %v, %v; expected %v", v, err, exp)
+		}
+		v, err = d.GetEntry(&TableSpec{Name: "LOOPBACK_INTERFACE"}, *NewKey("Loopback0"))
+		if err != nil || v.Get("NULL") != "NULL" {
+			t.Errorf("GetEntry(Loopback0) = %v, %v; expected NULL field", v, err)
+		}
+		if _, err = d.GetEntry(vlanTs, *NewKey("Vlan30")); err == nil {
+			t.Errorf("GetEntry(Vlan30) should have failed")
+		}
+	})
+
+	t.Run("GetKeys", func(t *testing.T) {
+		keys, err := d.GetKeys(vlanTs)
+		verifyCommitIdDsKeys(t, keys, err, "Vlan10", "Vlan20")
+	})
+
+	t.Run("GetKeysPattern", func(t *testing.T) {
+		keys, err := d.GetKeysPattern(memberTs, *NewKey("*", "Ethernet4"))
+		verifyCommitIdDsKeys(t, keys, err, "Vlan10|Ethernet4")
+	})
+
+	t.Run("GetTable", func(t *testing.T) {
+		table, err := d.GetTable(memberTs)
+		if err != nil {
+			t.Fatalf("GetTable() failed; err=%v", err)
+		}
+		keys, _ := table.GetKeys()
+		verifyCommitIdDsKeys(t, keys, nil, "Vlan10|Ethernet0", "Vlan10|Ethernet4")
+		if v, _ := table.GetEntry(*NewKey("Vlan10", "Ethernet4")); v.Get("tagging_mode") != "tagged" {
+			t.Errorf("Table.GetEntry(Vlan10|Ethernet4) = %v", v)
+		}
+	})
+
+	t.Run("GetConfig", func(t *testing.T) {
+		tables, err := d.GetConfig(nil, nil)
+		if err != nil || len(tables) != 3 {
+			t.Fatalf("GetConfig(nil) = %d tables, %v; expected 3 tables", len(tables), err)
+		}
+		tables, err = d.GetConfig([]*TableSpec{memberTs}, nil)
+		if err != nil || len(tables) != 1 || len(tables[*memberTs].entry) != 2 {
+			t.Errorf("GetConfig(VLAN_MEMBER) = %v, %v", tables, err)
+		}
+	})
+
+	t.Run("ScanCursor", func(t *testing.T) {
+		sc, err := d.NewScanCursor(memberTs, *NewKey("Vlan10", "*"), &ScanCursorOpts{})
+		if err != nil {
+			t.Fatalf("NewScanCursor() failed; err=%v", err)
+		}
+		defer sc.DeleteScanCursor()
+		keys, done, err := sc.GetNextKeys(nil)
+		if !done {
+			t.Errorf("GetNextKeys() did not complete the scan")
+		}
+		verifyCommitIdDsKeys(t, keys, err, "Vlan10|Ethernet0", "Vlan10|Ethernet4")
+	})
+
+	t.Run("FieldScanCursor", func(t *testing.T) {
+		opts := &ScanCursorOpts{ScanType: FieldScanType, FldScanPatt: "vlan*"}
+		sc, err := d.NewScanCursor(vlanTs, *NewKey("Vlan10"), opts)
+		if err != nil {
+			t.Fatalf("NewScanCursor() failed; err=%v", err)
+		}
+		defer sc.DeleteScanCursor()
+		v, _, err := sc.GetNextFields(opts)
+		if err != nil || len(v.Field) != 1 || v.Get("vlanid") != "10" {
+			t.Errorf("GetNextFields() = %v, %v", v, err)
+		}
+	})
+
+	t.Run("SetEntry", func(t *testing.T) {
+		if err := d.SetEntry(vlanTs, *NewKey("Vlan30"), Value{Field: map[string]string{"vlanid": "30"}}); err == nil {
+			t.Errorf("SetEntry() should have failed")
+		}
+	})
+}
+
+func TestCommitIdDbDs_Errors(t *testing.T) {
+	for _, id := range []string{"", "cp2", "../cp1", ".cp1"} {
+		if d, err := newTestCommitIdDB(t, id); err == nil {
+			d.DeleteDB()
+			t.Errorf("NewDB() with commit-id %q should have failed", id)
+		}
+	}
+}
+
+func verifyCommitIdDsKeys(t *testing.T, keys []Key, err error, exp ...string) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("err=%v", err)
+	}
+	var found []string
+	for _, k := range keys {
+		found = append(found, strings.Join(k.Comp, "|"))
+	}
+	sort.Strings(found)
+	if !reflect.DeepEqual(found, exp) {
+		t.Errorf("Found keys %v; expected %v", found, exp)
+	}
+}
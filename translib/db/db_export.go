@@ -20,18 +20,38 @@
 package db
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
+	"unicode/utf16"
+
+	"github.com/Azure/sonic-mgmt-common/cvl"
 )
 
+// ExportOptions controls the contents of the db dump generated by
+// ExportWithOptions().
+type ExportOptions struct {
+	// IncludeTables restricts the dump to these tables only.
+	// All tables are included if it is empty.
+	IncludeTables []string
+	// ExcludeTables lists the tables to be skipped.
+	ExcludeTables []string
+	// SystemTables includes the tables marked with sonic-extension:system-generated
+	// in the yang. Such tables are skipped by default.
+	SystemTables bool
+}
+
 // Export writes the full DB contents to a file in sonic db json format.
-// Includes contents from transaction cache, if present. Uses the sonic-cfggen
-// tool to remove system default entries/keys and apply standard format.
+// Includes contents from transaction cache, if present. System generated tables
+// are removed and the output is pretty printed, in the style of "sonic-cfggen
+// --print-data" (see writeCfggenJSON).
 //
 // If filePath is empty or has '*', it will be expanded to a random name similar to
 // the os.CreateTemp() API. Actual file path will be returned to the caller (outFile).
@@ -39,55 +59,51 @@ import (
 // path (but with garbage contents) even if there was an error. Caller must disregard
 // its contents and cleanup the file when outFile != "" && err != nil.
 func (d *DB) Export(filePath string) (outFile string, err error) {
+	return d.ExportWithOptions(filePath, nil)
+}
+
+// ExportWithOptions is similar to Export(), but allows filtering the tables
+// through ExportOptions. Default options are used if opts is nil.
+func (d *DB) ExportWithOptions(filePath string, opts *ExportOptions) (outFile string, err error) {
 	if d.Opts.DBNo != ConfigDB {
 		return "", fmt.Errorf("Export not supported on %v", d.Opts.DBNo)
 	}
-	var rawDump string
-	if len(d.txCmds) != 0 { // avoid temporary raw dump if there are no tx cache
-		rawDump, err = d.ExportRaw("db_dump_*.json")
-		if len(rawDump) != 0 {
-			defer os.Remove(rawDump)
-		}
+	if opts == nil {
+		opts = &ExportOptions{}
+	}
+
+	jData, err := d.exportData(opts)
+	if err != nil {
+		return
+	}
+
+	var f *os.File
+	f, err = createFile(filePath)
+	if err != nil {
+		err = fmt.Errorf("Failed to create dump file: %w", err)
+		return
 	}
-	if err == nil {
-		outFile, err = generateDbDump(filePath, rawDump)
+
+	defer f.Close()
+	outFile = f.Name()
+	f.Chmod(0664) // make it readable for everyone
+
+	w := bufio.NewWriter(f)
+	writeCfggenJSON(w, jData, d.Opts.KeySeparator)
+	if err = w.Flush(); err != nil {
+		err = fmt.Errorf("Failed to write dump file: %w", err)
 	}
 	return
 }
 
-// ExportRaw is similar to Export(), but does not process the output using sonic-cfggen tool.
+// ExportRaw is similar to Export(), but writes all tables as compact json
+// without any formatting.
 func (d *DB) ExportRaw(filePath string) (outFile string, err error) {
-	// Load everything from DB+txCache
-	var tables map[TableSpec]Table
-	opts := GetConfigOptions{AllowWritable: true}
-	tables, err = d.GetConfig([]*TableSpec{}, &opts)
+	jData, err := d.exportData(nil)
 	if err != nil {
 		return
 	}
 
-	// Prepare to db json map -- {"TABLE":{"KEY":{"FIELD": "VALUE", ...}, ...}, ...}
-	jData := make(map[string]map[string]map[string]interface{})
-	for ts, table := range tables {
-		entryMap := make(map[string]map[string]interface{})
-		keys, _ := table.GetKeys()
-		for _, key := range keys {
-			entry, _ := table.GetEntry(key)
-			entryKey := strings.Join(key.Comp, d.Opts.KeySeparator)
-			values := make(map[string]interface{}, len(entry.Field))
-			for k, v := range entry.Field {
-				switch {
-				case k == "NULL": // skip the dummy NULL field
-				case k[len(k)-1] == '@': // split leaf-list
-					values[k[:len(k)-1]] = strings.Split(v, ",")
-				default:
-					values[k] = v
-				}
-			}
-			entryMap[entryKey] = values
-		}
-		jData[ts.Name] = entryMap
-	}
-
 	// Open file for writing the DB contents
 	var f *os.File
 	f, err = createFile(filePath)
@@ -108,34 +124,209 @@ func (d *DB) ExportRaw(filePath string) (outFile string, err error) {
 	return
 }
 
-// generateDbDump runs sonic-cfggen tool to generate db dump file using data
-// from a json file (rawDumpFile) or from config_db (if rawDumpFile=="").
-func generateDbDump(filePath, rawDumpFile string) (string, error) {
-	f, err := createFile(filePath)
+// exportData loads contents of DB+txCache into a db json map --
+// {"TABLE":{"KEY":{"FIELD": "VALUE", ...}, ...}, ...}.
+// Leaf-list fields (with '@' suffix) are converted to string arrays.
+// Tables are filtered as per opts; all tables are loaded if opts is nil.
+func (d *DB) exportData(opts *ExportOptions) (map[string]map[string]map[string]interface{}, error) {
+	var tsList []*TableSpec
+	if opts != nil {
+		for _, name := range opts.IncludeTables {
+			tsList = append(tsList, &TableSpec{Name: name})
+		}
+	}
+
+	tables, err := d.GetConfig(tsList, &GetConfigOptions{AllowWritable: true})
 	if err != nil {
-		return "", fmt.Errorf("Failed to create dump file: %w", err)
+		return nil, err
 	}
 
-	defer f.Close()
-	f.Chmod(0664)
+	jData := make(map[string]map[string]map[string]interface{})
+	for ts, table := range tables {
+		if opts != nil && opts.skipTable(ts.Name) {
+			continue
+		}
+		entryMap := make(map[string]map[string]interface{})
+		keys, _ := table.GetKeys()
+		for _, key := range keys {
+			entry, _ := table.GetEntry(key)
+			entryKey := strings.Join(key.Comp, d.Opts.KeySeparator)
+			entryMap[entryKey] = exportValue(entry)
+		}
+		jData[ts.Name] = entryMap
+	}
 
-	args := make([]string, 0, 3)
-	args = append(args, "--print-data")
-	if len(rawDumpFile) != 0 {
-		args = append(args, "-j", rawDumpFile)
-	} else {
-		args = append(args, "-d")
+	return jData, nil
+}
+
+// exportValue converts the entry to the db json map of fields. Leaf-list
+// fields (with '@' suffix) are converted to string arrays.
+func exportValue(entry Value) map[string]interface{} {
+	values := make(map[string]interface{}, len(entry.Field))
+	for k, v := range entry.Field {
+		switch {
+		case k == "NULL": // skip the dummy NULL field
+		case k[len(k)-1] == '@': // split leaf-list
+			values[k[:len(k)-1]] = strings.Split(v, ",")
+		default:
+			values[k] = v
+		}
 	}
+	return values
+}
 
-	// Process using sonic-cfggen -- removes system defaults (copp, breakout etc) and pretty print
-	cmd := exec.Command("sonic-cfggen", args...)
-	cmd.Stdout = f // redirect stdout to f
-	err = cmd.Run()
-	if err != nil {
-		err = fmt.Errorf("sonic-cfggen failed: %w", err)
+func (opts *ExportOptions) skipTable(name string) bool {
+	for _, x := range opts.ExcludeTables {
+		if x == name {
+			return true
+		}
+	}
+	return !opts.SystemTables && cvl.IsSystemGeneratedTable(name)
+}
+
+// writeCfggenJSON writes the db json map in the style of "sonic-cfggen
+// --print-data", i.e. python json.dumps() output with indent=4 and ensure_ascii=True.
+// Tables, keys and fields are written in natural sort order, like sonic-cfggen.
+// Keys with multiple components are written after the single component keys,
+// since sonic-cfggen serializes the key tuples after sorting. The fields of
+// such entries are not sorted by sonic-cfggen (they are in the redis hash
+// order); they are sorted here.
+func writeCfggenJSON(w io.Writer, jData map[string]map[string]map[string]interface{}, keySep string) {
+	var buf bytes.Buffer
+	tableNames := make([]string, 0, len(jData))
+	for name := range jData {
+		tableNames = append(tableNames, name)
 	}
+	sort.Slice(tableNames, func(i, j int) bool {
+		return natCompare(tableNames[i], tableNames[j]) < 0
+	})
 
-	return f.Name(), err
+	buf.WriteByte('{')
+	for i, name := range tableNames {
+		writeJSONNewline(&buf, i, 1)
+		writeJSONString(&buf, name)
+		buf.WriteString(": ")
+		writeCfggenTable(&buf, jData[name], keySep)
+		w.Write(buf.Bytes())
+		buf.Reset()
+	}
+	if len(tableNames) != 0 {
+		buf.WriteByte('\n')
+	}
+	buf.WriteString("}\n")
+	w.Write(buf.Bytes())
+}
+
+func writeCfggenTable(buf *bytes.Buffer, entryMap map[string]map[string]interface{}, keySep string) {
+	type exportKey struct {
+		name string
+		comp []string
+	}
+	keys := make([]exportKey, 0, len(entryMap))
+	for k := range entryMap {
+		keys = append(keys, exportKey{name: k, comp: strings.Split(k, keySep)})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ki, kj := keys[i].comp, keys[j].comp
+		if (len(ki) == 1) != (len(kj) == 1) {
+			return len(ki) == 1
+		}
+		return natCompareList(ki, kj) < 0
+	})
+
+	buf.WriteByte('{')
+	for i, k := range keys {
+		writeJSONNewline(buf, i, 2)
+		writeJSONString(buf, k.name)
+		buf.WriteString(": ")
+		writeCfggenEntry(buf, entryMap[k.name])
+	}
+	if len(keys) != 0 {
+		writeJSONNewline(buf, 0, 1)
+	}
+	buf.WriteByte('}')
+}
+
+func writeCfggenEntry(buf *bytes.Buffer, values map[string]interface{}) {
+	fields := make([]string, 0, len(values))
+	for f := range values {
+		fields = append(fields, f)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return natCompare(fields[i], fields[j]) < 0
+	})
+
+	buf.WriteByte('{')
+	for i, f := range fields {
+		writeJSONNewline(buf, i, 3)
+		writeJSONString(buf, f)
+		buf.WriteString(": ")
+		switch v := values[f].(type) {
+		case []string:
+			buf.WriteByte('[')
+			for j, s := range v {
+				writeJSONNewline(buf, j, 4)
+				writeJSONString(buf, s)
+			}
+			if len(v) != 0 {
+				writeJSONNewline(buf, 0, 3)
+			}
+			buf.WriteByte(']')
+		default:
+			writeJSONString(buf, fmt.Sprint(v))
+		}
+	}
+	if len(fields) != 0 {
+		writeJSONNewline(buf, 0, 2)
+	}
+	buf.WriteByte('}')
+}
+
+// writeJSONNewline writes the item separator (for index > 0), a newline
+// and indentation for the given nesting level.
+func writeJSONNewline(buf *bytes.Buffer, index, level int) {
+	if index > 0 {
+		buf.WriteByte(',')
+	}
+	buf.WriteByte('\n')
+	for i := 0; i < level; i++ {
+		buf.WriteString("    ")
+	}
+}
+
+// writeJSONString writes a json string literal, escaping all non-printable
+// and non-ascii characters like python json.dumps() with ensure_ascii=True.
+func writeJSONString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		default:
+			switch {
+			case r >= 0x20 && r < 0x7f:
+				buf.WriteByte(byte(r))
+			case r > 0xffff:
+				r1, r2 := utf16.EncodeRune(r)
+				fmt.Fprintf(buf, `\u%04x\u%04x`, r1, r2)
+			default:
+				fmt.Fprintf(buf, `\u%04x`, r)
+			}
+		}
+	}
+	buf.WriteByte('"')
 }
 
 func createFile(template string) (*os.File, error) {
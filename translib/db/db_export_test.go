@@ -0,0 +1,254 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Azure/sonic-mgmt-common/cvl"
+)
+
+func TestNatCompare(t *testing.T) {
+	sorted := []string{"", "1", "02", "10", "10.0.0.2", "10.0.0.10", "Eth", "Ethernet",
+		"Ethernet1/2", "Ethernet1/10", "Ethernet2", "Ethernet10", "PortChannel5", "Vlan", "Vlan9", "Vlan100", "a"}
+	input := append([]string{}, sorted...)
+	sort.Slice(input, func(i, j int) bool { return natCompare(input[i], input[j]) < 0 })
+	for i := range sorted {
+		if input[i] != sorted[i] {
+			t.Fatalf("natural sort failed;\nexpected %q\nreceived %q", sorted, input)
+		}
+	}
+	if natCompare("a01", "a1") == 0 || natCompare("x", "x") != 0 {
+		t.Fatalf("natCompare should only return 0 for identical strings")
+	}
+}
+
+func TestWriteCfggenJSON(t *testing.T) {
+	jData := map[string]map[string]map[string]interface{}{
+		"VLAN_MEMBER": {
+			"Vlan10|Ethernet8": {"tagging_mode": "tagged"},
+			"Vlan10|Ethernet0": {"tagging_mode": "untagged"},
+			"Vlan2|Ethernet12": {"tagging_mode": "tagged"},
+		},
+		"VLAN": {
+			"Vlan10": {"vlanid": "10", "members": []string{"Ethernet8", "Ethernet0"}},
+			"Vlan2":  {"vlanid": "2", "description": "caf\u00e9 \"x\"\t<&>\U0001F600\x7f"},
+		},
+		"ACL_TABLE": {
+			"EMPTY": {},
+		},
+		"PORT": {},
+	}
+	expected := `{
+    "ACL_TABLE": {
+        "EMPTY": {}
+    },
+    "PORT": {},
+    "VLAN": {
+        "Vlan2": {
+            "description": "caf\u00e9 \"x\"\t<&>\ud83d\ude00\u007f",
+            "vlanid": "2"
+        },
+        "Vlan10": {
+            "members": [
+                "Ethernet8",
+                "Ethernet0"
+            ],
+            "vlanid": "10"
+        }
+    },
+    "VLAN_MEMBER": {
+        "Vlan2|Ethernet12": {
+            "tagging_mode": "tagged"
+        },
+        "Vlan10|Ethernet0": {
+            "tagging_mode": "untagged"
+        },
+        "Vlan10|Ethernet8": {
+            "tagging_mode": "tagged"
+        }
+    }
+}
+`
+	var buf bytes.Buffer
+	writeCfggenJSON(&buf, jData, "|")
+	if buf.String() != expected {
+		t.Fatalf("unexpected output;\nexpected:\n%s\nreceived:\n%s", expected, buf.String())
+	}
+
+	buf.Reset()
+	writeCfggenJSON(&buf, nil, "|")
+	if buf.String() != "{}\n" {
+		t.Fatalf("unexpected output for empty db: %q", buf.String())
+	}
+}
+
+// TestExportCfggen compares the Export of testdata/export/config_db.json
+// with testdata/export/print_data.json, the expected "sonic-cfggen -d
+// --print-data" output. It covers the natural sort of tables, keys and
+// fields, multi-part keys, leaf-lists and non-ASCII values. To regenerate
+// print_data.json, load config_db.json into an empty CONFIG_DB with
+// "sonic-cfggen -j config_db.json --write-to-db", and run sonic-cfggen.
+func TestExportCfggen(t *testing.T) {
+	useMemoryBackend(t)
+	d := openTestDB(t, ConfigDB, false)
+	data, err := readImportFile(filepath.Join("testdata", "export", "config_db.json"))
+	if err != nil {
+		t.Fatalf("readImportFile() failed; err=%v", err)
+	}
+	for table, entries := range data {
+		for key, value := range entries {
+			k := Key{Comp: strings.Split(key, "|")}
+			if err := d.SetEntry(&TableSpec{Name: table}, k, value); err != nil {
+				t.Fatalf("SetEntry(%s|%s) failed; err=%v", table, key, err)
+			}
+		}
+	}
+
+	f, err := d.Export(filepath.Join(t.TempDir(), "export.json"))
+	if err != nil {
+		t.Fatalf("Export() failed; err=%v", err)
+	}
+	exp, _ := ioutil.ReadFile(filepath.Join("testdata", "export", "print_data.json"))
+	if out, _ := ioutil.ReadFile(f); !bytes.Equal(out, exp) {
+		t.Fatalf("Export() output differs from sonic-cfggen;\nexpected:\n%s\nreceived:\n%s", exp, out)
+	}
+}
+
+func TestExportWithOptions(t *testing.T) {
+	useMemoryBackend(t)
+	d := openTestDB(t, ConfigDB, false)
+	for _, e := range []struct {
+		table, key string
+		fields     map[string]string
+	}{
+		{"PORT", "Ethernet0", map[string]string{"mtu": "9100", "admin_status": "up"}},
+		{"VLAN", "Vlan10", map[string]string{"vlanid": "10", "members@": "Ethernet0"}},
+		{"VLAN_INTERFACE", "Vlan10", map[string]string{"NULL": "NULL"}},
+	} {
+		ts := &TableSpec{Name: e.table}
+		if err := d.SetEntry(ts, *NewKey(e.key), Value{Field: e.fields}); err != nil {
+			t.Fatalf("SetEntry(%s|%s) failed; err=%v", e.table, e.key, err)
+		}
+	}
+
+	dir := t.TempDir()
+	export := func(opts *ExportOptions) string {
+		t.Helper()
+		f, err := d.ExportWithOptions(filepath.Join(dir, "config_*.json"), opts)
+		if err != nil {
+			t.Fatalf("ExportWithOptions(%+v) failed; err=%v", opts, err)
+		}
+		defer os.Remove(f)
+		data, _ := ioutil.ReadFile(f)
+		return string(data)
+	}
+
+	all := `{
+    "PORT": {
+        "Ethernet0": {
+            "admin_status": "up",
+            "mtu": "9100"
+        }
+    },
+    "VLAN": {
+        "Vlan10": {
+            "members": [
+                "Ethernet0"
+            ],
+            "vlanid": "10"
+        }
+    },
+    "VLAN_INTERFACE": {
+        "Vlan10": {}
+    }
+}
+`
+	if s := export(nil); s != all {
+		t.Fatalf("Export with default options returned:\n%s", s)
+	}
+
+	vlanOnly := `{
+    "VLAN_INTERFACE": {
+        "Vlan10": {}
+    }
+}
+`
+	opts := &ExportOptions{IncludeTables: []string{"VLAN", "VLAN_INTERFACE"}, ExcludeTables: []string{"VLAN"}}
+	if s := export(opts); s != vlanOnly {
+		t.Fatalf("Export with %+v returned:\n%s", opts, s)
+	}
+}
+
+// TestExportSystemTables checks the system generated tables of the sonic
+// yangs (models/yang/sonic), as compiled into the CVL schema.
+func TestExportSystemTables(t *testing.T) {
+	sysTables := cvl.GetSystemGeneratedTables()
+	if len(sysTables) == 0 {
+		t.Skip("No system generated tables in the yang")
+	}
+	if cvl.IsSystemGeneratedTable("PORT") {
+		t.Errorf("PORT is a system generated table")
+	}
+
+	useMemoryBackend(t)
+	d := openTestDB(t, ConfigDB, false)
+	for _, table := range append([]string{"PORT"}, sysTables...) {
+		ts := &TableSpec{Name: table}
+		if err := d.SetEntry(ts, *NewKey("k1"), Value{Field: map[string]string{"f": "v"}}); err != nil {
+			t.Fatalf("SetEntry(%s|k1) failed; err=%v", table, err)
+		}
+	}
+
+	tables := func(opts *ExportOptions) []string {
+		t.Helper()
+		f, err := d.ExportWithOptions(filepath.Join(t.TempDir(), "config_*.json"), opts)
+		if err != nil {
+			t.Fatalf("ExportWithOptions(%+v) failed; err=%v", opts, err)
+		}
+		var jData map[string]interface{}
+		if data, err := ioutil.ReadFile(f); err != nil || json.Unmarshal(data, &jData) != nil {
+			t.Fatalf("Invalid export file %s; err=%v", f, err)
+		}
+		var names []string
+		for name := range jData {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	if names := tables(nil); !reflect.DeepEqual(names, []string{"PORT"}) {
+		t.Errorf("Export with default options returned tables %v", names)
+	}
+	exp := append([]string{"PORT"}, sysTables...)
+	sort.Strings(exp)
+	if names := tables(&ExportOptions{SystemTables: true}); !reflect.DeepEqual(names, exp) {
+		t.Errorf("Export with SystemTables returned tables %v", names)
+	}
+}
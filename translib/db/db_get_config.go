@@ -70,6 +70,10 @@ func (d *DB) GetConfig(tables []*TableSpec, opt *GetConfigOptions) (map[TableSpe
 		glog.Warning("GetConfig: Per Connection Cache not supported")
 	}
 
+	if d.cpDs != nil {
+		return d.cpDs.getConfig(d, tables), nil
+	}
+
 	// Filter on tables: This is optimized for 1 table. Filtering on multiple
 	// tables can be optimized, however, it needs some glob pattern
 	// manufacturing feasibility. All tables is the only requirement currently,
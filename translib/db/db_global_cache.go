@@ -0,0 +1,445 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+//  Exported Types                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+// DBGlobalCache is the process-wide, read-through cache of the tables and
+// maps, shared by all the (write disabled) DB handles. It is kept coherent
+// with the keyspace notifications of a SubscribeDB, and is enabled by the
+// DBCacheConfig.Global. Each namespace has its own caches.
+type DBGlobalCache struct {
+	mu         sync.RWMutex
+	sMu        sync.Mutex                  // Serializes the (re)subscriptions
+	namespaces map[string]*gCacheNamespace // Namespace -> caches of its DBs
+}
+
+// DBGlobalCacheStats are the global cache statistics of a DB
+type DBGlobalCacheStats struct {
+	Hits          uint64  `json:"hits"`
+	Misses        uint64  `json:"misses"`
+	Invalidations uint64  `json:"invalidations"`
+	HitRatio      float64 `json:"hit-ratio"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Internal Types                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+// gCacheNamespace is the global cache of the DBs of a namespace
+type gCacheNamespace struct {
+	dbs   [MaxDB]dbCache
+	sDB   [MaxDB]*DB                // Keyspace notifications subscription
+	sNext [MaxDB]time.Time          // Earliest subscription retry
+	gen   [MaxDB]uint64             // Incremented on every invalidation
+	stats [MaxDB]DBGlobalCacheStats // Updated atomically
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Internal Functions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+var dbGlobalCache = &DBGlobalCache{namespaces: make(map[string]*gCacheNamespace)}
+
+// gCacheRetry is the wait before retrying a failed subscription. Till then
+// the reads go to redis.
+var gCacheRetry = 5 * time.Second
+
+// namespace returns the caches of the namespace, creating them if needed.
+// Called with the mu held.
+func (c *DBGlobalCache) namespace(ns string) *gCacheNamespace {
+	n, ok := c.namespaces[ns]
+	if !ok {
+		n = &gCacheNamespace{}
+		for dbNo := range n.dbs {
+			n.clear(DBNum(dbNo))
+		}
+		c.namespaces[ns] = n
+	}
+	return n
+}
+
+// subscribe makes sure the keyspace notifications of the DB are being
+// received, without which nothing may be cached.
+func (c *DBGlobalCache) subscribe(d *DB) bool {
+	ns, dbNo := d.Opts.Namespace, d.Opts.DBNo
+
+	c.mu.RLock()
+	n := c.namespaces[ns]
+	ok := n != nil && n.sDB[dbNo] != nil
+	c.mu.RUnlock()
+	if ok {
+		return true
+	}
+
+	c.sMu.Lock()
+	defer c.sMu.Unlock()
+
+	c.mu.Lock()
+	n = c.namespace(ns)
+	ok = n.sDB[dbNo] != nil
+	retry := n.sNext[dbNo]
+	c.mu.Unlock()
+	if ok || time.Now().Before(retry) {
+		return ok
+	}
+
+	sdb, e := SubscribeDB(Options{
+		DBNo:               dbNo,
+		Namespace:          ns,
+		TableNameSeparator: d.Opts.TableNameSeparator,
+		KeySeparator:       d.Opts.KeySeparator,
+	}, []*SKey{{Ts: &TableSpec{Name: "*"}, Key: &Key{}}}, c.handleNotification)
+
+	c.mu.Lock()
+	if e != nil {
+		glog.Warningf("DBGlobalCache: %s%v: SubscribeDB: %v", nsPrefix(ns), dbNo, e)
+		n.sNext[dbNo] = time.Now().Add(gCacheRetry)
+	} else {
+		n.sDB[dbNo] = sdb
+		n.clear(dbNo)
+	}
+	c.mu.Unlock()
+
+	return e == nil
+}
+
+// nsPrefix returns the namespace prefix for the log messages
+func nsPrefix(ns string) string {
+	if len(ns) == 0 {
+		return ""
+	}
+	return ns + "/"
+}
+
+// handleNotification invalidates the notified key. On a resync (i.e. missed
+// notifications), the DB cache is dropped. On losing the subscription, it is
+// dropped too, and is resubscribed on next use.
+func (c *DBGlobalCache) handleNotification(sdb *DB, skey *SKey, key *Key,
+	event SEvent) error {
+
+	ns, dbNo := sdb.Opts.Namespace, sdb.Opts.DBNo
+
+	switch event {
+	case SEventClose:
+		return nil
+	case SEventErr:
+		glog.Warningf("DBGlobalCache: %s%v: subscription lost", nsPrefix(ns), dbNo)
+		c.mu.Lock()
+		if n := c.namespaces[ns]; n != nil && n.sDB[dbNo] == sdb {
+			n.sDB[dbNo] = nil
+			n.clear(dbNo)
+		}
+		c.mu.Unlock()
+		sdb.UnsubscribeDB()
+		return nil
+	case SEventResync:
+		glog.Infof("DBGlobalCache: %s%v: resync", nsPrefix(ns), dbNo)
+		c.mu.Lock()
+		if n := c.namespaces[ns]; n != nil && n.sDB[dbNo] == sdb {
+			n.clear(dbNo)
+		}
+		c.mu.Unlock()
+		return nil
+	}
+
+	if len(key.Comp) != 0 {
+		c.invalidate(ns, dbNo, strings.Join(key.Comp,
+			sdb.Opts.TableNameSeparator), sdb.Opts.TableNameSeparator)
+	}
+	return nil
+}
+
+// invalidate drops the cached entry, the key patterns of its table, and
+// the map of the same name if any.
+func (c *DBGlobalCache) invalidate(ns string, dbNo DBNum, redisKey, sep string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := c.namespaces[ns]
+	if n == nil || n.sDB[dbNo] == nil {
+		return
+	}
+
+	n.gen[dbNo]++
+	atomic.AddUint64(&n.stats[dbNo].Invalidations, 1)
+
+	cache := &n.dbs[dbNo]
+	if tk := strings.SplitN(redisKey, sep, 2); len(tk) == 2 {
+		if table, ok := cache.Tables[tk[0]]; ok {
+			delete(table.entry, redisKey)
+			table.patterns = make(map[string][]Key, InitialTablePatternCount)
+			table.complete = false
+			cache.Tables[tk[0]] = table
+		}
+	}
+	delete(cache.Maps, redisKey)
+}
+
+// invalidateTx drops the entries written by the committed transaction,
+// ahead of their notifications.
+func (c *DBGlobalCache) invalidateTx(d *DB) {
+	for _, cmd := range d.txCmds {
+		c.invalidate(d.Opts.Namespace, d.Opts.DBNo,
+			d.key2redis(cmd.ts, *cmd.key), d.Opts.TableNameSeparator)
+	}
+}
+
+// clear drops the DB cache. Called with the mu held.
+func (n *gCacheNamespace) clear(dbNo DBNum) {
+	n.gen[dbNo]++
+	n.dbs[dbNo] = dbCache{
+		Tables: make(map[string]Table, InitialTablesCount),
+		Maps:   make(map[string]MAP, InitialMapsCount),
+	}
+}
+
+// clearAll drops all the caches, and their subscriptions.
+func (c *DBGlobalCache) clearAll() {
+	var sdbs []*DB
+
+	c.sMu.Lock()
+	c.mu.Lock()
+	for _, n := range c.namespaces {
+		for dbNo := range n.dbs {
+			if n.sDB[dbNo] != nil {
+				sdbs = append(sdbs, n.sDB[dbNo])
+				n.sDB[dbNo] = nil
+			}
+			n.sNext[dbNo] = time.Time{}
+			n.clear(DBNum(dbNo))
+		}
+	}
+	c.mu.Unlock()
+	c.sMu.Unlock()
+
+	for _, sdb := range sdbs {
+		sdb.UnsubscribeDB()
+	}
+}
+
+// lookup counts the hit or miss, and returns the generation to be passed to
+// the subsequent put on a miss.
+func (n *gCacheNamespace) lookup(dbNo DBNum, hit bool) (uint64, bool) {
+	if hit {
+		atomic.AddUint64(&n.stats[dbNo].Hits, 1)
+	} else {
+		atomic.AddUint64(&n.stats[dbNo].Misses, 1)
+	}
+	return n.gen[dbNo], hit
+}
+
+func (c *DBGlobalCache) getEntry(d *DB, ts *TableSpec, entry string) (Value, uint64, bool) {
+	if !c.subscribe(d) {
+		return Value{}, 0, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	n := c.namespaces[d.Opts.Namespace]
+	value, ok := n.dbs[d.Opts.DBNo].Tables[ts.Name].entry[entry]
+	if ok {
+		value = value.Copy()
+	}
+	gen, hit := n.lookup(d.Opts.DBNo, ok)
+	return value, gen, hit
+}
+
+func (c *DBGlobalCache) getKeys(d *DB, ts *TableSpec, pattern string) ([]Key, uint64, bool) {
+	if !c.subscribe(d) {
+		return nil, 0, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var keys []Key
+	n := c.namespaces[d.Opts.Namespace]
+	cKeys, ok := n.dbs[d.Opts.DBNo].Tables[ts.Name].patterns[pattern]
+	if ok {
+		keys = make([]Key, len(cKeys))
+		for i, key := range cKeys {
+			keys[i] = key.Copy()
+		}
+	}
+	gen, hit := n.lookup(d.Opts.DBNo, ok)
+	return keys, gen, hit
+}
+
+func (c *DBGlobalCache) getMap(d *DB, ts *TableSpec, mapKey string) (string, uint64, bool) {
+	if !c.subscribe(d) {
+		return "", 0, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	n := c.namespaces[d.Opts.Namespace]
+	v, ok := n.dbs[d.Opts.DBNo].Maps[ts.Name].mapMap[mapKey]
+	gen, hit := n.lookup(d.Opts.DBNo, ok)
+	return v, gen, hit
+}
+
+func (c *DBGlobalCache) getMapAll(d *DB, ts *TableSpec) (Value, uint64, bool) {
+	if !c.subscribe(d) {
+		return Value{}, 0, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var value Value
+	n := c.namespaces[d.Opts.Namespace]
+	mAP, ok := n.dbs[d.Opts.DBNo].Maps[ts.Name]
+	if ok = ok && mAP.complete; ok {
+		value = Value{Field: mAP.mapMap}.Copy()
+	}
+	gen, hit := n.lookup(d.Opts.DBNo, ok)
+	return value, gen, hit
+}
+
+// current returns the caches of the namespace of the DB, provided the cache
+// was not invalidated since the gen was read. Called with the mu held.
+func (c *DBGlobalCache) current(d *DB, gen uint64) (*gCacheNamespace, bool) {
+	n, dbNo := c.namespaces[d.Opts.Namespace], d.Opts.DBNo
+	if n == nil || n.sDB[dbNo] == nil || n.gen[dbNo] != gen {
+		return nil, false
+	}
+	return n, true
+}
+
+// table returns the cached table, creating it if needed, provided the cache
+// was not invalidated since the gen was read. Called with the mu held.
+func (c *DBGlobalCache) table(d *DB, ts *TableSpec, gen uint64) (Table, bool) {
+	n, ok := c.current(d, gen)
+	if !ok {
+		return Table{}, false
+	}
+	dbNo := d.Opts.DBNo
+	table, ok := n.dbs[dbNo].Tables[ts.Name]
+	if !ok {
+		table = Table{
+			ts:       ts,
+			entry:    make(map[string]Value, InitialTableEntryCount),
+			patterns: make(map[string][]Key, InitialTablePatternCount),
+		}
+		n.dbs[dbNo].Tables[ts.Name] = table
+	}
+	return table, true
+}
+
+func (c *DBGlobalCache) putEntry(d *DB, ts *TableSpec, entry string, value Value, gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if table, ok := c.table(d, ts, gen); ok {
+		table.entry[entry] = value.Copy()
+	}
+}
+
+func (c *DBGlobalCache) putKeys(d *DB, ts *TableSpec, pattern string, keys []Key, gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if table, ok := c.table(d, ts, gen); ok {
+		keysCopy := make([]Key, len(keys))
+		for i, key := range keys {
+			keysCopy[i] = key.Copy()
+		}
+		table.patterns[pattern] = keysCopy
+	}
+}
+
+func (c *DBGlobalCache) putMap(d *DB, ts *TableSpec, mapKey, v string, gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n, ok := c.current(d, gen)
+	if !ok {
+		return
+	}
+
+	dbNo := d.Opts.DBNo
+	mAP, ok := n.dbs[dbNo].Maps[ts.Name]
+	if !ok {
+		mAP = MAP{ts: ts, mapMap: make(map[string]string, InitialMapKeyCount)}
+		n.dbs[dbNo].Maps[ts.Name] = mAP
+	}
+	mAP.mapMap[mapKey] = v
+}
+
+func (c *DBGlobalCache) putMapAll(d *DB, ts *TableSpec, value Value, gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if n, ok := c.current(d, gen); ok {
+		n.dbs[d.Opts.DBNo].Maps[ts.Name] = MAP{ts: ts, complete: true,
+			mapMap: value.Copy().Field}
+	}
+}
+
+// getStats returns the global cache statistics of the DB of the namespace,
+// with the hit ratio.
+func (c *DBGlobalCache) getStats(ns string, dbNo DBNum) *DBGlobalCacheStats {
+	c.mu.RLock()
+	n := c.namespaces[ns]
+	c.mu.RUnlock()
+	if n == nil {
+		return nil
+	}
+
+	stats := DBGlobalCacheStats{
+		Hits:          atomic.LoadUint64(&n.stats[dbNo].Hits),
+		Misses:        atomic.LoadUint64(&n.stats[dbNo].Misses),
+		Invalidations: atomic.LoadUint64(&n.stats[dbNo].Invalidations),
+	}
+	if stats.Hits == 0 && stats.Misses == 0 && stats.Invalidations == 0 {
+		return nil
+	}
+	if lookups := stats.Hits + stats.Misses; lookups != 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(lookups)
+	}
+	return &stats
+}
+
+func (c *DBGlobalCache) clearStats() {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, n := range c.namespaces {
+		for dbNo := range n.stats {
+			atomic.StoreUint64(&n.stats[dbNo].Hits, 0)
+			atomic.StoreUint64(&n.stats[dbNo].Misses, 0)
+			atomic.StoreUint64(&n.stats[dbNo].Invalidations, 0)
+		}
+	}
+}
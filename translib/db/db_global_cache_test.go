@@ -0,0 +1,247 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+)
+
+// useGlobalCache enables the global cache for the duration of the test.
+func useGlobalCache(t *testing.T) {
+	prev := getDBCacheConfig()
+	t.Cleanup(func() {
+		mutexCacheConfig.Lock()
+		dbCacheConfig = &prev
+		mutexCacheConfig.Unlock()
+		ClearCache()
+	})
+	openTestClient(t, ConfigDB).HSet("TRANSLIB_DB|default", "global_cache", "True")
+	dbCacheConfig.handleReconfigureSignal()
+	ReconfigureCache()
+	ClearCache()
+	ClearDBStats()
+}
+
+// eventually polls f till it returns true, or a second has elapsed.
+func eventually(f func() bool) bool {
+	for i := 0; i < 100; i++ {
+		if f() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestGlobalCache(t *testing.T) {
+	useMemoryBackend(t)
+	useGlobalCache(t)
+	c := openTestClient(t, ConfigDB)
+	ts := &TableSpec{Name: "GCACHE_TEST"}
+	k1, k2 := *NewKey("k1"), *NewKey("k2")
+
+	w := openTestDB(t, ConfigDB, false)
+	if w.dbCacheConfig.Global {
+		t.Fatalf("Global cache enabled on a write enabled DB")
+	}
+	r1 := openTestDB(t, ConfigDB, true)
+	r2 := openTestDB(t, ConfigDB, true)
+	if !r1.dbCacheConfig.Global {
+		t.Fatalf("Global cache not enabled on a write disabled DB")
+	}
+
+	c.HSet("GCACHE_TEST|k1", "a", "1")
+	for _, d := range []*DB{r1, r1, r2} {
+		if v, e := d.GetEntry(ts, k1); e != nil || v.Get("a") != "1" {
+			t.Fatalf("GetEntry() = %v, %v; expected a=1", v, e)
+		}
+	}
+	if keys, e := r1.GetKeys(ts); e != nil || len(keys) != 1 {
+		t.Fatalf("GetKeys() = %v, %v; expected [k1]", keys, e)
+	}
+
+	// Changes by the other processes are seen via the notifications
+	c.HSet("GCACHE_TEST|k1", "a", "2")
+	if !eventually(func() bool {
+		v, _ := r2.GetEntry(ts, k1)
+		return v.Get("a") == "2"
+	}) {
+		t.Fatalf("GetEntry() stale after HSET")
+	}
+	c.HSet("GCACHE_TEST|k2", "x", "1")
+	if !eventually(func() bool {
+		keys, _ := r1.GetKeys(ts)
+		return len(keys) == 2
+	}) {
+		t.Fatalf("GetKeys() stale after HSET of new key")
+	}
+
+	// Changes by this process are seen at once
+	if v, e := r1.GetEntry(ts, k2); e != nil || v.Get("x") != "1" {
+		t.Fatalf("GetEntry(k2) = %v, %v", v, e)
+	}
+	w.ModEntry(ts, k2, Value{Field: map[string]string{"y": "2"}})
+	if v, e := r1.GetEntry(ts, k2); e != nil ||
+		!reflect.DeepEqual(v.Field, map[string]string{"x": "1", "y": "2"}) {
+		t.Fatalf("GetEntry(k2) = %v, %v; after ModEntry", v, e)
+	}
+	if e := w.StartTx(nil, nil); e != nil {
+		t.Fatalf("StartTx() fails e = %v", e)
+	}
+	w.DeleteEntry(ts, k2)
+	if e := w.CommitTx(); e != nil {
+		t.Fatalf("CommitTx() fails e = %v", e)
+	}
+	if v, e := r1.GetEntry(ts, k2); e == nil {
+		t.Fatalf("GetEntry(k2) = %v; after DeleteEntry", v)
+	}
+
+	c.Del("GCACHE_TEST|k1")
+	if !eventually(func() bool {
+		_, e := r2.GetEntry(ts, k1)
+		return e != nil
+	}) {
+		t.Fatalf("GetEntry() stale after DEL")
+	}
+
+	stats, _ := GetDBStats()
+	gs := stats.Databases[ConfigDB].GlobalCache
+	if gs == nil || gs.Hits == 0 || gs.Misses == 0 || gs.Invalidations == 0 ||
+		gs.HitRatio <= 0 || gs.HitRatio >= 1 {
+		t.Fatalf("GlobalCache stats = %+v", gs)
+	}
+}
+
+func TestGlobalCacheMap(t *testing.T) {
+	useMemoryBackend(t)
+	useGlobalCache(t)
+	c := openTestClient(t, ConfigDB)
+	ts := &TableSpec{Name: "GCACHE_MAP"}
+	r := openTestDB(t, ConfigDB, true)
+
+	c.HSet("GCACHE_MAP", "p1", "oid1")
+	for i := 0; i < 2; i++ {
+		if v, e := r.GetMap(ts, "p1"); e != nil || v != "oid1" {
+			t.Fatalf("GetMap() = %v, %v", v, e)
+		}
+		if v, e := r.GetMapAll(ts); e != nil || v.Get("p1") != "oid1" {
+			t.Fatalf("GetMapAll() = %v, %v", v, e)
+		}
+	}
+	if gs := dbGlobalCache.getStats("", ConfigDB); gs == nil || gs.Hits < 2 {
+		t.Fatalf("GlobalCache stats = %+v", gs)
+	}
+
+	c.HSet("GCACHE_MAP", "p2", "oid2")
+	if !eventually(func() bool {
+		v, _ := r.GetMapAll(ts)
+		return v.Get("p2") == "oid2"
+	}) {
+		t.Fatalf("GetMapAll() stale after HSET")
+	}
+}
+
+func TestGlobalCacheNoCacheTables(t *testing.T) {
+	useMemoryBackend(t)
+	useGlobalCache(t)
+	openTestClient(t, ConfigDB).HSet("TRANSLIB_DB|default",
+		"@no_tables_cache", "GCACHE_NO")
+	dbCacheConfig.handleReconfigureSignal()
+	ReconfigureCache()
+
+	r := openTestDB(t, ConfigDB, true)
+	r.GetEntry(&TableSpec{Name: "GCACHE_NO"}, *NewKey("k1"))
+	r.GetEntry(&TableSpec{Name: "GCACHE_NO"}, *NewKey("k1"))
+	if gs := dbGlobalCache.getStats("", ConfigDB); gs != nil {
+		t.Fatalf("GlobalCache stats = %+v; expected none", gs)
+	}
+}
+
+func TestGlobalCacheNamespaces(t *testing.T) {
+	setupTestNamespaces(t)
+	useMemoryBackend(t)
+	useGlobalCache(t)
+	ts := &TableSpec{Name: "GCACHE_TEST"}
+	k1 := *NewKey("k1")
+
+	var readers []*DB
+	for i, ns := range []string{"asic0", "asic1"} {
+		c := redis.NewClient(adjustRedisOpts(&Options{DBNo: ConfigDB, Namespace: ns}))
+		t.Cleanup(func() { c.Close() })
+		c.HSet("GCACHE_TEST|k1", "asic", strconv.Itoa(i))
+
+		d, err := NewDB(Options{
+			DBNo:               ConfigDB,
+			Namespace:          ns,
+			TableNameSeparator: "|",
+			KeySeparator:       "|",
+			IsWriteDisabled:    true,
+			DisableCVLCheck:    true,
+		})
+		if err != nil {
+			t.Fatalf("NewDB(%s) fails e = %v", ns, err)
+		}
+		t.Cleanup(func() { d.DeleteDB() })
+		if !d.dbCacheConfig.Global {
+			t.Fatalf("Global cache not enabled on %s", ns)
+		}
+		readers = append(readers, d)
+	}
+
+	// Each namespace is cached separately
+	for n := 0; n < 2; n++ {
+		for i, d := range readers {
+			if v, e := d.GetEntry(ts, k1); e != nil || v.Get("asic") != strconv.Itoa(i) {
+				t.Fatalf("%s GetEntry() = %v, %v", d.Opts.Namespace, v, e)
+			}
+		}
+	}
+
+	// Invalidation is by namespace too
+	w := newMemTestNsDB(t, "asic1")
+	w.ModEntry(ts, k1, Value{Field: map[string]string{"asic": "one"}})
+	if v, _ := readers[1].GetEntry(ts, k1); v.Get("asic") != "one" {
+		t.Fatalf("asic1 GetEntry() = %v; after ModEntry", v)
+	}
+	if v, _ := readers[0].GetEntry(ts, k1); v.Get("asic") != "0" {
+		t.Fatalf("asic0 GetEntry() = %v; after asic1 ModEntry", v)
+	}
+
+	stats, _ := GetDBStats()
+	for _, ns := range []string{"asic0", "asic1"} {
+		var gs *DBGlobalCacheStats
+		for _, dbs := range stats.Databases {
+			if dbs.Namespace == ns && dbs.Name == ConfigDB.String() {
+				gs = dbs.GlobalCache
+			}
+		}
+		if gs == nil || gs.Hits == 0 || gs.Misses == 0 {
+			t.Errorf("%s GlobalCache stats = %+v", ns, gs)
+		}
+	}
+	if gs := stats.Databases[ConfigDB].GlobalCache; gs != nil {
+		t.Errorf("Default namespace GlobalCache stats = %+v; expected none", gs)
+	}
+}
@@ -0,0 +1,271 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/Azure/sonic-mgmt-common/cvl"
+	"github.com/golang/glog"
+)
+
+// ImportMode indicates how Import() applies the config file contents to the DB.
+type ImportMode int
+
+const (
+	// ImportMerge creates new entries and updates fields of existing entries.
+	// Entries and fields not present in the file are retained.
+	ImportMerge ImportMode = iota
+	// ImportReplace makes the DB contents same as the file contents. Entries
+	// and fields not present in the file are deleted. Tables marked with
+	// sonic-extension:system-generated are retained if not present in the file.
+	ImportReplace
+)
+
+func (m ImportMode) String() string {
+	switch m {
+	case ImportMerge:
+		return "merge"
+	case ImportReplace:
+		return "replace"
+	}
+	return fmt.Sprintf("ImportMode(%d)", int(m))
+}
+
+// Import loads a config_db.json file (like the one generated by Export()) into
+// the DB. Changes are computed against the current DB contents and applied in
+// a single transaction; all of them are validated in the transaction's CVL
+// session. Deletes are performed first, child tables before parent tables;
+// followed by creates and updates, parent tables before child tables.
+// Table order is derived through cvl.SortDepTables().
+//
+// For session DBs, the changes are added to the session's transaction
+// (through a save point). Caller should commit it using CommitSessTx().
+func (d *DB) Import(filePath string, mode ImportMode) error {
+	if d.Opts.DBNo != ConfigDB {
+		return fmt.Errorf("Import not supported on %v", d.Opts.DBNo)
+	}
+	if mode != ImportMerge && mode != ImportReplace {
+		return fmt.Errorf("Invalid import mode %v", mode)
+	}
+
+	glog.Infof("Import: Begin: file: %s, mode: %v", filePath, mode)
+
+	data, err := readImportFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	if err = d.StartTx(nil, []*TableSpec{{Name: "*"}}); err != nil {
+		return err
+	}
+
+	if err = d.importData(data, mode); err != nil {
+		glog.Errorf("Import: %v", err)
+		d.AbortTx()
+		return err
+	}
+
+	err = d.CommitTx()
+	glog.Infof("Import: End: err: %v", err)
+	return err
+}
+
+// readImportFile reads the db json file into a map of table name to
+// entries, indexed by the key string.
+func readImportFile(filePath string) (map[string]map[string]Value, error) {
+	buff, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read import file: %w", err)
+	}
+
+	var jData map[string]map[string]map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(buff))
+	dec.UseNumber()
+	if err = dec.Decode(&jData); err != nil {
+		return nil, fmt.Errorf("Failed to parse import file: %w", err)
+	}
+
+	data := make(map[string]map[string]Value, len(jData))
+	for table, entries := range jData {
+		entryMap := make(map[string]Value, len(entries))
+		for key, fields := range entries {
+			value := Value{Field: make(map[string]string, len(fields))}
+			for name, v := range fields {
+				switch v := v.(type) {
+				case []interface{}: // leaf-list
+					items := make([]string, len(v))
+					for i, item := range v {
+						items[i] = fmt.Sprint(item)
+					}
+					value.Field[name+"@"] = strings.Join(items, ",")
+				case map[string]interface{}, nil:
+					return nil, fmt.Errorf("Invalid value for %s|%s field %s", table, key, name)
+				default:
+					value.Field[name] = fmt.Sprint(v)
+				}
+			}
+			if len(value.Field) == 0 {
+				value.Field["NULL"] = "NULL"
+			}
+			entryMap[key] = value
+		}
+		data[table] = entryMap
+	}
+
+	return data, nil
+}
+
+// importData applies the changes required to transform the current DB contents
+// to the given data. Should be called within a transaction.
+func (d *DB) importData(data map[string]map[string]Value, mode ImportMode) error {
+	tables, err := d.GetConfig(nil, &GetConfigOptions{AllowWritable: true})
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]Table, len(tables))
+	names := make([]string, 0, len(tables)+len(data))
+	for ts, table := range tables {
+		current[ts.Name] = table
+		if _, ok := data[ts.Name]; !ok && mode == ImportReplace &&
+			!cvl.IsSystemGeneratedTable(ts.Name) {
+			names = append(names, ts.Name)
+		}
+	}
+	for name := range data {
+		names = append(names, name)
+	}
+
+	order := d.importTableOrder(names)
+
+	// Delete stale entries, child tables first
+	for i := len(order) - 1; i >= 0 && mode == ImportReplace; i-- {
+		table, ok := current[order[i]]
+		if !ok {
+			continue
+		}
+		ts := &TableSpec{Name: order[i]}
+		entries := data[ts.Name]
+		for _, redisKey := range sortedKeys(table.entry) {
+			key := d.redis2key(ts, redisKey)
+			if _, ok := entries[strings.Join(key.Comp, d.Opts.KeySeparator)]; ok {
+				continue
+			}
+			if err = d.DeleteEntry(ts, key); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Create or update the entries, parent tables first
+	for _, name := range order {
+		entries, ok := data[name]
+		if !ok {
+			continue
+		}
+		ts := &TableSpec{Name: name}
+		table := current[name]
+		for _, k := range sortedKeys(entries) {
+			key := Key{Comp: strings.Split(k, d.Opts.KeySeparator)}
+			value := entries[k]
+			curValue, exists := table.entry[d.key2redis(ts, key)]
+			switch {
+			case !exists:
+				err = d.CreateEntry(ts, key, value)
+			case mode == ImportReplace:
+				if !curValue.Equals(&value) {
+					err = d.SetEntry(ts, key, value)
+				}
+			default:
+				if diff := modifiedFields(curValue, value); len(diff.Field) != 0 {
+					err = d.ModEntry(ts, key, diff)
+				}
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// importTableOrder sorts the tables as per their dependencies; parent tables
+// first. Tables unknown to CVL are placed at the end.
+func (d *DB) importTableOrder(names []string) []string {
+	sort.Strings(names)
+
+	var sorted []string
+	if d.cv != nil {
+		var status cvl.CVLRetCode
+		if sorted, status = d.cv.SortDepTables(names); status != cvl.CVL_SUCCESS {
+			glog.Warningf("Import: SortDepTables failed; status=%v", status)
+			sorted = nil
+		}
+	}
+
+	known := make(map[string]bool, len(sorted))
+	order := make([]string, 0, len(names))
+	for _, name := range sorted {
+		if !known[name] {
+			known[name] = true
+			order = append(order, name)
+		}
+	}
+	for _, name := range names {
+		if !known[name] {
+			order = append(order, name)
+		}
+	}
+	return order
+}
+
+// modifiedFields returns the fields of value which are not present in curValue
+// or have different values. The NULL placeholder field is ignored.
+func modifiedFields(curValue, value Value) Value {
+	diff := Value{Field: make(map[string]string)}
+	for name, v := range value.Field {
+		curV, ok := curValue.Field[name]
+		switch {
+		case name == "NULL":
+		case !ok:
+			diff.Field[name] = v
+		case curV == v:
+		case name[len(name)-1] != '@' || !leaflistEquals(curV, v):
+			diff.Field[name] = v
+		}
+	}
+	return diff
+}
+
+func sortedKeys(m map[string]Value) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,111 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestImport(t *testing.T) {
+	useMemoryBackend(t)
+	d := openTestDB(t, ConfigDB, false)
+	setup := map[string]map[string]string{
+		"PORT|Ethernet0":       {"mtu": "9100", "admin_status": "up"},
+		"PORT|Ethernet4":       {"mtu": "9100"},
+		"VLAN|Vlan10":          {"vlanid": "10", "members@": "Ethernet0,Ethernet4"},
+		"VLAN_INTERFACE|Vlan1": {"NULL": "NULL"},
+	}
+	for k, v := range setup {
+		ts, key := d.redis2ts_key(k)
+		if err := d.SetEntry(&ts, key, Value{Field: v}); err != nil {
+			t.Fatalf("SetEntry(%s) failed; err=%v", k, err)
+		}
+	}
+
+	cfgFile := filepath.Join(t.TempDir(), "config_db.json")
+	ioutil.WriteFile(cfgFile, []byte(`{
+		"PORT": {
+			"Ethernet0": {"mtu": "1500"},
+			"Ethernet8": {"mtu": 9100}
+		},
+		"VLAN": {
+			"Vlan10": {"vlanid": "10", "members": ["Ethernet4", "Ethernet0"]}
+		},
+		"VLAN_INTERFACE": {
+			"Vlan10": {}
+		}
+	}`), 0600)
+
+	verify := func(mode ImportMode, exp map[string]map[string]string) {
+		t.Helper()
+		if err := d.Import(cfgFile, mode); err != nil {
+			t.Fatalf("Import(%v) failed; err=%v", mode, err)
+		}
+		for _, table := range []string{"PORT", "VLAN", "VLAN_INTERFACE"} {
+			ts := &TableSpec{Name: table}
+			keys, _ := d.GetKeys(ts)
+			for _, key := range keys {
+				redisKey := d.key2redis(ts, key)
+				v, _ := d.GetEntry(ts, key)
+				if e, ok := exp[redisKey]; !ok {
+					t.Errorf("Import(%v) retained %s", mode, redisKey)
+				} else if !reflect.DeepEqual(v.Field, e) {
+					t.Errorf("Import(%v) %s = %v; expected %v", mode, redisKey, v.Field, e)
+				}
+				delete(exp, redisKey)
+			}
+		}
+		for k := range exp {
+			t.Errorf("Import(%v) did not create %s", mode, k)
+		}
+	}
+
+	verify(ImportMerge, map[string]map[string]string{
+		"PORT|Ethernet0":        {"mtu": "1500", "admin_status": "up"},
+		"PORT|Ethernet4":        {"mtu": "9100"},
+		"PORT|Ethernet8":        {"mtu": "9100"},
+		"VLAN|Vlan10":           {"vlanid": "10", "members@": "Ethernet0,Ethernet4"},
+		"VLAN_INTERFACE|Vlan1":  {"NULL": "NULL"},
+		"VLAN_INTERFACE|Vlan10": {"NULL": "NULL"},
+	})
+
+	verify(ImportReplace, map[string]map[string]string{
+		"PORT|Ethernet0":        {"mtu": "1500"},
+		"PORT|Ethernet8":        {"mtu": "9100"},
+		"VLAN|Vlan10":           {"vlanid": "10", "members@": "Ethernet0,Ethernet4"},
+		"VLAN_INTERFACE|Vlan10": {"NULL": "NULL"},
+	})
+}
+
+func TestImportInvalidFile(t *testing.T) {
+	useMemoryBackend(t)
+	d := openTestDB(t, ConfigDB, false)
+	cfgFile := filepath.Join(t.TempDir(), "config_db.json")
+	ioutil.WriteFile(cfgFile, []byte(`{"PORT": {"Ethernet0": {"mtu": {"x": "y"}}}}`), 0600)
+	if err := d.Import(cfgFile, ImportReplace); err == nil {
+		t.Fatalf("Import() with invalid field value did not fail")
+	}
+	if err := d.Import(filepath.Join(t.TempDir(), "none.json"), ImportMerge); err == nil {
+		t.Fatalf("Import() with non-existing file did not fail")
+	}
+}
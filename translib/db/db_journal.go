@@ -0,0 +1,642 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+// CONFIG_DB change journal. When enabled, each CONFIG_DB commit appends a
+// JournalRecord, with the before and after images of every entry it
+// modifies, to the redis stream CONFIG_DB_JOURNAL (in the same MULTI/EXEC
+// as the commit), or to a local file.
+//
+// While the journal is enabled, every CONFIG_DB write of a DB increments the
+// sequence number CONFIG_DB_JOURNAL_SEQ, also the ones which are not
+// journaled (writes outside a transaction). They leave a gap in the sequence
+// of the records, which GetConfigAt() does not go back across. While it is
+// disabled, the writes make no journal calls; when it is enabled (at the
+// process start, or again), the process increments the sequence once, for
+// the gap. GetConfigAt() does not
+// go back before the first record either. The writes of other (non translib)
+// processes are not seen.
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/sonic-mgmt-common/translib/tlerr"
+	"github.com/go-redis/redis/v7"
+	"github.com/golang/glog"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+//  Exported Types                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+// DBJournalConfig configures the CONFIG_DB change journal. It is read from
+// the "journal", "journal_file", and "journal_maxlen" fields of
+// TRANSLIB_DB|default.
+type DBJournalConfig struct {
+	Enabled bool   // Record the CONFIG_DB commits
+	File    string // Journal file. Empty == the CONFIG_DB_JOURNAL stream
+	MaxLen  int64  // Max records in the stream. 0 == No limit
+}
+
+// JournalOp is a HMSET, HDEL, or DEL of a commit, with the entry before and
+// after it. A nil Before/After is an absent entry.
+type JournalOp struct {
+	Op     string            `json:"op"`
+	Table  string            `json:"table"`
+	Key    []string          `json:"key"`
+	Before map[string]string `json:"before,omitempty"`
+	After  map[string]string `json:"after,omitempty"`
+}
+
+// JournalRecord is the journal record of a commit. Seq is the commit
+// sequence number, which increments by 1 for every journaled commit.
+type JournalRecord struct {
+	Seq     int64       `json:"seq"`
+	Time    time.Time   `json:"time"`
+	User    string      `json:"user,omitempty"`
+	Session string      `json:"session,omitempty"`
+	Ops     []JournalOp `json:"ops"`
+}
+
+// JournalFilter selects the journal records. The zero value selects all.
+type JournalFilter struct {
+	Tables []string  // Only the Ops on these tables
+	Key    *Key      // Only the Ops on the key (may be a pattern)
+	Since  time.Time // Records at, or after this time
+	Until  time.Time // Records before this time
+	User   string    // Only the records of this user
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Exported Functions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+func ReconfigureJournal() error {
+	return dbJournalConfig.reconfigure()
+}
+
+// ReadJournal returns the journal records selected by the filter, in the
+// order of their commit. If the filter has Tables or Key, only the matching
+// Ops of a record are returned, and the records without them are skipped.
+// Only supported on the CONFIG_DB.
+func (d *DB) ReadJournal(filter *JournalFilter) ([]JournalRecord, error) {
+	if d.Opts.DBNo != ConfigDB {
+		return nil, SupportsCfgDBOnly
+	}
+	if filter == nil {
+		filter = &JournalFilter{}
+	}
+
+	var recs []JournalRecord
+	var err error
+	if len(d.dbJournalConfig.File) != 0 {
+		recs, err = readJournalFile(d.dbJournalConfig.File, filter)
+	} else {
+		recs, err = d.readJournalStream(filter)
+	}
+	if err != nil {
+		glog.Errorf("ReadJournal: %v", err)
+	}
+	return recs, err
+}
+
+// GetConfigAt is GetConfig, as of the time at. It is reconstructed by
+// undoing the journal records of the later commits, with their before
+// images. Returns an error if the journal does not cover the time, i.e.
+// it is disabled or empty, the time is before the first record, or there
+// were writes, which are not journaled, since the time.
+func (d *DB) GetConfigAt(at time.Time, tables []*TableSpec, opt *GetConfigOptions) (map[TableSpec]Table, error) {
+	if !d.dbJournalConfig.Enabled {
+		return nil, tlerr.TranslibDBNotSupported{Description: "Journal is disabled"}
+	}
+	tblM, err := d.GetConfig(tables, opt)
+	if err != nil {
+		return nil, err
+	}
+	recs, err := d.ReadJournal(nil)
+	if err != nil {
+		return nil, err
+	}
+	if err = d.checkJournalCoverage(recs, at); err != nil {
+		glog.Errorf("GetConfigAt: %v", err)
+		return nil, err
+	}
+
+	first := len(recs) // First record after the time at
+	for i := range recs {
+		if recs[i].Time.After(at) {
+			first = i
+			break
+		}
+	}
+
+	tsM := make(map[string]bool, len(tables))
+	for _, ts := range tables {
+		tsM[ts.Name] = true
+	}
+	for i := len(recs) - 1; i >= first; i-- {
+		for j := len(recs[i].Ops) - 1; j >= 0; j-- {
+			op := &recs[i].Ops[j]
+			if len(tsM) != 0 && !tsM[op.Table] {
+				continue
+			}
+			ts := TableSpec{Name: op.Table}
+			tbl, ok := tblM[ts]
+			if !ok {
+				tbl = Table{ts: &ts, entry: make(map[string]Value),
+					complete: true, db: d}
+				tblM[ts] = tbl
+			}
+			entry := d.key2redis(&ts, Key{Comp: op.Key})
+			if len(op.Before) == 0 {
+				delete(tbl.entry, entry)
+			} else {
+				tbl.entry[entry] = Value{Field: op.Before}.Copy()
+			}
+		}
+	}
+
+	for ts, tbl := range tblM {
+		if len(tbl.entry) == 0 {
+			delete(tblM, ts)
+		}
+	}
+	return tblM, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Internal Functions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+// checkJournalCoverage returns an error, if the journal records (all of
+// them) do not have every CONFIG_DB write since the time at. i.e. the time
+// is before the first record, there is a gap in their sequence after the
+// time, or the last one is not the current sequence number.
+func (d *DB) checkJournalCoverage(recs []JournalRecord, at time.Time) error {
+	if len(recs) == 0 {
+		return tlerr.TranslibDBNotSupported{Description: "Journal is empty"}
+	}
+	d.markJournalRestart()
+
+	glog.Info("checkJournalCoverage: RedisCmd: ", d.Name(), ": GET ", journalSeqKey)
+	seq, err := d.client.Get(journalSeqKey).Int64()
+	if err != nil {
+		return err
+	}
+	if last := recs[len(recs)-1].Seq; last != seq {
+		return tlerr.TranslibDBNotSupported{Description: "Journal has a gap " +
+			"after seq " + strconv.FormatInt(last, 10)}
+	}
+
+	first := len(recs) - 1 // Last record at, or before the time at
+	for first >= 0 && recs[first].Time.After(at) {
+		first--
+	}
+	if first < 0 {
+		return tlerr.TranslibDBNotSupported{
+			Description: "Journal does not go back to " + at.String()}
+	}
+	for i := first + 1; i < len(recs); i++ {
+		if i > 0 && recs[i].Seq != recs[i-1].Seq+1 {
+			return tlerr.TranslibDBNotSupported{Description: "Journal has a gap " +
+				"after seq " + strconv.FormatInt(recs[i-1].Seq, 10)}
+		}
+	}
+	return nil
+}
+
+// markJournalGap increments the journal sequence number, for a CONFIG_DB
+// write, which is not journaled. In a transaction, it is queued in the MULTI.
+// Nothing is done while the journal is disabled.
+func (d *DB) markJournalGap() error {
+	if !d.dbJournalConfig.Enabled {
+		return nil
+	}
+	glog.Info("markJournalGap: RedisCmd: ", d.Name(), ": INCR ", journalSeqKey)
+	e := d.client.Do("INCR", journalSeqKey).Err()
+	if e != nil {
+		glog.Warning("markJournalGap: INCR e: ", e)
+	}
+	return e
+}
+
+// markJournalRestart increments the journal sequence number, once in each
+// namespace, after the journal is enabled in this process (first, or again).
+// It marks the gap of the writes made while it was disabled, before the
+// first journal write, or coverage check.
+func (d *DB) markJournalRestart() {
+	ns := d.Opts.Namespace
+	mutexJournalConfig.Lock()
+	restarts, marked := journalRestarts, journalRestartsMarked[ns]
+	mutexJournalConfig.Unlock()
+	if marked == restarts {
+		return
+	}
+
+	glog.Info("markJournalRestart: RedisCmd: ", d.Name(), ": INCR ", journalSeqKey)
+	if e := d.client.Do("INCR", journalSeqKey).Err(); e != nil {
+		glog.Warning("markJournalRestart: INCR e: ", e)
+		return
+	}
+	mutexJournalConfig.Lock()
+	journalRestartsMarked[ns] = restarts
+	mutexJournalConfig.Unlock()
+}
+
+const (
+	journalStream string = "CONFIG_DB_JOURNAL"
+	journalSeqKey string = "CONFIG_DB_JOURNAL_SEQ"
+
+	journalReadCount int64 = 1000 // Records per XRANGE
+)
+
+var dbJournalConfig *DBJournalConfig
+var defaultDBJournalConfig DBJournalConfig = DBJournalConfig{
+	Enabled: false,
+}
+var reconfigureJournalConfig bool
+var mutexJournalConfig sync.Mutex
+
+// journalRestarts is the number of times the journal was disabled, after
+// being enabled, in this process, plus the process start. journalRestartsMarked
+// is the number marked by markJournalRestart, in each namespace.
+var journalRestarts int = 1
+var journalRestartsMarked = make(map[string]int)
+
+var mutexJournalFile sync.Mutex
+
+func init() {
+	dbJournalConfig = &DBJournalConfig{}
+	dbJournalConfig.handleReconfigureSignal()
+
+	RegisterRuntimeConfig(RuntimeConfigComponent[DBJournalConfig]{
+		Name: "db_journal",
+		Parse: func(fields map[string]string) DBJournalConfig {
+			var config DBJournalConfig
+			config.parseFields(fields)
+			return config
+		},
+		OnChange: func(_, config DBJournalConfig) { setDBJournalConfig(config) },
+		Current:  getDBJournalConfig,
+	})
+}
+
+func getDBJournalConfig() DBJournalConfig {
+	dbJournalConfig.reconfigure()
+	mutexJournalConfig.Lock()
+	journalConfig := *dbJournalConfig
+	mutexJournalConfig.Unlock()
+	return journalConfig
+}
+
+func (config *DBJournalConfig) reconfigure() error {
+	mutexJournalConfig.Lock()
+	var doReconfigure bool = reconfigureJournalConfig
+	if reconfigureJournalConfig {
+		reconfigureJournalConfig = false
+	}
+	mutexJournalConfig.Unlock()
+
+	if doReconfigure {
+		var readDBJournalConfig DBJournalConfig
+		readDBJournalConfig.readFromDB()
+		setDBJournalConfig(readDBJournalConfig)
+	}
+	return nil
+}
+
+func setDBJournalConfig(config DBJournalConfig) {
+	mutexJournalConfig.Lock()
+	if !reflect.DeepEqual(*dbJournalConfig, config) {
+		glog.Infof("DBJournalConfig: %+v", config)
+	}
+	if dbJournalConfig.Enabled && !config.Enabled {
+		journalRestarts++
+	}
+	dbJournalConfig = &config
+	mutexJournalConfig.Unlock()
+}
+
+func (config *DBJournalConfig) handleReconfigureSignal() error {
+	mutexJournalConfig.Lock()
+	reconfigureJournalConfig = true
+	mutexJournalConfig.Unlock()
+	return nil
+}
+
+func (config *DBJournalConfig) readFromDB() error {
+	fields, e := readRuntimeConfig()
+	if e != nil {
+		*config = defaultDBJournalConfig
+	} else {
+		config.parseFields(fields)
+	}
+	return e
+}
+
+// parseFields sets the config from the TRANSLIB_DB|default fields
+func (config *DBJournalConfig) parseFields(fields map[string]string) {
+	for k, v := range fields {
+		switch {
+		case k == "journal" && v == "True":
+			config.Enabled = true
+		case k == "journal" && v == "False":
+			config.Enabled = false
+		case k == "journal_file":
+			config.File = v
+		case k == "journal_maxlen":
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil && n >= 0 {
+				config.MaxLen = n
+			}
+		}
+	}
+}
+
+// journalOps returns the JournalOps of the txCmds, if the journal is enabled.
+// The modified keys are WATCHed, so that the before images read here are
+// the ones at the commit.
+func (d *DB) journalOps() ([]JournalOp, error) {
+	if !d.dbJournalConfig.Enabled || d.Opts.DBNo != ConfigDB ||
+		len(d.txCmds) == 0 {
+		return nil, nil
+	}
+	d.markJournalRestart()
+
+	redisKeys := make([]string, 0, len(d.txCmds))
+	images := make(map[string]map[string]string, len(d.txCmds))
+	for _, cmd := range d.txCmds {
+		redisKey := d.key2redis(cmd.ts, *cmd.key)
+		if _, ok := images[redisKey]; !ok {
+			images[redisKey] = nil
+			redisKeys = append(redisKeys, redisKey)
+		}
+	}
+
+	args := make([]interface{}, 0, len(redisKeys)+1)
+	args = append(args, "WATCH")
+	for _, redisKey := range redisKeys {
+		args = append(args, redisKey)
+	}
+	glog.Info("journalOps: RedisCmd: ", d.Name(), ": WATCH #keys ", len(redisKeys))
+	if e := d.client.Do(args...).Err(); e != nil {
+		glog.Warning("journalOps: WATCH e: ", e)
+		return nil, e
+	}
+
+	pipe := d.client.Pipeline()
+	results := make([]*redis.StringStringMapCmd, len(redisKeys))
+	for i, redisKey := range redisKeys {
+		results[i] = pipe.HGetAll(redisKey)
+	}
+	_, e := pipe.Exec()
+	pipe.Close()
+	if e != nil {
+		glog.Warning("journalOps: HGETALL e: ", e)
+		return nil, e
+	}
+	for i, redisKey := range redisKeys {
+		if v := results[i].Val(); len(v) != 0 {
+			images[redisKey] = v
+		}
+	}
+
+	ops := make([]JournalOp, 0, len(d.txCmds))
+	for _, cmd := range d.txCmds {
+		redisKey := d.key2redis(cmd.ts, *cmd.key)
+		before := images[redisKey]
+		var after map[string]string
+		switch cmd.op {
+		case txOpHMSet, txOpHDel:
+			after = make(map[string]string, len(before)+len(cmd.value.Field))
+			for f, v := range before {
+				after[f] = v
+			}
+			for f, v := range cmd.value.Field {
+				if cmd.op == txOpHMSet {
+					after[f] = v
+				} else {
+					delete(after, f)
+				}
+			}
+			if len(after) == 0 {
+				after = nil
+			}
+		}
+		ops = append(ops, JournalOp{
+			Op:     getOperationName(cmd.op),
+			Table:  cmd.ts.Name,
+			Key:    append([]string(nil), cmd.key.Comp...),
+			Before: before,
+			After:  after,
+		})
+		images[redisKey] = after
+	}
+	return ops, nil
+}
+
+// queueJournal queues (in the MULTI) the luaScriptJournal, which assigns
+// the commit sequence number, and adds the record to the stream.
+func (d *DB) queueJournal(rec *JournalRecord) error {
+	data, e := json.Marshal(rec)
+	if e != nil {
+		return e
+	}
+	args := []interface{}{"EVAL", luaScriptJournal, 1, journalSeqKey}
+	if len(d.dbJournalConfig.File) == 0 {
+		args = []interface{}{"EVAL", luaScriptJournal, 2, journalSeqKey,
+			journalStream}
+	}
+	args = append(args, string(data), d.dbJournalConfig.MaxLen)
+	glog.Info("CommitTx: Do: EVAL luaScriptJournal: #ops ", len(rec.Ops))
+	_, e = d.client.Do(args...).Result()
+	return e
+}
+
+// recordJournal completes the journal record of the commit, with the
+// sequence number from the EXEC reply (of luaScriptJournal, the last
+// command), and appends it to the journal file (if configured).
+func (d *DB) recordJournal(rec *JournalRecord, execReply interface{}) {
+	if replies, ok := execReply.([]interface{}); ok && len(replies) != 0 {
+		if seq, ok := replies[len(replies)-1].(int64); ok {
+			rec.Seq = seq
+		} else {
+			glog.Errorf("recordJournal: luaScriptJournal failed: %v",
+				replies[len(replies)-1])
+			return
+		}
+	}
+	if len(d.dbJournalConfig.File) == 0 {
+		return
+	}
+
+	data, e := json.Marshal(rec)
+	if e == nil {
+		mutexJournalFile.Lock()
+		e = appendFile(d.dbJournalConfig.File, append(data, '\n'))
+		mutexJournalFile.Unlock()
+	}
+	if e != nil {
+		glog.Errorf("recordJournal: %s: seq %d: %v", d.dbJournalConfig.File,
+			rec.Seq, e)
+	}
+}
+
+func appendFile(fileName string, data []byte) error {
+	f, e := os.OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0640)
+	if e != nil {
+		return e
+	}
+	if _, e = f.Write(data); e != nil {
+		f.Close()
+		return e
+	}
+	return f.Close()
+}
+
+func readJournalFile(fileName string, filter *JournalFilter) ([]JournalRecord, error) {
+	f, e := os.Open(fileName)
+	if os.IsNotExist(e) {
+		return nil, nil
+	} else if e != nil {
+		return nil, e
+	}
+	defer f.Close()
+
+	var recs []JournalRecord
+	r := bufio.NewReader(f)
+	for {
+		line, e := r.ReadBytes('\n')
+		if e == io.EOF {
+			// A partial last line is a record being written
+			return recs, nil
+		} else if e != nil {
+			return recs, e
+		}
+		var rec JournalRecord
+		if e = json.Unmarshal(line, &rec); e != nil {
+			return recs, e
+		}
+		if filter.match(&rec) {
+			recs = append(recs, rec)
+		}
+	}
+}
+
+func (d *DB) readJournalStream(filter *JournalFilter) ([]JournalRecord, error) {
+	start, end := "-", "+"
+	if !filter.Since.IsZero() {
+		start = timeMs(filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		end = timeMs(filter.Until)
+	}
+
+	var recs []JournalRecord
+	for {
+		glog.Info("ReadJournal: RedisCmd: ", d.Name(), ": XRANGE ",
+			journalStream, " ", start, " ", end)
+		msgs, e := d.client.XRangeN(journalStream, start, end,
+			journalReadCount).Result()
+		if e != nil {
+			return recs, e
+		}
+		for _, msg := range msgs {
+			var rec JournalRecord
+			data, _ := msg.Values["record"].(string)
+			if e = json.Unmarshal([]byte(data), &rec); e != nil {
+				return recs, e
+			}
+			seq, _ := msg.Values["seq"].(string)
+			rec.Seq, _ = strconv.ParseInt(seq, 10, 64)
+			if filter.match(&rec) {
+				recs = append(recs, rec)
+			}
+		}
+		if int64(len(msgs)) < journalReadCount {
+			return recs, nil
+		}
+
+		// Next page starts after the last ID
+		last := strings.SplitN(msgs[len(msgs)-1].ID, "-", 2)
+		seq, _ := strconv.ParseInt(last[len(last)-1], 10, 64)
+		start = last[0] + "-" + strconv.FormatInt(seq+1, 10)
+	}
+}
+
+// match checks whether the record is selected by the filter, and filters
+// its Ops on the Tables and Key.
+func (filter *JournalFilter) match(rec *JournalRecord) bool {
+	if (!filter.Since.IsZero() && rec.Time.Before(filter.Since)) ||
+		(!filter.Until.IsZero() && !rec.Time.Before(filter.Until)) ||
+		(len(filter.User) != 0 && filter.User != rec.User) {
+		return false
+	}
+	if len(filter.Tables) == 0 && filter.Key == nil {
+		return true
+	}
+
+	ops := rec.Ops[:0]
+	for _, op := range rec.Ops {
+		if len(filter.Tables) != 0 && !contains(filter.Tables, op.Table) {
+			continue
+		}
+		if filter.Key != nil && !(Key{Comp: op.Key}).Matches(*filter.Key) {
+			continue
+		}
+		ops = append(ops, op)
+	}
+	rec.Ops = ops
+	return len(ops) != 0
+}
+
+func contains(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// luaScriptJournal increments the commit sequence number KEYS[1], and adds
+// the record ARGV[1] to the stream KEYS[2] (if present), trimming it to
+// ARGV[2] (if > 0) records. The trimming is exact, not "MAXLEN ~", which
+// trims only whole stream nodes. Returns the sequence number.
+var luaScriptJournal = `
+	local seq = redis.call("INCR", KEYS[1])
+	if #KEYS > 1 then
+		local maxLen = tonumber(ARGV[2])
+		if maxLen > 0 then
+			redis.call("XADD", KEYS[2], "MAXLEN", maxLen, "*",
+				"seq", seq, "record", ARGV[1])
+		else
+			redis.call("XADD", KEYS[2], "*", "seq", seq, "record", ARGV[1])
+		end
+	end
+	return seq
+`
@@ -0,0 +1,273 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// useJournal configures the journal in TRANSLIB_DB|default of the current
+// backend, for the duration of the test. The journal starts empty; its
+// first write marks the (re)start, with the sequence number 1.
+func useJournal(t *testing.T, fields map[string]interface{}) {
+	prev := getDBJournalConfig()
+	t.Cleanup(func() {
+		mutexJournalConfig.Lock()
+		dbJournalConfig = &prev
+		mutexJournalConfig.Unlock()
+	})
+	mutexJournalConfig.Lock()
+	journalRestartsMarked = make(map[string]int)
+	mutexJournalConfig.Unlock()
+	client := openTestClient(t, ConfigDB)
+	client.Del(journalSeqKey, journalStream)
+	client.HMSet("TRANSLIB_DB|default", fields)
+	dbJournalConfig.handleReconfigureSignal()
+	ReconfigureJournal()
+}
+
+// journalCommits makes 2 commits on the table; returns the times before
+// each of them.
+func journalCommits(t *testing.T, d *DB, ts *TableSpec) (time.Time, time.Time) {
+	t.Helper()
+	k1, k2 := *NewKey("k1"), *NewKey("k2")
+	commit := func(ops func()) time.Time {
+		t.Helper()
+		before := time.Now()
+		time.Sleep(2 * time.Millisecond)
+		if err := d.StartTx(nil, nil); err != nil {
+			t.Fatalf("StartTx() fails e = %v", err)
+		}
+		ops()
+		if err := d.CommitTx(); err != nil {
+			t.Fatalf("CommitTx() fails e = %v", err)
+		}
+		time.Sleep(2 * time.Millisecond)
+		return before
+	}
+
+	t0 := commit(func() {
+		d.SetEntry(ts, k1, Value{Field: map[string]string{"a": "1"}})
+		d.SetEntry(ts, k2, Value{Field: map[string]string{"x": "1"}})
+	})
+	t1 := commit(func() {
+		d.ModEntry(ts, k1, Value{Field: map[string]string{"b": "2"}})
+		d.DeleteEntryFields(ts, k1, Value{Field: map[string]string{"a": ""}})
+		d.DeleteEntry(ts, k2)
+	})
+	return t0, t1
+}
+
+func TestJournal(t *testing.T) {
+	onTestBackends(t, testJournal)
+}
+
+func testJournal(t *testing.T) {
+	useJournal(t, map[string]interface{}{"journal": "True"})
+	d := openTestDB(t, ConfigDB, false)
+	d.Opts.User = "admin"
+	ts := &TableSpec{Name: "JOURNAL_TEST"}
+	t0, t1 := journalCommits(t, d, ts)
+
+	recs, err := d.ReadJournal(nil)
+	if err != nil || len(recs) != 2 {
+		t.Fatalf("ReadJournal() = %+v, %v", recs, err)
+	}
+	if recs[0].Seq != 2 || recs[1].Seq != 3 || recs[1].User != "admin" ||
+		recs[1].Time.Before(t1) {
+		t.Fatalf("ReadJournal() = %+v", recs)
+	}
+	expOps := []JournalOp{
+		{Op: "HMSET", Table: ts.Name, Key: []string{"k1"},
+			Before: map[string]string{"a": "1"},
+			After:  map[string]string{"a": "1", "b": "2"}},
+		{Op: "HDEL", Table: ts.Name, Key: []string{"k1"},
+			Before: map[string]string{"a": "1", "b": "2"},
+			After:  map[string]string{"b": "2"}},
+		{Op: "DEL", Table: ts.Name, Key: []string{"k2"},
+			Before: map[string]string{"x": "1"}},
+	}
+	if !reflect.DeepEqual(recs[1].Ops, expOps) {
+		t.Fatalf("ReadJournal() Ops = %+v; expected %+v", recs[1].Ops, expOps)
+	}
+
+	// Filters
+	for _, tc := range []struct {
+		name   string
+		filter JournalFilter
+		seqs   []int64
+		numOps int
+	}{
+		{"since", JournalFilter{Since: t1}, []int64{3}, 3},
+		{"until", JournalFilter{Until: t1}, []int64{2}, -1},
+		{"user", JournalFilter{User: "admin"}, []int64{2, 3}, -1},
+		{"otherUser", JournalFilter{User: "guest"}, nil, 0},
+		{"otherTable", JournalFilter{Tables: []string{"OTHER"}}, nil, 0},
+		{"key", JournalFilter{Tables: []string{ts.Name}, Key: NewKey("k2")}, []int64{2, 3}, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			recs, err := d.ReadJournal(&tc.filter)
+			if err != nil || len(recs) != len(tc.seqs) {
+				t.Fatalf("ReadJournal() = %+v, %v", recs, err)
+			}
+			for i, rec := range recs {
+				if rec.Seq != tc.seqs[i] || (tc.numOps >= 0 && len(rec.Ops) != tc.numOps) {
+					t.Fatalf("ReadJournal() = %+v", recs)
+				}
+			}
+		})
+	}
+
+	// Point in time
+	opt := &GetConfigOptions{AllowWritable: true}
+	cfg, err := d.GetConfigAt(t1, []*TableSpec{ts}, opt)
+	if err != nil {
+		t.Fatalf("GetConfigAt() fails e = %v", err)
+	}
+	exp := map[string]Value{
+		"JOURNAL_TEST|k1": {Field: map[string]string{"a": "1"}},
+		"JOURNAL_TEST|k2": {Field: map[string]string{"x": "1"}},
+	}
+	if tbl := cfg[TableSpec{Name: ts.Name}]; !reflect.DeepEqual(tbl.entry, exp) {
+		t.Fatalf("GetConfigAt(t1) = %v; expected %v", tbl.entry, exp)
+	}
+	// Before the first record
+	if cfg, err = d.GetConfigAt(t0, []*TableSpec{ts}, opt); err == nil {
+		t.Fatalf("GetConfigAt(t0) = %v; expected to fail", cfg)
+	}
+}
+
+func TestJournalMaxLen(t *testing.T) {
+	onTestBackends(t, testJournalMaxLen)
+}
+
+func testJournalMaxLen(t *testing.T) {
+	useJournal(t, map[string]interface{}{"journal": "True", "journal_maxlen": "1"})
+	d := openTestDB(t, ConfigDB, false)
+	ts := &TableSpec{Name: "JOURNAL_TEST"}
+	t0, t1 := journalCommits(t, d, ts)
+
+	if recs, err := d.ReadJournal(nil); err != nil || len(recs) != 1 || recs[0].Seq != 3 {
+		t.Fatalf("ReadJournal() = %+v, %v", recs, err)
+	}
+	opt := &GetConfigOptions{AllowWritable: true}
+	if _, err := d.GetConfigAt(time.Now(), []*TableSpec{ts}, opt); err != nil {
+		t.Fatalf("GetConfigAt(now) fails e = %v", err)
+	}
+	// Whether the trimmed commit was before t1 is not known
+	for _, at := range []time.Time{t0, t1} {
+		if _, err := d.GetConfigAt(at, []*TableSpec{ts}, opt); err == nil {
+			t.Fatalf("GetConfigAt(%v) did not fail with a trimmed journal", at)
+		}
+	}
+}
+
+func TestJournalCoverage(t *testing.T) {
+	onTestBackends(t, testJournalCoverage)
+}
+
+func testJournalCoverage(t *testing.T) {
+	useJournal(t, map[string]interface{}{"journal": "True"})
+	d := openTestDB(t, ConfigDB, false)
+	ts := &TableSpec{Name: "JOURNAL_TEST"}
+	opt := &GetConfigOptions{AllowWritable: true}
+	getConfigAt := func(at time.Time) error {
+		_, err := d.GetConfigAt(at, []*TableSpec{ts}, opt)
+		return err
+	}
+	getSeq := func() string {
+		return openTestClient(t, ConfigDB).Get(journalSeqKey).Val()
+	}
+
+	if err := getConfigAt(time.Now()); err == nil {
+		t.Fatalf("GetConfigAt() did not fail with an empty journal")
+	}
+	t0, t1 := journalCommits(t, d, ts)
+
+	// Commits with the journal disabled make no journal calls
+	setDBJournalConfig(DBJournalConfig{})
+	d = openTestDB(t, ConfigDB, false)
+	if err := getConfigAt(time.Now()); err == nil {
+		t.Fatalf("GetConfigAt() did not fail with the journal disabled")
+	}
+	t2, _ := journalCommits(t, d, ts)
+	if seq := getSeq(); seq != "3" {
+		t.Fatalf("%s = %q with the journal disabled; expected 3", journalSeqKey, seq)
+	}
+
+	// Enabled again; the restart is marked, before the first check
+	setDBJournalConfig(DBJournalConfig{Enabled: true})
+	d = openTestDB(t, ConfigDB, false)
+	for _, at := range []time.Time{t0, t1, t2, time.Now()} {
+		if err := getConfigAt(at); err == nil {
+			t.Fatalf("GetConfigAt(%v) did not fail across the disabled journal", at)
+		}
+	}
+	if seq := getSeq(); seq != "4" {
+		t.Fatalf("%s = %q after the restart; expected 4", journalSeqKey, seq)
+	}
+	t3, t4 := journalCommits(t, d, ts)
+	if recs, err := d.ReadJournal(nil); err != nil || len(recs) != 4 ||
+		recs[1].Seq != 3 || recs[2].Seq != 5 {
+		t.Fatalf("ReadJournal() = %+v, %v", recs, err)
+	}
+	if err := getConfigAt(t3); err == nil {
+		t.Fatalf("GetConfigAt(t3) did not fail across the disabled journal")
+	}
+	if err := getConfigAt(t4); err != nil {
+		t.Fatalf("GetConfigAt(t4) fails e = %v", err)
+	}
+
+	// A write outside a transaction
+	if err := d.SetEntry(ts, *NewKey("k3"), Value{Field: map[string]string{"a": "1"}}); err != nil {
+		t.Fatalf("SetEntry() fails e = %v", err)
+	}
+	for _, at := range []time.Time{t4, time.Now()} {
+		if err := getConfigAt(at); err == nil {
+			t.Fatalf("GetConfigAt(%v) did not fail after a non-journaled write", at)
+		}
+	}
+}
+
+func TestJournalFile(t *testing.T) {
+	onTestBackends(t, testJournalFile)
+}
+
+func testJournalFile(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "journal.json")
+	useJournal(t, map[string]interface{}{"journal": "True", "journal_file": fileName})
+	d := openTestDB(t, ConfigDB, false)
+	ts := &TableSpec{Name: "JOURNAL_TEST"}
+	_, t1 := journalCommits(t, d, ts)
+
+	if recs, err := d.ReadJournal(nil); err != nil || len(recs) != 2 ||
+		recs[0].Seq != 2 || recs[1].Seq != 3 || len(recs[1].Ops) != 3 {
+		t.Fatalf("ReadJournal() = %+v, %v", recs, err)
+	}
+	if recs, err := d.ReadJournal(&JournalFilter{Since: t1}); err != nil || len(recs) != 1 {
+		t.Fatalf("ReadJournal(since) = %+v, %v", recs, err)
+	}
+	if n, _ := openTestClient(t, ConfigDB).Exists(journalStream).Result(); n != 0 {
+		t.Fatalf("Journal stream is used, with the journal file")
+	}
+}
@@ -72,7 +72,7 @@ func (d *DB) ExistKeysPattern(ts *TableSpec, pat Key) (bool, error) {
 		}
 	}()
 
-	if !d.Opts.IsWriteDisabled {
+	if !d.Opts.IsWriteDisabled || d.cpDs != nil {
 
 		// If Write is enabled, then just call GetKeysPattern() and check
 		// for now.
@@ -114,7 +114,7 @@ func (d *DB) ExistKeysPattern(ts *TableSpec, pat Key) (bool, error) {
 	}
 
 	// Run Lua script [Found = SUCCESS return]
-	if d.Opts.IsWriteDisabled && !exists {
+	if d.Opts.IsWriteDisabled && !exists && d.cpDs == nil {
 
 		var luaExists interface{}
 		if luaExists, err = luaScriptExistsKeysPatterns.Run(d.client,
@@ -156,6 +156,8 @@ func (d *DB) ExistKeysPattern(ts *TableSpec, pat Key) (bool, error) {
 			stats.ExistsKeyPatternPeak = dur
 		}
 		stats.ExistsKeyPatternTime += dur
+
+		d.observeOp(&stats, OpExistsKeyPattern, ts, d.key2redis(ts, pat), dur)
 	}
 
 	if d.dbStatsConfig.TableStats {
@@ -23,11 +23,16 @@ package db
 
 import (
 	"flag"
+	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
 	"runtime/debug"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Azure/sonic-mgmt-common/translib/tlerr"
@@ -45,13 +50,36 @@ const (
 
 	tryLockAttempt int           = 4
 	tryLockPause   time.Duration = 200
+
+	// Lock waiters are queued in a separate hash, as fields
+	// "<lockname>|<pid>:<seq>:<comm>:<id>" with value "<sinceMs>:<seenMs>"
+	lockWaitKey   string        = lockTableKey + "_waiters"
+	lockWaitPoll  time.Duration = 100 * time.Millisecond
+	lockWaitStale time.Duration = 10 * lockWaitPoll
+
+	// The lease of a lock is in the field "<lockname>.lease", with value
+	// "<expiryMs>:<pid>:<pid namespace>"
+	lockLeaseSuffix string = ".lease"
 )
 
+// lockLeaseTTL is the lease duration of the locks. The holder renews the
+// lease every lockLeaseTTL/3, so that the lock can be taken over once the
+// holder stops renewing it.
+var lockLeaseTTL = 30 * time.Second
+
 var execName string
 
+var pidNamespace string
+
+var lockWaitSeq uint32
+
 type lockStruct struct {
 	comm   string // Basename of the executable
 	locked bool
+
+	renewStop chan struct{}     // Closed to stop the lease renewal
+	lost      int32             // Set (atomically) if the lease is lost
+	onLost    func(*LockStruct) // Called when the lease is lost
 }
 
 type LockStruct struct {
@@ -62,6 +90,14 @@ type LockStruct struct {
 }
 
 func (lt *LockStruct) tryLock() error {
+	return lt.lock("", time.Time{})
+}
+
+// lock acquires the lock, or takes it over if the lease of the holder has
+// expired, or the holder process is gone. If waiter is not empty, the
+// caller is queued as a waiter on failure, with the wait start time since.
+// Waiters, and try-lockers, do not get the lock ahead of earlier waiters.
+func (lt *LockStruct) lock(waiter string, since time.Time) error {
 	var err error
 	var client *redis.Client
 	var reply interface{}
@@ -78,19 +114,29 @@ func (lt *LockStruct) tryLock() error {
 	}
 	defer client.Close()
 
-	// HSETNX: Set Hash Field if Not Exist
-	args := []interface{}{"HSETNX", lockTableKey, lt.Name,
-		lt.comm + ":" + lt.Id}
-	glog.Info("tryLock: RedisCmd: STATE_DB: ", args)
-	if reply, err = client.Do(args...).Result(); err == nil {
+	// Run the LUA Script to set the Hash Field, if it does not exist, or
+	// if it can be taken over.
+	now := time.Now()
+	deadValue, deadOwner := deadLockHolder(client, lt.Name)
+	args := []string{lt.Name, lt.value(), lockLease(now), timeMs(now),
+		waiter, timeMs(since), strconv.FormatInt(int64(lockWaitStale/time.Millisecond), 10),
+		deadValue, deadOwner}
+	glog.Info("tryLock: RedisCmd: STATE_DB: ", lockTableKey, args)
+	if reply, err = luaScriptLock.Run(client,
+		[]string{lockTableKey, lockWaitKey}, args).Result(); err == nil {
+
 		if intReply, ok := reply.(int64); !ok {
 			glog.Errorf("tryLock: Reply %v Not int64: %v Type: %v",
 				args, reply, reflect.TypeOf(reply))
 			err = tlerr.TranslibDBScriptFail{Description: "Unexpected response"}
-		} else if intReply == 0 {
+		} else if intReply <= 0 {
 			err = lt.dbLockedError(client)
 		} else {
+			if intReply == 2 {
+				glog.Warningf("tryLock: Took over stale lock %s", lt.Name)
+			}
 			lt.locked = true
+			lt.startRenew()
 			glog.Infof("tryLock: Locked: %s:%s", lt.Name, lt.Id)
 		}
 	}
@@ -101,6 +147,81 @@ func (lt *LockStruct) tryLock() error {
 	return err
 }
 
+func (lt *LockStruct) value() string {
+	return lt.comm + ":" + lt.Id
+}
+
+// lockLease returns the lease value, of a lock acquired or renewed at now.
+func lockLease(now time.Time) string {
+	return timeMs(now.Add(lockLeaseTTL)) + ":" + lockOwner()
+}
+
+// lockOwner returns the lease owner, "<pid>:<pid ns>", of this process
+func lockOwner() string {
+	return strconv.Itoa(os.Getpid()) + ":" + pidNamespace
+}
+
+// startRenew starts renewing the lease of the lock, till unlock().
+func (lt *LockStruct) startRenew() {
+	stop := make(chan struct{})
+	lt.renewStop = stop
+	name, value := lt.Name, lt.value()
+
+	go func() {
+		ticker := time.NewTicker(lockLeaseTTL / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				if !renewLease(name, value, lockLease(now)) {
+					atomic.StoreInt32(&lt.lost, 1)
+					if lt.onLost != nil {
+						lt.onLost(lt)
+					}
+					return
+				}
+			}
+		}
+	}()
+}
+
+// renewLease updates the lease of the lock, if it is still held with value,
+// by this process. Returns false if the lock is lost.
+func renewLease(name, value, lease string) bool {
+	client, err := getStateDB()
+	if err != nil {
+		return true
+	}
+	defer client.Close()
+
+	reply, err := luaScriptRenewLock.Run(client, []string{lockTableKey},
+		[]string{name, value, lease, lockOwner()}).Result()
+	if err != nil {
+		glog.Warningf("renewLease: %s: %v", name, err)
+		return true
+	}
+	if intReply, ok := reply.(int64); ok && intReply == 0 {
+		glog.Errorf("renewLease: Lock %s:%s lost", name, value)
+		return false
+	}
+	return true
+}
+
+// isLost returns true if the lease of the lock was lost, i.e. the lock was
+// taken over by another process. A nil lock is lost.
+func (lt *LockStruct) isLost() bool {
+	return lt == nil || atomic.LoadInt32(&lt.lost) != 0
+}
+
+func (lt *LockStruct) stopRenew() {
+	if lt.renewStop != nil {
+		close(lt.renewStop)
+		lt.renewStop = nil
+	}
+}
+
 func (lt *LockStruct) unlock() error {
 	var err error
 	var client *redis.Client
@@ -129,6 +250,7 @@ func (lt *LockStruct) unlock() error {
 			err = tlerr.TranslibDBScriptFail{Description: "Unexpected response"}
 		} else if intReply == 1 {
 			lt.locked = false
+			lt.stopRenew()
 			glog.Infof("unlock: Unlocked: %s:%s", lt.Name, lt.Id)
 		} else {
 			glog.Info("unlock: Already Unlocked")
@@ -170,6 +292,8 @@ func (lt *LockStruct) dbLockedError(c *redis.Client) error {
 
 var cdbLock *LockStruct
 
+var cdbLockMu sync.Mutex // Guards cdbLock
+
 func ConfigDBTryLock(token string) error {
 	var err error
 	glog.Info("ConfigDBTryLock:")
@@ -179,13 +303,16 @@ func ConfigDBTryLock(token string) error {
 		dumpStack(9, 10)
 	}
 
+	cdbLockMu.Lock()
+	defer cdbLockMu.Unlock()
+
 	// If len(token) == 0, this is not a configure session. (Eg: exec mode
 	// configure replace)
 	if cdbLock != nil {
 		err = cdbLock.dbLockedError(nil)
 	} else {
 		ls := LockStruct{Name: configDBLock, Id: token,
-			lockStruct: lockStruct{comm: execName}}
+			lockStruct: lockStruct{comm: execName, onLost: configDBLockLost}}
 		for attempts := 0; attempts < tryLockAttempt; attempts++ {
 			if err = ls.tryLock(); err == nil {
 				cdbLock = &ls
@@ -207,6 +334,44 @@ func ConfigDBTryLock(token string) error {
 	return err
 }
 
+// ConfigDBLock is ConfigDBTryLock, but waits up to timeout for the lock.
+// The waiters are queued in STATE_DB, and get the lock in the order of
+// their arrival. Returns the TranslibDBLock error of the holder on timeout.
+func ConfigDBLock(token string, timeout time.Duration) error {
+	var err error
+	glog.Infof("ConfigDBLock: timeout %v", timeout)
+
+	since := time.Now()
+	deadline := since.Add(timeout)
+	waiter := fmt.Sprintf("%s|%d:%d:%s:%s", configDBLock, os.Getpid(),
+		atomic.AddUint32(&lockWaitSeq, 1), execName, token)
+
+	for {
+		cdbLockMu.Lock()
+		if cdbLock != nil {
+			err = cdbLock.dbLockedError(nil)
+		} else {
+			ls := LockStruct{Name: configDBLock, Id: token,
+				lockStruct: lockStruct{comm: execName, onLost: configDBLockLost}}
+			if err = ls.lock(waiter, since); err == nil {
+				cdbLock = &ls
+			}
+		}
+		cdbLockMu.Unlock()
+
+		if _, ok := err.(tlerr.TranslibDBLock); !ok || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(lockWaitPoll)
+	}
+
+	if err != nil {
+		leaveLockWait(waiter)
+		glog.Error("ConfigDBLock: Error", err)
+	}
+	return err
+}
+
 func ConfigDBUnlock(token string) error {
 	var err error
 	glog.Info("ConfigDBUnlock:")
@@ -216,10 +381,14 @@ func ConfigDBUnlock(token string) error {
 		dumpStack(9, 10)
 	}
 
+	cdbLockMu.Lock()
+	defer cdbLockMu.Unlock()
+
 	if cdbLock == nil {
 		err = tlerr.TranslibDBLock{}
 	} else if cdbLock != nil {
 		err = cdbLock.unlock()
+		cdbLock.stopRenew()
 		cdbLock = nil
 	}
 
@@ -229,12 +398,64 @@ func ConfigDBUnlock(token string) error {
 	return err
 }
 
+// heldConfigDBLock returns the ConfigDB lock held by the process, if any
+func heldConfigDBLock() *LockStruct {
+	cdbLockMu.Lock()
+	defer cdbLockMu.Unlock()
+	return cdbLock
+}
+
+// configDBUnlock is ConfigDBUnlock, of the lock lt only. A lost lock, which
+// may have been placed again since, is not released.
+func configDBUnlock(lt *LockStruct) error {
+	cdbLockMu.Lock()
+	defer cdbLockMu.Unlock()
+
+	if lt == nil || cdbLock != lt {
+		glog.Warning("configDBUnlock: Lock lost")
+		return tlerr.TranslibDBLock{}
+	}
+	err := cdbLock.unlock()
+	cdbLock.stopRenew()
+	cdbLock = nil
+	return err
+}
+
+// configDBLockLost drops the ConfigDB lock, whose lease was lost. The DBs
+// which placed it fail their subsequent writes and commits.
+func configDBLockLost(lt *LockStruct) {
+	glog.Errorf("configDBLockLost: %s:%s", lt.Name, lt.Id)
+	cdbLockMu.Lock()
+	if cdbLock == lt {
+		cdbLock = nil
+	}
+	cdbLockMu.Unlock()
+}
+
+// checkConfigDBLock puts the DB in error, if the ConfigDB lock placed by it
+// was lost. Its subsequent writes and commits fail, since the changes made
+// so far may have been interleaved with those of the new holder.
+func (d *DB) checkConfigDBLock() {
+	if d.configDBLocked && d.configDBLock.isLost() {
+		glog.Errorf("%s: ConfigDB lock lost", d.Name())
+		d.configDBLocked = false
+		d.configDBLock = nil
+		d.err = tlerr.TranslibDBLock{}
+	}
+}
+
 func ConfigDBClearLock() error {
 	var err error
 	glog.Info("ConfigDBClearLock:")
 
+	cdbLockMu.Lock()
+	defer cdbLockMu.Unlock()
+
 	err = (&LockStruct{Name: configDBLock, Id: "*",
 		lockStruct: lockStruct{comm: execName, locked: true}}).unlock()
+	if cdbLock != nil {
+		cdbLock.stopRenew()
+	}
 	cdbLock = nil
 
 	// Clearing an absent lock is ok.
@@ -245,6 +466,22 @@ func ConfigDBClearLock() error {
 	return err
 }
 
+// LockInfo describes the holder, or a waiter, of a lock.
+type LockInfo struct {
+	Name   string    // Lockname
+	Comm   string    // Basename of the executable
+	Id     string    // ID Unique to the executable (Eg: Session-Token, "0-0")
+	Pid    int       // Process ID, if known
+	Expiry time.Time // Lease expiry of the holder. Zero if no lease.
+	Since  time.Time // Wait start time of the waiter.
+}
+
+// ConfigDBLockStatus returns the holder of the ConfigDB lock (nil if not
+// locked), and the waiters for it, in the order they will get the lock.
+func ConfigDBLockStatus() (*LockInfo, []LockInfo, error) {
+	return getLockStatus(configDBLock)
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // Internal Functions                                                        //
 ///////////////////////////////////////////////////////////////////////////////
@@ -278,12 +515,169 @@ func getExecName() string {
 	return filepath.Base(os.Args[0])
 }
 
+func getPidNamespace() string {
+	ns, _ := os.Readlink("/proc/self/ns/pid")
+	return ns
+}
+
+func timeMs(t time.Time) string {
+	if t.IsZero() {
+		return "0"
+	}
+	return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+}
+
+func msTime(ms string) time.Time {
+	if n, err := strconv.ParseInt(ms, 10, 64); err == nil && n != 0 {
+		return time.Unix(0, n*int64(time.Millisecond))
+	}
+	return time.Time{}
+}
+
+// parseLease splits the lease value "<expiryMs>:<pid>:<pid namespace>"
+func parseLease(lease string) (expiry time.Time, pid int, pidNs string) {
+	parts := strings.SplitN(lease, ":", 3)
+	expiry = msTime(parts[0])
+	if len(parts) > 1 {
+		pid, _ = strconv.Atoi(parts[1])
+	}
+	if len(parts) > 2 {
+		pidNs = parts[2]
+	}
+	return
+}
+
+// deadLockHolder returns the value of the lock, and the owner ("<pid>:<pid
+// namespace>") of its lease, if it is held by a process in our PID namespace,
+// which is gone. i.e. there is no process with the holder's PID, or it is
+// running a different executable. Returns "" if the holder is alive, or its
+// liveness can not be determined. The lock is taken over only if both the
+// value (comm and token) and the lease owner are still the same, since the
+// value alone does not tell apart the holders with the same token (Eg: "0-0").
+func deadLockHolder(client *redis.Client, name string) (value, owner string) {
+	vals, err := client.HMGet(lockTableKey, name, name+lockLeaseSuffix).Result()
+	if err != nil || len(vals) != 2 || vals[0] == nil || vals[1] == nil {
+		return "", ""
+	}
+	value, _ = vals[0].(string)
+	lease, _ := vals[1].(string)
+	_, pid, pidNs := parseLease(lease)
+	if pid <= 0 || pidNs == "" || pidNs != pidNamespace || pid == os.Getpid() {
+		return "", ""
+	}
+	owner = strconv.Itoa(pid) + ":" + pidNs
+
+	comm := strings.SplitN(value, ":", 2)[0]
+	procComm, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid))
+	if err == nil {
+		// /proc/<pid>/comm is truncated to 15 characters
+		if pc := strings.TrimSpace(string(procComm)); pc != "" && strings.HasPrefix(comm, pc) {
+			return "", ""
+		}
+	} else if !os.IsNotExist(err) {
+		return "", ""
+	}
+
+	glog.Warningf("deadLockHolder: %s holder %s (pid %d) is gone", name, value, pid)
+	return value, owner
+}
+
+// leaveLockWait removes the waiter from the lock wait queue.
+func leaveLockWait(waiter string) {
+	if client, err := getStateDB(); err == nil {
+		client.HDel(lockWaitKey, waiter)
+		client.Close()
+	}
+}
+
+func getLockStatus(name string) (*LockInfo, []LockInfo, error) {
+	client, err := getStateDB()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer client.Close()
+
+	locks, err := client.HGetAll(lockTableKey).Result()
+	if err != nil {
+		return nil, nil, err
+	}
+	waits, err := client.HGetAll(lockWaitKey).Result()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var holder *LockInfo
+	if value, ok := locks[name]; ok {
+		holder = &LockInfo{Name: name}
+		holder.Comm, holder.Id = splitLockValue(value)
+		holder.Expiry, holder.Pid, _ = parseLease(locks[name+lockLeaseSuffix])
+	}
+
+	var waiters []LockInfo
+	var fields []string
+	staleTime := time.Now().Add(-lockWaitStale)
+	for field, val := range waits {
+		if !strings.HasPrefix(field, name+"|") {
+			continue
+		}
+		times := strings.SplitN(val, ":", 2)
+		if len(times) != 2 || msTime(times[1]).Before(staleTime) {
+			continue
+		}
+		// <pid>:<seq>:<comm>:<id>
+		parts := strings.SplitN(strings.TrimPrefix(field, name+"|"), ":", 4)
+		if len(parts) != 4 {
+			continue
+		}
+		pid, _ := strconv.Atoi(parts[0])
+		waiters = append(waiters, LockInfo{Name: name, Comm: parts[2],
+			Id: parts[3], Pid: pid, Since: msTime(times[0])})
+		fields = append(fields, field)
+	}
+
+	sort.Sort(lockWaiters{waiters, fields})
+	return holder, waiters, nil
+}
+
+func splitLockValue(value string) (comm, id string) {
+	if colon := strings.Index(value, ":"); colon >= 0 {
+		return value[:colon], value[colon+1:]
+	}
+	return value, ""
+}
+
+// lockWaiters sorts the waiters in the order of luaScriptLock, i.e. by
+// the wait start time, and then by the field name.
+type lockWaiters struct {
+	info   []LockInfo
+	fields []string
+}
+
+func (w lockWaiters) Len() int { return len(w.info) }
+
+func (w lockWaiters) Less(i, j int) bool {
+	if !w.info[i].Since.Equal(w.info[j].Since) {
+		return w.info[i].Since.Before(w.info[j].Since)
+	}
+	return w.fields[i] < w.fields[j]
+}
+
+func (w lockWaiters) Swap(i, j int) {
+	w.info[i], w.info[j] = w.info[j], w.info[i]
+	w.fields[i], w.fields[j] = w.fields[j], w.fields[i]
+}
+
 var luaScriptUnlock *redis.Script
 
+var luaScriptLock *redis.Script
+
+var luaScriptRenewLock *redis.Script
+
 func init() {
 
 	// Executable Name
 	execName = getExecName()
+	pidNamespace = getPidNamespace()
 
 	// Register the Lua Script. Only Unlock if the Hash Field Value matches
 	// i.e. if HGET KEYS[1] ARGV[1] == ARGV[2]:ARGV[3], ARGV[2],[3] could be *
@@ -299,12 +693,81 @@ func init() {
 			local id = string.sub(fieldVal, colon + 1, slen)
 			if ((ARGV[2] == '*') or (ARGV[2] == comm)) and
 					((ARGV[3] == '*') or (ARGV[3] == id)) then
+				redis.call("HDEL", KEYS[1], ARGV[1] .. ".lease")
 				return redis.call("HDEL", KEYS[1], ARGV[1])
 			end
 		end
 		return 0
 	`)
 
+	// Lock: KEYS[1] is the lock hash, KEYS[2] is the waiter hash. ARGV[1] is
+	// the lockname, ARGV[2] the value, ARGV[3] the lease, ARGV[4] now (ms),
+	// ARGV[5] the waiter field ("" if not waiting), ARGV[6] its wait start
+	// time, ARGV[7] the staleness (ms) of waiters, ARGV[8] the value of a
+	// dead holder ("" if none) and ARGV[9] its lease owner ("<pid>:<pid ns>").
+	// Returns 1 if locked, 2 if taken over, 0 if locked by others, and -1
+	// if an earlier waiter is ahead.
+	luaScriptLock = redis.NewScript(`
+		local name, waiter = ARGV[1], ARGV[5]
+		local now, since = tonumber(ARGV[4]), tonumber(ARGV[6])
+		local prefix = name .. "|"
+		local ahead = false
+		local w = redis.call("HGETALL", KEYS[2])
+		for i = 1, #w, 2 do
+			local f = w[i]
+			if f ~= waiter and string.sub(f, 1, #prefix) == prefix then
+				local fSince, fSeen = string.match(w[i + 1], "^(%d+):(%d+)$")
+				fSince, fSeen = tonumber(fSince), tonumber(fSeen)
+				if (not fSeen) or (fSeen + tonumber(ARGV[7]) < now) then
+					redis.call("HDEL", KEYS[2], f)
+				elseif (waiter == "") or (fSince < since) or
+						((fSince == since) and (f < waiter)) then
+					ahead = true
+				end
+			end
+		end
+
+		local result = 1
+		local cur = redis.call("HGET", KEYS[1], name)
+		if cur then
+			local lease = redis.call("HGET", KEYS[1], name .. ".lease")
+			local expiry = lease and tonumber(string.match(lease, "^%d+"))
+			local owner = lease and string.match(lease, "^%d+:(.*)$")
+			if (expiry and expiry < now) or
+					(ARGV[8] ~= "" and cur == ARGV[8] and owner == ARGV[9]) then
+				result = 2
+			else
+				result = 0
+			end
+		end
+		if (result > 0) and ahead then
+			result = -1
+		end
+
+		if result > 0 then
+			redis.call("HMSET", KEYS[1], name, ARGV[2], name .. ".lease", ARGV[3])
+			if waiter ~= "" then
+				redis.call("HDEL", KEYS[2], waiter)
+			end
+		elseif waiter ~= "" then
+			redis.call("HSET", KEYS[2], waiter, ARGV[6] .. ":" .. ARGV[4])
+		end
+		return result
+	`)
+
+	// Renew the lease ARGV[3] of lock ARGV[1], if it is still ARGV[2], and
+	// the lease owner is still ARGV[4] ("<pid>:<pid ns>").
+	luaScriptRenewLock = redis.NewScript(`
+		if redis.call("HGET", KEYS[1], ARGV[1]) == ARGV[2] then
+			local lease = redis.call("HGET", KEYS[1], ARGV[1] .. ".lease")
+			if lease and string.match(lease, "^%d+:(.*)$") == ARGV[4] then
+				redis.call("HSET", KEYS[1], ARGV[1] .. ".lease", ARGV[3])
+				return 1
+			end
+		end
+		return 0
+	`)
+
 	// Clears the ConfigDB Lock, (if the current executable placed it, i.e.
 	// RESTCONF/rest-server clears it's lock, and gNMI/telemetry clears
 	// it's lock).
@@ -0,0 +1,314 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Azure/sonic-mgmt-common/translib/tlerr"
+	"github.com/go-redis/redis/v7"
+)
+
+// setupLeaseLock sets the ConfigDB lock held by holder, with a lease of
+// given expiry and pid, in the STATE_DB of the current backend.
+func setupLeaseLock(t *testing.T, holder string, expiry time.Time, pid int, pidNs string) *redis.Client {
+	t.Cleanup(func() { ConfigDBClearLock() })
+	client := openTestClient(t, StateDB)
+	client.Del(lockTableKey, lockWaitKey)
+	if len(holder) != 0 {
+		client.HMSet(lockTableKey, map[string]interface{}{
+			configDBLock:                   holder,
+			configDBLock + lockLeaseSuffix: timeMs(expiry) + ":" + strconv.Itoa(pid) + ":" + pidNs,
+		})
+	}
+	return client
+}
+
+func verifyLockHolder(t *testing.T, expId string) {
+	t.Helper()
+	holder, _, err := ConfigDBLockStatus()
+	if err != nil {
+		t.Fatalf("ConfigDBLockStatus() fails e = %v", err)
+	}
+	if len(expId) == 0 {
+		if holder != nil {
+			t.Fatalf("ConfigDBLockStatus() holder = %+v; expected none", holder)
+		}
+		return
+	}
+	if holder == nil || holder.Id != expId || holder.Comm != execName ||
+		holder.Pid != os.Getpid() || !holder.Expiry.After(time.Now()) {
+		t.Fatalf("ConfigDBLockStatus() holder = %+v; expected %s", holder, expId)
+	}
+}
+
+func TestLockLeaseTakeover(t *testing.T) {
+	onTestBackends(t, testLockLeaseTakeover)
+}
+
+func testLockLeaseTakeover(t *testing.T) {
+	// Holder with an expired lease
+	client := setupLeaseLock(t, "other:1001-2", time.Now().Add(-time.Second),
+		os.Getpid(), pidNamespace)
+	if err := ConfigDBTryLock(testSTok); err != nil {
+		t.Fatalf("ConfigDBTryLock() with expired lease; e = %v", err)
+	}
+	verifyLockHolder(t, testSTok)
+
+	if err := ConfigDBUnlock(testSTok); err != nil {
+		t.Fatalf("ConfigDBUnlock() fails e = %v", err)
+	}
+	verifyLockHolder(t, "")
+	if n, _ := client.Exists(lockTableKey).Result(); n != 0 {
+		t.Fatalf("Lease is not cleared by ConfigDBUnlock()")
+	}
+}
+
+func TestLockLeaseLiveHolder(t *testing.T) {
+	onTestBackends(t, testLockLeaseLiveHolder)
+}
+
+func testLockLeaseLiveHolder(t *testing.T) {
+	// Holder (this process) with a valid lease
+	setupLeaseLock(t, "other:1001-2", time.Now().Add(time.Minute),
+		os.Getpid(), pidNamespace)
+	err := ConfigDBTryLock(testSTok)
+	if e, ok := err.(tlerr.TranslibDBLock); !ok || e.Type != tlerr.DBLockConfigSession {
+		t.Fatalf("ConfigDBTryLock() with live holder; e = %#v", err)
+	}
+}
+
+func TestLockLeaseDeadHolder(t *testing.T) {
+	onTestBackends(t, testLockLeaseDeadHolder)
+}
+
+func testLockLeaseDeadHolder(t *testing.T) {
+	if len(pidNamespace) == 0 {
+		t.Skip("PID namespace is not known")
+	}
+
+	// Beyond the max pid_max, so there is no such process.
+	deadPid := 4194305
+
+	t.Run("samePidNs", func(t *testing.T) {
+		setupLeaseLock(t, execName+":1001-2", time.Now().Add(time.Minute),
+			deadPid, pidNamespace)
+		if err := ConfigDBTryLock(testSTok); err != nil {
+			t.Fatalf("ConfigDBTryLock() with dead holder; e = %v", err)
+		}
+		verifyLockHolder(t, testSTok)
+	})
+
+	t.Run("otherPidNs", func(t *testing.T) {
+		setupLeaseLock(t, execName+":1001-2", time.Now().Add(time.Minute),
+			deadPid, "pid:[1]")
+		if err := ConfigDBTryLock(testSTok); err == nil {
+			t.Fatalf("ConfigDBTryLock() took over a lock of other PID namespace")
+		}
+	})
+}
+
+func TestLockLeaseDeadHolderReplaced(t *testing.T) {
+	onTestBackends(t, testLockLeaseDeadHolderReplaced)
+}
+
+func testLockLeaseDeadHolderReplaced(t *testing.T) {
+	if len(pidNamespace) == 0 {
+		t.Skip("PID namespace is not known")
+	}
+
+	// The dead holder is replaced, with the same value, by another process
+	// between deadLockHolder() and the takeover.
+	client := setupLeaseLock(t, execName+":0-0", time.Now().Add(time.Minute),
+		4194305, pidNamespace)
+	value, owner := deadLockHolder(client, configDBLock)
+	if value != execName+":0-0" || owner != "4194305:"+pidNamespace {
+		t.Fatalf("deadLockHolder() = %q, %q", value, owner)
+	}
+	client.HSet(lockTableKey, configDBLock+lockLeaseSuffix,
+		timeMs(time.Now().Add(time.Minute))+":1:"+pidNamespace)
+
+	now := time.Now()
+	reply, err := luaScriptLock.Run(client, []string{lockTableKey, lockWaitKey},
+		[]string{configDBLock, execName + ":" + testSTok, lockLease(now), timeMs(now),
+			"", timeMs(now), "1000", value, owner}).Result()
+	if err != nil || reply.(int64) != 0 {
+		t.Fatalf("luaScriptLock took over a replaced holder; reply = %v, e = %v", reply, err)
+	}
+}
+
+func TestLockLeaseLost(t *testing.T) {
+	onTestBackends(t, testLockLeaseLost)
+}
+
+func testLockLeaseLost(t *testing.T) {
+	defer func(ttl time.Duration) { lockLeaseTTL = ttl }(lockLeaseTTL)
+	lockLeaseTTL = 300 * time.Millisecond
+
+	client := setupLeaseLock(t, "", time.Time{}, 0, "")
+	ts := &TableSpec{Name: "LOCK_LOST_TEST"}
+	d := openTestDB(t, ConfigDB, false)
+	if err := d.StartTx(nil, nil); err != nil {
+		t.Fatalf("StartTx() fails e = %v", err)
+	}
+	if err := d.SetEntry(ts, *NewKey("k1"), Value{Field: map[string]string{"f": "1"}}); err != nil {
+		t.Fatalf("SetEntry() fails e = %v", err)
+	}
+
+	// Another process takes over the lock
+	client.HSet(lockTableKey, configDBLock, "other:0-0")
+	if !eventually(func() bool { return heldConfigDBLock() == nil }) {
+		t.Fatalf("Lost ConfigDB lock is not dropped")
+	}
+
+	err := d.SetEntry(ts, *NewKey("k2"), Value{Field: map[string]string{"f": "2"}})
+	if _, ok := err.(tlerr.TranslibDBLock); !ok {
+		t.Fatalf("SetEntry() after lock lost; e = %#v", err)
+	}
+	if err = d.CommitTx(); err == nil {
+		t.Fatalf("CommitTx() after lock lost succeeds")
+	}
+	if n, _ := openTestClient(t, ConfigDB).Exists("LOCK_LOST_TEST|k1").Result(); n != 0 {
+		t.Fatalf("Entry is written after lock lost")
+	}
+
+	// DeleteDB does not release the lock of the new holder
+	d.DeleteDB()
+	if v, _ := client.HGet(lockTableKey, configDBLock).Result(); v != "other:0-0" {
+		t.Fatalf("Lock of the new holder = %q after DeleteDB()", v)
+	}
+}
+
+func TestLockLeaseRenew(t *testing.T) {
+	onTestBackends(t, testLockLeaseRenew)
+}
+
+func testLockLeaseRenew(t *testing.T) {
+	defer func(ttl time.Duration) { lockLeaseTTL = ttl }(lockLeaseTTL)
+	lockLeaseTTL = 300 * time.Millisecond
+
+	setupLeaseLock(t, "", time.Time{}, 0, "")
+	if err := ConfigDBTryLock(testSTok); err != nil {
+		t.Fatalf("ConfigDBTryLock() fails e = %v", err)
+	}
+	time.Sleep(2 * lockLeaseTTL)
+	verifyLockHolder(t, testSTok)
+
+	if err := ConfigDBUnlock(testSTok); err != nil {
+		t.Fatalf("ConfigDBUnlock() fails e = %v", err)
+	}
+}
+
+func TestLockLeaseRenewOtherOwner(t *testing.T) {
+	onTestBackends(t, testLockLeaseRenewOtherOwner)
+}
+
+func testLockLeaseRenewOtherOwner(t *testing.T) {
+	// Another process holds the lock, with the same value
+	value := execName + ":0-0"
+	client := setupLeaseLock(t, value, time.Now().Add(time.Minute), 1, pidNamespace)
+	lease, _ := client.HGet(lockTableKey, configDBLock+lockLeaseSuffix).Result()
+	if renewLease(configDBLock, value, lockLease(time.Now())) {
+		t.Fatalf("renewLease() renewed the lease of another process")
+	}
+	if v, _ := client.HGet(lockTableKey, configDBLock+lockLeaseSuffix).Result(); v != lease {
+		t.Fatalf("Lease = %q after renewLease(); expected %q", v, lease)
+	}
+
+	// This process holds the lock
+	client.HSet(lockTableKey, configDBLock+lockLeaseSuffix, lockLease(time.Now()))
+	if !renewLease(configDBLock, value, lockLease(time.Now())) {
+		t.Fatalf("renewLease() failed for this process")
+	}
+}
+
+func TestLockWait(t *testing.T) {
+	onTestBackends(t, testLockWait)
+}
+
+func testLockWait(t *testing.T) {
+	client := setupLeaseLock(t, "other:1001-2", time.Now().Add(time.Minute),
+		os.Getpid(), pidNamespace)
+
+	// Times out, and leaves the wait queue
+	err := ConfigDBLock(testSTok, 300*time.Millisecond)
+	if _, ok := err.(tlerr.TranslibDBLock); !ok {
+		t.Fatalf("ConfigDBLock() with live holder; e = %#v", err)
+	}
+	if _, waiters, _ := ConfigDBLockStatus(); len(waiters) != 0 {
+		t.Fatalf("ConfigDBLockStatus() waiters = %+v after timeout", waiters)
+	}
+
+	// Gets the lock when released by the holder
+	done := make(chan error)
+	go func() { done <- ConfigDBLock(testSTok, 10*time.Second) }()
+
+	for start := time.Now(); ; time.Sleep(lockWaitPoll) {
+		holder, waiters, _ := ConfigDBLockStatus()
+		if len(waiters) == 1 {
+			if holder == nil || holder.Id != "1001-2" || waiters[0].Id != testSTok ||
+				waiters[0].Pid != os.Getpid() || waiters[0].Since.IsZero() {
+				t.Fatalf("ConfigDBLockStatus() = %+v, %+v", holder, waiters)
+			}
+			break
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatalf("ConfigDBLock() is not waiting")
+		}
+	}
+	client.HDel(lockTableKey, configDBLock, configDBLock+lockLeaseSuffix)
+
+	if err := <-done; err != nil {
+		t.Fatalf("ConfigDBLock() fails e = %v", err)
+	}
+	verifyLockHolder(t, testSTok)
+	if _, waiters, _ := ConfigDBLockStatus(); len(waiters) != 0 {
+		t.Fatalf("ConfigDBLockStatus() waiters = %+v after lock", waiters)
+	}
+}
+
+func TestLockWaitOrder(t *testing.T) {
+	onTestBackends(t, testLockWaitOrder)
+}
+
+func testLockWaitOrder(t *testing.T) {
+	client := setupLeaseLock(t, "", time.Time{}, 0, "")
+
+	// An earlier waiter is ahead of try-lockers
+	now := time.Now()
+	waiter := configDBLock + "|1:1:other:1001-2"
+	client.HSet(lockWaitKey, waiter, timeMs(now.Add(-time.Second))+":"+timeMs(now.Add(time.Minute)))
+	if err := ConfigDBTryLock(testSTok); err == nil {
+		t.Fatalf("ConfigDBTryLock() got ahead of a waiter")
+	}
+	if _, waiters, _ := ConfigDBLockStatus(); len(waiters) != 1 || waiters[0].Id != "1001-2" {
+		t.Fatalf("ConfigDBLockStatus() waiters = %+v", waiters)
+	}
+
+	// Stale waiters are ignored
+	client.HSet(lockWaitKey, waiter, timeMs(now.Add(-time.Minute))+":"+timeMs(now.Add(-time.Minute)))
+	if err := ConfigDBTryLock(testSTok); err != nil {
+		t.Fatalf("ConfigDBTryLock() with stale waiter; e = %v", err)
+	}
+	verifyLockHolder(t, testSTok)
+}
@@ -0,0 +1,323 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"encoding"
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+//  Exported Functions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+// Marshal returns the Value of the struct, or the pointer to the struct, v.
+// Marshal and Unmarshal map the struct fields to the Value fields using the
+// `db` struct tags. Eg:
+//
+//	type VlanEntry struct {
+//		VlanId  int        `db:"vlanid"`
+//		Mtu     uint32     `db:"mtu,omitempty"`
+//		Enabled bool       `db:"enabled"`
+//		Members []string   `db:"members"` // leaf-list "members@"
+//		Gateway net.IP     `db:"gateway,omitempty"`
+//		Prefix  *net.IPNet `db:"prefix,omitempty"`
+//		Cache   string     `db:"-"`
+//	}
+//
+// The tag is the field name, followed by the comma separated options:
+//
+//	omitempty : Marshal skips the zero value
+//
+// Struct fields without a `db` tag, or with the "-" tag, are ignored.
+// Untagged embedded structs are flattened.
+//
+// The supported field types are string, bool, the integer and float types,
+// net.IP, net.IPNet (the "address/length", with the host bits kept), the
+// types implementing encoding.TextMarshaler and encoding.TextUnmarshaler,
+// and the pointers to these. A nil pointer is
+// not marshalled. Slices of these are leaf-lists: the "@" suffix is
+// appended to the field name (if not already in the tag), the items are
+// comma separated, and an empty slice is not marshalled (as Value.SetList).
+func Marshal(v interface{}) (Value, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return Value{}, fmt.Errorf("db.Marshal: %T is not a struct", v)
+	}
+
+	value := Value{Field: make(map[string]string)}
+	for _, f := range structFields(rv.Type()) {
+		fv := rv.FieldByIndex(f.index)
+		if f.omitEmpty && fv.IsZero() {
+			continue
+		}
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+
+		if !f.isList {
+			s, err := marshalField(fv)
+			if err != nil {
+				return Value{}, fmt.Errorf("db.Marshal: %s: %v", f.name, err)
+			}
+			value.Field[f.name] = s
+			continue
+		}
+
+		items := make([]string, fv.Len())
+		for i := range items {
+			s, err := marshalField(fv.Index(i))
+			if err != nil {
+				return Value{}, fmt.Errorf("db.Marshal: %s: %v", f.name, err)
+			}
+			items[i] = s
+		}
+		value.SetList(f.name, items)
+	}
+
+	return value, nil
+}
+
+// Unmarshal sets the struct fields of the pointer to the struct v, from
+// the Value. The struct fields of the absent Value fields are unchanged.
+func Unmarshal(value Value, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("db.Unmarshal: %T is not a pointer to a struct", v)
+	}
+	rv = rv.Elem()
+
+	for _, f := range structFields(rv.Type()) {
+		data, ok := value.Field[f.name]
+		if !ok {
+			continue
+		}
+		fv := rv.FieldByIndex(f.index)
+		if fv.Kind() == reflect.Ptr {
+			fv.Set(reflect.New(fv.Type().Elem()))
+			fv = fv.Elem()
+		}
+
+		if !f.isList {
+			if err := unmarshalField(fv, data); err != nil {
+				return fmt.Errorf("db.Unmarshal: %s: %v", f.name, err)
+			}
+			continue
+		}
+
+		items := value.GetList(f.name)
+		list := reflect.MakeSlice(fv.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := unmarshalField(list.Index(i), item); err != nil {
+				return fmt.Errorf("db.Unmarshal: %s: %v", f.name, err)
+			}
+		}
+		fv.Set(list)
+	}
+
+	return nil
+}
+
+// GetEntryAs gets the entry(row) of the table, into the pointer to the
+// struct v. See Unmarshal().
+func (d *DB) GetEntryAs(ts *TableSpec, key Key, v interface{}) error {
+	value, err := d.GetEntry(ts, key)
+	if err != nil {
+		return err
+	}
+	return Unmarshal(value, v)
+}
+
+// SetEntryFrom sets the entry(row) of the table, from the struct, or the
+// pointer to the struct, v. See Marshal(). If none of the struct fields are
+// marshalled, the entry is set with the "NULL" field, rather than deleted
+// (as SetEntry does with an empty Value).
+func (d *DB) SetEntryFrom(ts *TableSpec, key Key, v interface{}) error {
+	value, err := Marshal(v)
+	if err != nil {
+		return err
+	}
+	if !value.IsPopulated() {
+		value.Set("NULL", "NULL")
+	}
+	return d.SetEntry(ts, key, value)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Internal Functions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+// structField is the Value field info of a struct field
+type structField struct {
+	name      string // Value field name. Includes the "@" of leaf-lists
+	index     []int  // reflect.Value.FieldByIndex()
+	omitEmpty bool
+	isList    bool
+}
+
+// structFieldsMap caches the []structField of the struct types
+var structFieldsMap sync.Map
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+var ipNetType = reflect.TypeOf(net.IPNet{})
+
+func structFields(t reflect.Type) []structField {
+	if fields, ok := structFieldsMap.Load(t); ok {
+		return fields.([]structField)
+	}
+
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, tagged := sf.Tag.Lookup("db")
+		if !tagged && sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			for _, f := range structFields(sf.Type) {
+				f.index = append([]int{i}, f.index...)
+				fields = append(fields, f)
+			}
+			continue
+		}
+		if !tagged || tag == "-" || sf.PkgPath != "" {
+			continue
+		}
+
+		opts := strings.Split(tag, ",")
+		f := structField{name: opts[0], index: []int{i}}
+		for _, opt := range opts[1:] {
+			f.omitEmpty = f.omitEmpty || opt == "omitempty"
+		}
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		f.isList = ft.Kind() == reflect.Slice && !ft.Implements(textMarshalerType)
+		if f.isList && !strings.HasSuffix(f.name, "@") {
+			f.name += "@"
+		}
+		fields = append(fields, f)
+	}
+
+	structFieldsMap.Store(t, fields)
+	return fields
+}
+
+// marshalField returns the string of the (non-pointer) struct field, or
+// leaf-list item, fv.
+func marshalField(fv reflect.Value) (string, error) {
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			return "", nil
+		}
+		fv = fv.Elem()
+	}
+
+	if fv.Type() == ipNetType {
+		ipNet := fv.Interface().(net.IPNet)
+		return ipNet.String(), nil
+	}
+	if fv.Type().Implements(textMarshalerType) {
+		text, err := fv.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		return fv.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(fv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(fv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(fv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(fv.Float(), 'g', -1, fv.Type().Bits()), nil
+	}
+
+	return "", fmt.Errorf("unsupported type %v", fv.Type())
+}
+
+// unmarshalField sets the (non-pointer) struct field, or leaf-list item,
+// fv from the string data.
+func unmarshalField(fv reflect.Value, data string) error {
+	if fv.Kind() == reflect.Ptr {
+		fv.Set(reflect.New(fv.Type().Elem()))
+		fv = fv.Elem()
+	}
+
+	if fv.Type() == ipNetType {
+		// Keep the host address, as in the "address/len" of an interface
+		ip, ipNet, err := net.ParseCIDR(data)
+		if err == nil {
+			if len(ipNet.IP) == net.IPv4len {
+				ip = ip.To4()
+			}
+			ipNet.IP = ip
+			fv.Set(reflect.ValueOf(*ipNet))
+		}
+		return err
+	}
+	if u, ok := fv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(data))
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(data)
+		return nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(data)
+		if err == nil {
+			fv.SetBool(b)
+		}
+		return err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(data, 10, fv.Type().Bits())
+		if err == nil {
+			fv.SetInt(n)
+		}
+		return err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(data, 10, fv.Type().Bits())
+		if err == nil {
+			fv.SetUint(n)
+		}
+		return err
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(data, fv.Type().Bits())
+		if err == nil {
+			fv.SetFloat(f)
+		}
+		return err
+	}
+
+	return fmt.Errorf("unsupported type %v", fv.Type())
+}
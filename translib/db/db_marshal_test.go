@@ -0,0 +1,141 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+type marshalTestBase struct {
+	Desc string `db:"description,omitempty"`
+}
+
+type marshalTestEntry struct {
+	marshalTestBase
+	VlanId  int        `db:"vlanid"`
+	Mtu     uint16     `db:"mtu,omitempty"`
+	Enabled bool       `db:"enabled"`
+	Members []string   `db:"members"`
+	Vids    []uint32   `db:"vids@"`
+	Gateway net.IP     `db:"gateway,omitempty"`
+	Prefix  *net.IPNet `db:"prefix,omitempty"`
+	Speed   *float64   `db:"speed"`
+	Cache   string     `db:"-"`
+	Other   string
+}
+
+func TestMarshal(t *testing.T) {
+	_, prefix, _ := net.ParseCIDR("10.1.0.0/16")
+	e := marshalTestEntry{
+		marshalTestBase: marshalTestBase{Desc: "uplink"},
+		VlanId:          10,
+		Members:         []string{"Ethernet0", "Ethernet4"},
+		Vids:            []uint32{1, 2},
+		Gateway:         net.ParseIP("10.1.0.1"),
+		Prefix:          prefix,
+		Cache:           "x",
+		Other:           "y",
+	}
+	exp := map[string]string{
+		"description": "uplink",
+		"vlanid":      "10",
+		"enabled":     "false",
+		"members@":    "Ethernet0,Ethernet4",
+		"vids@":       "1,2",
+		"gateway":     "10.1.0.1",
+		"prefix":      "10.1.0.0/16",
+	}
+
+	v, err := Marshal(&e)
+	if err != nil || !reflect.DeepEqual(v.Field, exp) {
+		t.Fatalf("Marshal() = %v, %v; expected %v", v.Field, err, exp)
+	}
+
+	var e2 marshalTestEntry
+	v.Set("mtu", "9100")
+	v.Set("speed", "2.5")
+	if err = Unmarshal(v, &e2); err != nil {
+		t.Fatalf("Unmarshal() fails e = %v", err)
+	}
+	e.Mtu, e.Cache, e.Other = 9100, "", ""
+	if e2.Speed == nil || *e2.Speed != 2.5 {
+		t.Errorf("Unmarshal() Speed = %v", e2.Speed)
+	}
+	e2.Speed = nil
+	if !reflect.DeepEqual(e2, e) {
+		t.Errorf("Unmarshal() = %+v; expected %+v", e2, e)
+	}
+
+	// Conversion errors
+	for f, data := range map[string]string{"mtu": "65536", "enabled": "yes",
+		"prefix": "10.1.0.1", "gateway": "10.1", "vids@": "1,x"} {
+		bad := v.Copy()
+		bad.Set(f, data)
+		if err = Unmarshal(bad, &e2); err == nil {
+			t.Errorf("Unmarshal() %s = %q did not fail", f, data)
+		}
+	}
+	if _, err = Marshal("x"); err == nil {
+		t.Errorf("Marshal(string) did not fail")
+	}
+	if err = Unmarshal(v, e2); err == nil {
+		t.Errorf("Unmarshal(struct) did not fail")
+	}
+}
+
+func TestMarshalPrefixAddress(t *testing.T) {
+	for _, prefix := range []string{"10.1.1.5/24", "10.1.0.0/16", "2001:db8::5/64"} {
+		var e marshalTestEntry
+		if err := Unmarshal(Value{Field: map[string]string{"prefix": prefix}}, &e); err != nil {
+			t.Fatalf("Unmarshal() %s fails e = %v", prefix, err)
+		}
+		v, err := Marshal(&e)
+		if err != nil || v.Get("prefix") != prefix {
+			t.Errorf("Marshal() prefix = %q, %v; expected %q", v.Get("prefix"), err, prefix)
+		}
+	}
+}
+
+func TestGetEntryAs(t *testing.T) {
+	useMemoryBackend(t)
+	d := openTestDB(t, ConfigDB, false)
+	ts := &TableSpec{Name: "MARSHAL_TEST"}
+
+	if err := d.SetEntryFrom(ts, *NewKey("Vlan10"), marshalTestEntry{VlanId: 10, Enabled: true}); err != nil {
+		t.Fatalf("SetEntryFrom() fails e = %v", err)
+	}
+	var e marshalTestEntry
+	if err := d.GetEntryAs(ts, *NewKey("Vlan10"), &e); err != nil || e.VlanId != 10 || !e.Enabled {
+		t.Errorf("GetEntryAs() = %+v, %v", e, err)
+	}
+
+	// Nothing marshalled: the entry is set with the "NULL" field
+	if err := d.SetEntryFrom(ts, *NewKey("Vlan20"), &marshalTestBase{}); err != nil {
+		t.Fatalf("SetEntryFrom() fails e = %v", err)
+	}
+	if v, err := d.GetEntry(ts, *NewKey("Vlan20")); err != nil || v.Get("NULL") != "NULL" {
+		t.Errorf("GetEntry() = %v, %v", v, err)
+	}
+	if err := d.GetEntryAs(ts, *NewKey("Vlan30"), &e); !isNotExist(err) {
+		t.Errorf("GetEntryAs() of missing entry; e = %v", err)
+	}
+}
@@ -0,0 +1,495 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang/glog"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+//  Exported Types                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+// MigrationData is the CONFIG_DB contents being migrated. Table name ->
+// key (components joined by "|") -> entry. Leaf-list fields have the "@"
+// suffix, and entries without fields have the "NULL" field, as in redis.
+type MigrationData map[string]map[string]Value
+
+// Migration is a CONFIG_DB schema migration step. The migrations are run
+// in the natural order (Eg: version_4_0_2 < version_4_0_10) of their
+// Version, for the versions after the VERSIONS|DATABASE "VERSION" of the
+// data. The VERSION is updated to the Version of each migration run.
+// VERSIONS|DATABASE is also the version of the db_migrator (sonic-utilities)
+// steps; a Version is a step in that same sequence, and is done once,
+// either by the db_migrator or here.
+//
+// The same migrations are applied to the CONFIG_DB (Migrate()), the config
+// files (MigrateFile()), and the checkpoint files (MigrateCheckpoints(), or
+// in memory, whenever an older checkpoint is loaded). Hence Migrate should
+// only transform the data; Eg: rename fields, split tables, change the key
+// formats.
+type Migration struct {
+	Version     string // Eg: "version_4_0_2"
+	Description string
+	Migrate     func(data MigrationData) error
+}
+
+// MigrateOptions are the options of the Migrate APIs
+type MigrateOptions struct {
+	// DryRun reports the changes, without writing them. The CONFIG_DB
+	// changes are still validated; in a transaction which is aborted.
+	DryRun bool
+	// ToVersion limits the migrations to the ones upto this version.
+	// All the registered migrations are run if empty.
+	ToVersion string
+}
+
+// MigrationReport is what a Migrate API run (or would run, for a DryRun)
+type MigrationReport struct {
+	Source      string   // "CONFIG_DB", or the file path
+	FromVersion string   // VERSIONS|DATABASE VERSION before the migrations
+	ToVersion   string   // VERSIONS|DATABASE VERSION after the migrations
+	Applied     []string // Versions of the migrations run
+	Changes     []MigrationChange
+}
+
+// MigrationOp is the type of a MigrationChange
+type MigrationOp int
+
+const (
+	MigrationCreate MigrationOp = iota
+	MigrationUpdate
+	MigrationDelete
+)
+
+func (op MigrationOp) String() string {
+	switch op {
+	case MigrationCreate:
+		return "create"
+	case MigrationUpdate:
+		return "update"
+	case MigrationDelete:
+		return "delete"
+	}
+	return fmt.Sprintf("MigrationOp(%d)", int(op))
+}
+
+// MigrationChange is an entry changed by the migrations
+type MigrationChange struct {
+	Op    MigrationOp
+	Table string
+	Key   string
+	Old   Value // Empty for MigrationCreate
+	New   Value // Empty for MigrationDelete
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Exported Functions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+// RegisterMigration adds a migration to the registry. Typically called from
+// the init() of the package defining the migration.
+func RegisterMigration(m Migration) error {
+	if len(m.Version) == 0 || m.Migrate == nil {
+		return fmt.Errorf("Invalid migration %q", m.Version)
+	}
+
+	mutexMigrations.Lock()
+	defer mutexMigrations.Unlock()
+
+	i := sort.Search(len(migrations), func(i int) bool {
+		return natCompare(migrations[i].Version, m.Version) >= 0
+	})
+	if i < len(migrations) && migrations[i].Version == m.Version {
+		return fmt.Errorf("Migration %q is already registered", m.Version)
+	}
+	migrations = append(migrations, Migration{})
+	copy(migrations[i+1:], migrations[i:])
+	migrations[i] = m
+	return nil
+}
+
+// Migrate runs the migrations on the CONFIG_DB in a single transaction, and
+// reports the changes. The changes are validated in the transaction's CVL
+// session. Deletes are performed first, child tables before parent tables;
+// followed by creates and updates, parent tables before child tables, as
+// Import().
+func (d *DB) Migrate(opts *MigrateOptions) (*MigrationReport, error) {
+	if d.Opts.DBNo != ConfigDB {
+		return nil, SupportsCfgDBOnly
+	}
+	if opts == nil {
+		opts = &MigrateOptions{}
+	}
+
+	glog.Infof("Migrate: Begin: %+v", *opts)
+
+	if err := d.StartTx(nil, []*TableSpec{{Name: "*"}}); err != nil {
+		return nil, err
+	}
+
+	report, err := d.migrate(opts)
+	if err != nil || opts.DryRun {
+		d.AbortTx()
+	} else {
+		err = d.CommitTx()
+	}
+
+	if err != nil {
+		glog.Errorf("Migrate: %v", err)
+		return nil, err
+	}
+
+	glog.Infof("Migrate: End: %s -> %s: %d changes", report.FromVersion,
+		report.ToVersion, len(report.Changes))
+	return report, nil
+}
+
+// MigrateFile runs the migrations on a config_db.json style file (Eg:
+// /etc/sonic/config_db.json, or a checkpoint file), and reports the
+// changes. The file is rewritten, in "sonic-cfggen --print-data" format,
+// only if there are changes.
+func MigrateFile(filePath string, opts *MigrateOptions) (*MigrationReport, error) {
+	if opts == nil {
+		opts = &MigrateOptions{}
+	}
+
+	data, err := readImportFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := MigrationData(data).migrate(opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+	report.Source = filePath
+
+	if len(report.Changes) != 0 && !opts.DryRun {
+		if err = writeMigratedFile(filePath, data); err != nil {
+			return nil, err
+		}
+		glog.Infof("MigrateFile: %s: %s -> %s: %d changes", filePath,
+			report.FromVersion, report.ToVersion, len(report.Changes))
+	}
+
+	return report, nil
+}
+
+// MigrateCheckpoints runs the migrations on all the checkpoint files, and
+// rewrites them, so that the older checkpoints can be rolled back to.
+// Returns the report of each of the files. The checkpoint files not migrated
+// here are not changed; they are migrated in memory, every time they are
+// loaded.
+func MigrateCheckpoints(opts *MigrateOptions) ([]*MigrationReport, error) {
+	files, err := filepath.Glob(filepath.Join(CHECKPOINTS_DIR, "*"+CHECKPOINT_EXT))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+	reports := make([]*MigrationReport, 0, len(files))
+	for _, f := range files {
+		report, err := MigrateFile(f, opts)
+		if err != nil {
+			return reports, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// Version returns the VERSIONS|DATABASE VERSION of the data
+func (data MigrationData) Version() string {
+	return data[migrationVersionTable][migrationVersionKey].Field["VERSION"]
+}
+
+// Entry returns the entry of the table; false if it does not exist.
+func (data MigrationData) Entry(table, key string) (Value, bool) {
+	v, ok := data[table][key]
+	return v, ok
+}
+
+// SetEntry sets the entry of the table
+func (data MigrationData) SetEntry(table, key string, value Value) {
+	if data[table] == nil {
+		data[table] = make(map[string]Value)
+	}
+	if len(value.Field) == 0 {
+		value = Value{Field: map[string]string{"NULL": "NULL"}}
+	}
+	data[table][key] = value
+}
+
+// DeleteEntry deletes the entry of the table
+func (data MigrationData) DeleteEntry(table, key string) {
+	delete(data[table], key)
+	if len(data[table]) == 0 {
+		delete(data, table)
+	}
+}
+
+// RenameTable moves the entries of the table from to the table to
+func (data MigrationData) RenameTable(from, to string) {
+	for key, value := range data[from] {
+		data.SetEntry(to, key, value)
+	}
+	delete(data, from)
+}
+
+// RenameField renames the field of all the entries of the table. Use the
+// "@" suffix for leaf-lists.
+func (data MigrationData) RenameField(table, from, to string) {
+	for _, value := range data[table] {
+		if v, ok := value.Field[from]; ok {
+			delete(value.Field, from)
+			value.Field[to] = v
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Internal Functions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+// The version of the migrations is kept in the VERSIONS|DATABASE entry, as
+// by the db_migrator.
+const (
+	migrationVersionTable = "VERSIONS"
+	migrationVersionKey   = "DATABASE"
+)
+
+// migrations are the registered migrations, in the natural order of their
+// versions.
+var migrations []Migration
+
+var mutexMigrations sync.Mutex
+
+// pendingMigrations returns the migrations after the fromVersion, upto the
+// toVersion (all, if empty).
+func pendingMigrations(fromVersion, toVersion string) []Migration {
+	mutexMigrations.Lock()
+	defer mutexMigrations.Unlock()
+
+	var pending []Migration
+	for _, m := range migrations {
+		if len(toVersion) != 0 && natCompare(m.Version, toVersion) > 0 {
+			break
+		}
+		if len(fromVersion) == 0 || natCompare(m.Version, fromVersion) > 0 {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+// migrate runs the pending migrations on the data, and reports the changes.
+// The data is left unchanged, if any of the migrations fail.
+func (data MigrationData) migrate(opts *MigrateOptions) (*MigrationReport, error) {
+	report := &MigrationReport{FromVersion: data.Version()}
+	pending := pendingMigrations(report.FromVersion, opts.ToVersion)
+	if len(pending) == 0 {
+		report.ToVersion = report.FromVersion
+		return report, nil
+	}
+
+	after := data.copy()
+	for _, m := range pending {
+		glog.Infof("migrate: %s: %s", m.Version, m.Description)
+		if err := m.Migrate(after); err != nil {
+			return nil, fmt.Errorf("Migration %s failed: %w", m.Version, err)
+		}
+		version, _ := after.Entry(migrationVersionTable, migrationVersionKey)
+		version = version.Copy()
+		version.Field["VERSION"] = m.Version
+		delete(version.Field, "NULL")
+		after.SetEntry(migrationVersionTable, migrationVersionKey, version)
+		report.Applied = append(report.Applied, m.Version)
+	}
+
+	report.ToVersion = after.Version()
+	report.Changes = data.diff(after)
+
+	// Update the data in place
+	for table := range data {
+		delete(data, table)
+	}
+	for table, entries := range after {
+		data[table] = entries
+	}
+	return report, nil
+}
+
+func (data MigrationData) copy() MigrationData {
+	c := make(MigrationData, len(data))
+	for table, entries := range data {
+		c[table] = make(map[string]Value, len(entries))
+		for key, value := range entries {
+			c[table][key] = value.Copy()
+		}
+	}
+	return c
+}
+
+// diff returns the changes from data to after, ordered by the table, and
+// key.
+func (data MigrationData) diff(after MigrationData) []MigrationChange {
+	var changes []MigrationChange
+	for table, entries := range data {
+		for key, old := range entries {
+			if value, ok := after[table][key]; !ok {
+				changes = append(changes, MigrationChange{Op: MigrationDelete,
+					Table: table, Key: key, Old: old})
+			} else if !value.Equals(&old) {
+				changes = append(changes, MigrationChange{Op: MigrationUpdate,
+					Table: table, Key: key, Old: old, New: value})
+			}
+		}
+	}
+	for table, entries := range after {
+		for key, value := range entries {
+			if _, ok := data[table][key]; !ok {
+				changes = append(changes, MigrationChange{Op: MigrationCreate,
+					Table: table, Key: key, New: value})
+			}
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Table != changes[j].Table {
+			return changes[i].Table < changes[j].Table
+		}
+		return natCompare(changes[i].Key, changes[j].Key) < 0
+	})
+	return changes
+}
+
+// migrate runs the migrations on the DB contents, and writes the changes.
+// Should be called within a transaction.
+func (d *DB) migrate(opts *MigrateOptions) (*MigrationReport, error) {
+	tables, err := d.GetConfig(nil, &GetConfigOptions{AllowWritable: true})
+	if err != nil {
+		return nil, err
+	}
+
+	data := make(MigrationData, len(tables))
+	for ts, table := range tables {
+		entries := make(map[string]Value, len(table.entry))
+		for redisKey, value := range table.entry {
+			key := d.redis2key(&ts, redisKey)
+			entries[strings.Join(key.Comp, d.Opts.KeySeparator)] = value.Copy()
+		}
+		data[ts.Name] = entries
+	}
+
+	report, err := data.migrate(opts)
+	if err != nil {
+		return nil, err
+	}
+	report.Source = d.Name()
+
+	names := make([]string, 0, len(report.Changes))
+	changes := make(map[string][]MigrationChange)
+	for _, c := range report.Changes {
+		if _, ok := changes[c.Table]; !ok {
+			names = append(names, c.Table)
+		}
+		changes[c.Table] = append(changes[c.Table], c)
+	}
+	order := d.importTableOrder(names)
+
+	// Deletes, child tables first
+	for i := len(order) - 1; i >= 0; i-- {
+		ts := &TableSpec{Name: order[i]}
+		for _, c := range changes[ts.Name] {
+			if c.Op != MigrationDelete {
+				continue
+			}
+			key := Key{Comp: strings.Split(c.Key, d.Opts.KeySeparator)}
+			if err = d.DeleteEntry(ts, key); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Creates and updates, parent tables first
+	for _, name := range order {
+		ts := &TableSpec{Name: name}
+		for _, c := range changes[name] {
+			key := Key{Comp: strings.Split(c.Key, d.Opts.KeySeparator)}
+			switch c.Op {
+			case MigrationCreate:
+				err = d.CreateEntry(ts, key, c.New)
+			case MigrationUpdate:
+				err = d.SetEntry(ts, key, c.New)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// writeMigratedFile rewrites the config file with the data; via a
+// temporary file in the same directory, which is renamed to the file.
+func writeMigratedFile(filePath string, data MigrationData) error {
+	f, err := createFile(filePath + ".*")
+	if err != nil {
+		return fmt.Errorf("Failed to create migrated file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if fi, err := os.Stat(filePath); err == nil {
+		f.Chmod(fi.Mode().Perm())
+	}
+
+	jData := make(map[string]map[string]map[string]interface{}, len(data))
+	for table, entries := range data {
+		entryMap := make(map[string]map[string]interface{}, len(entries))
+		for key, value := range entries {
+			entryMap[key] = exportValue(value)
+		}
+		jData[table] = entryMap
+	}
+
+	w := bufio.NewWriter(f)
+	writeCfggenJSON(w, jData, "|")
+	if err = w.Flush(); err == nil {
+		err = f.Close()
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), filePath)
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to write migrated file: %w", err)
+	}
+	return nil
+}
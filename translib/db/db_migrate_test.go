@@ -0,0 +1,270 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Azure/sonic-mgmt-common/cvl"
+)
+
+// useMigrations replaces the registered migrations with the ms; for the
+// duration of the test.
+func useMigrations(t *testing.T, ms ...Migration) {
+	mutexMigrations.Lock()
+	prev := migrations
+	migrations = nil
+	mutexMigrations.Unlock()
+	t.Cleanup(func() {
+		mutexMigrations.Lock()
+		migrations = prev
+		mutexMigrations.Unlock()
+	})
+
+	for _, m := range ms {
+		if err := RegisterMigration(m); err != nil {
+			t.Fatalf("RegisterMigration(%s) failed; err=%v", m.Version, err)
+		}
+	}
+}
+
+// useTestMigrations replaces the registered migrations with the test
+// migrations; for the duration of the test.
+func useTestMigrations(t *testing.T) {
+	// version_1_0_10 must run after version_1_0_2
+	testMigrations := []Migration{{
+		Version:     "version_1_0_10",
+		Description: "Rename PORT speed_mbps to speed",
+		Migrate: func(data MigrationData) error {
+			data.RenameField("PORT", "speed_mbps", "speed")
+			return nil
+		},
+	}, {
+		Version:     "version_1_0_2",
+		Description: "Rename OLD_VLAN table to VLAN, and convert the keys",
+		Migrate: func(data MigrationData) error {
+			for key, value := range data["OLD_VLAN"] {
+				data.SetEntry("VLAN", "Vlan"+strings.TrimPrefix(key, "vlan"), value)
+			}
+			delete(data, "OLD_VLAN")
+			return nil
+		},
+	}}
+	useMigrations(t, testMigrations...)
+	if err := RegisterMigration(testMigrations[0]); err == nil {
+		t.Fatalf("RegisterMigration() with duplicate version did not fail")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	useMemoryBackend(t)
+	useTestMigrations(t)
+	d := openTestDB(t, ConfigDB, false)
+	setup := map[string]map[string]string{
+		"VERSIONS|DATABASE": {"VERSION": "version_1_0_1"},
+		"PORT|Ethernet0":    {"mtu": "9100", "speed_mbps": "100000"},
+		"OLD_VLAN|vlan10":   {"vlanid": "10"},
+	}
+	for k, v := range setup {
+		ts, key := d.redis2ts_key(k)
+		if err := d.SetEntry(&ts, key, Value{Field: v}); err != nil {
+			t.Fatalf("SetEntry(%s) failed; err=%v", k, err)
+		}
+	}
+
+	report, err := d.Migrate(&MigrateOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Migrate(DryRun) failed; err=%v", err)
+	}
+	if !reflect.DeepEqual(report.Applied, []string{"version_1_0_2", "version_1_0_10"}) {
+		t.Errorf("Migrate(DryRun) applied %v", report.Applied)
+	}
+	var changes []string
+	for _, c := range report.Changes {
+		changes = append(changes, c.Op.String()+" "+c.Table+"|"+c.Key)
+	}
+	expChanges := []string{"delete OLD_VLAN|vlan10", "update PORT|Ethernet0",
+		"update VERSIONS|DATABASE", "create VLAN|Vlan10"}
+	if !reflect.DeepEqual(changes, expChanges) {
+		t.Errorf("Migrate(DryRun) changes = %v; expected %v", changes, expChanges)
+	}
+	if v, _ := d.GetEntry(&TableSpec{Name: "PORT"}, Key{Comp: []string{"Ethernet0"}}); !reflect.DeepEqual(v.Field, setup["PORT|Ethernet0"]) {
+		t.Errorf("Migrate(DryRun) changed PORT|Ethernet0 to %v", v.Field)
+	}
+
+	if report, err = d.Migrate(&MigrateOptions{ToVersion: "version_1_0_2"}); err != nil {
+		t.Fatalf("Migrate(version_1_0_2) failed; err=%v", err)
+	}
+	if report.ToVersion != "version_1_0_2" || len(report.Changes) != 3 {
+		t.Errorf("Migrate(version_1_0_2) = %+v", report)
+	}
+	if report, err = d.Migrate(nil); err != nil {
+		t.Fatalf("Migrate() failed; err=%v", err)
+	}
+	if report.FromVersion != "version_1_0_2" || report.ToVersion != "version_1_0_10" {
+		t.Errorf("Migrate() = %+v", report)
+	}
+
+	exp := map[string]map[string]string{
+		"VERSIONS|DATABASE": {"VERSION": "version_1_0_10"},
+		"PORT|Ethernet0":    {"mtu": "9100", "speed": "100000"},
+		"VLAN|Vlan10":       {"vlanid": "10"},
+	}
+	for k, e := range exp {
+		ts, key := d.redis2ts_key(k)
+		if v, err := d.GetEntry(&ts, key); err != nil || !reflect.DeepEqual(v.Field, e) {
+			t.Errorf("Migrate() %s = %v, %v; expected %v", k, v.Field, err, e)
+		}
+	}
+	if _, err = d.GetEntry(&TableSpec{Name: "OLD_VLAN"}, Key{Comp: []string{"vlan10"}}); !isNotExist(err) {
+		t.Errorf("Migrate() retained OLD_VLAN|vlan10; err=%v", err)
+	}
+
+	if report, err = d.Migrate(nil); err != nil || len(report.Applied) != 0 {
+		t.Errorf("Migrate() again = %+v, %v", report, err)
+	}
+}
+
+func TestMigrateFile(t *testing.T) {
+	useTestMigrations(t)
+	cfgFile := filepath.Join(t.TempDir(), "config_db.json")
+	ioutil.WriteFile(cfgFile, []byte(`{
+		"OLD_VLAN": {
+			"vlan10": {"vlanid": "10", "members": ["Ethernet0"]}
+		},
+		"PORT": {
+			"Ethernet0": {"speed_mbps": "100000"}
+		}
+	}`), 0600)
+
+	report, err := MigrateFile(cfgFile, &MigrateOptions{DryRun: true})
+	if err != nil || len(report.Changes) != 4 {
+		t.Fatalf("MigrateFile(DryRun) = %+v, %v", report, err)
+	}
+	if data, _ := readImportFile(cfgFile); data["OLD_VLAN"] == nil {
+		t.Fatalf("MigrateFile(DryRun) changed the file")
+	}
+
+	if report, err = MigrateFile(cfgFile, nil); err != nil {
+		t.Fatalf("MigrateFile() failed; err=%v", err)
+	}
+	data, err := readImportFile(cfgFile)
+	if err != nil {
+		t.Fatalf("Failed to read migrated file; err=%v", err)
+	}
+	exp := map[string]map[string]Value{
+		"PORT":     {"Ethernet0": {Field: map[string]string{"speed": "100000"}}},
+		"VLAN":     {"Vlan10": {Field: map[string]string{"vlanid": "10", "members@": "Ethernet0"}}},
+		"VERSIONS": {"DATABASE": {Field: map[string]string{"VERSION": "version_1_0_10"}}},
+	}
+	if !reflect.DeepEqual(data, exp) {
+		t.Errorf("MigrateFile() = %v; expected %v", data, exp)
+	}
+
+	// Checkpoints are migrated in memory when loaded; the file is not changed
+	cpData := []byte(`{"OLD_VLAN": {"vlan20": {"vlanid": "20"}}}`)
+	ioutil.WriteFile(cfgFile, cpData, 0600)
+	cpDs, err := loadCheckpoint(cfgFile, "|")
+	if err != nil {
+		t.Fatalf("loadCheckpoint() failed; err=%v", err)
+	}
+	if v := cpDs.entries["VLAN|Vlan20"]; v.Field["vlanid"] != "20" {
+		t.Errorf("loadCheckpoint() did not migrate OLD_VLAN|vlan20; entries=%v", cpDs.entries)
+	}
+	if v := cpDs.entries["VERSIONS|DATABASE"]; v.Field["VERSION"] != "version_1_0_10" {
+		t.Errorf("loadCheckpoint() VERSIONS|DATABASE = %v", v.Field)
+	}
+	if b, _ := ioutil.ReadFile(cfgFile); !bytes.Equal(b, cpData) {
+		t.Errorf("loadCheckpoint() rewrote the checkpoint; data=%s", b)
+	}
+	if report, err = MigrateFile(cfgFile, &MigrateOptions{DryRun: true}); err != nil ||
+		len(report.Applied) != 2 {
+		t.Errorf("Loaded checkpoint does not have the pending migrations; report=%+v, err=%v", report, err)
+	}
+}
+
+// TestMigrateCVL runs the migrations with the CVL validation, on the
+// redis-server; an invalid migrated entry fails the Migrate.
+func TestMigrateCVL(t *testing.T) {
+	useRedisServer(t)
+	for _, key := range []string{"VERSIONS|DATABASE", "VLAN|Vlan3001"} {
+		if tbl, _ := cvl.SplitRedisKey(key); tbl == "" {
+			t.Skipf("CVL schema does not have %s", key)
+		}
+	}
+	useMigrations(t, Migration{
+		Version:     "version_1_0_2",
+		Description: "Set the VLAN mtu",
+		Migrate: func(data MigrationData) error {
+			data.RenameField("VLAN", "mtu_old", "mtu")
+			return nil
+		},
+	}, Migration{
+		Version:     "version_1_0_3",
+		Description: "Invalid VLAN vlanid",
+		Migrate: func(data MigrationData) error {
+			for _, value := range data["VLAN"] {
+				value.Field["vlanid"] = "5000"
+			}
+			return nil
+		},
+	})
+
+	// Legacy data, set without the CVL
+	setup := openTestDB(t, ConfigDB, false)
+	setup.SetEntry(&TableSpec{Name: "VERSIONS"}, *NewKey("DATABASE"),
+		Value{Field: map[string]string{"VERSION": "version_1_0_1"}})
+	setup.SetEntry(&TableSpec{Name: "VLAN"}, *NewKey("Vlan3001"),
+		Value{Field: map[string]string{"vlanid": "3001", "mtu_old": "9100"}})
+
+	d, err := NewDB(Options{
+		DBNo:               ConfigDB,
+		TableNameSeparator: "|",
+		KeySeparator:       "|",
+	})
+	if err != nil {
+		t.Fatalf("NewDB() fails e = %v", err)
+	}
+	defer d.DeleteDB()
+
+	if report, err := d.Migrate(&MigrateOptions{ToVersion: "version_1_0_2"}); err != nil ||
+		report.ToVersion != "version_1_0_2" {
+		t.Fatalf("Migrate(version_1_0_2) = %+v, %v", report, err)
+	}
+	if _, err := d.Migrate(nil); err == nil {
+		t.Fatalf("Migrate() with an invalid vlanid did not fail")
+	}
+
+	exp := map[string]map[string]string{
+		"VERSIONS|DATABASE": {"VERSION": "version_1_0_2"},
+		"VLAN|Vlan3001":     {"vlanid": "3001", "mtu": "9100"},
+	}
+	for k, e := range exp {
+		ts, key := d.redis2ts_key(k)
+		if v, err := d.GetEntry(&ts, key); err != nil || !reflect.DeepEqual(v.Field, e) {
+			t.Errorf("Migrate() %s = %v, %v; expected %v", k, v.Field, err, e)
+		}
+	}
+}
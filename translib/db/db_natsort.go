@@ -0,0 +1,95 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"strings"
+)
+
+// natCompare compares two strings in natural order -- digit sequences are
+// compared by their numeric value and other parts lexicographically.
+// Eg, "Ethernet2" < "Ethernet10". Follows the ordering of python natsort
+// library (default options), which is used by sonic-cfggen.
+// Returns a negative value if a < b, positive if a > b and 0 if a == b.
+func natCompare(a, b string) int {
+	ca, cb := natChunks(a), natChunks(b)
+	for i := 0; i < len(ca) && i < len(cb); i++ {
+		var r int
+		if i%2 == 0 { // even chunks are non-digit parts
+			r = strings.Compare(ca[i], cb[i])
+		} else {
+			r = compareNumStrings(ca[i], cb[i])
+		}
+		if r != 0 {
+			return r
+		}
+	}
+	switch {
+	case len(ca) < len(cb):
+		return -1
+	case len(ca) > len(cb):
+		return 1
+	}
+	// Natural equal strings like "a01" and "a1"; fallback to plain compare
+	// to keep the order deterministic.
+	return strings.Compare(a, b)
+}
+
+// natCompareList compares string slices element by element using natCompare.
+// A shorter slice is less than the longer one if all its elements match.
+func natCompareList(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if r := natCompare(a[i], b[i]); r != 0 {
+			return r
+		}
+	}
+	return len(a) - len(b)
+}
+
+// natChunks splits s into alternate non-digit and digit parts. First chunk
+// is always a non-digit part (can be empty if s starts with a digit).
+func natChunks(s string) []string {
+	var chunks []string
+	start, digit := 0, false
+	for i := 0; i < len(s); i++ {
+		if d := isDigit(s[i]); d != digit {
+			chunks = append(chunks, s[start:i])
+			start, digit = i, d
+		}
+	}
+	if start < len(s) || len(chunks) == 0 {
+		chunks = append(chunks, s[start:])
+	}
+	return chunks
+}
+
+// compareNumStrings compares two decimal digit strings by their numeric value.
+func compareNumStrings(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	return strings.Compare(a, b)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -107,6 +107,30 @@ func (d *DB) OnChangeCacheDelete(ts *TableSpec, key Key) (Value, error) {
 	return valueOrig, nil
 }
 
+// OnChangeCacheKeys returns the keys of the on_change cache entries of the
+// table, that match the pattern. Used to find the entries deleted while the
+// notifications were lost (SEventResync).
+func (d *DB) OnChangeCacheKeys(ts *TableSpec, pat Key) ([]Key, error) {
+	if glog.V(3) {
+		glog.Info("OnChangeCacheKeys: Begin: ", "ts:", ts, " pat:", pat)
+	}
+	if !d.IsOpen() {
+		return nil, ConnectionClosed
+	}
+	if !d.Opts.IsOnChangeEnabled {
+		return nil, OnChangeDisabled
+	}
+
+	var keys []Key
+	for entry := range d.cache.Tables[ts.Name].entry {
+		if key := d.redis2key(ts, entry); key.Matches(pat) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 //  Internal Functions                                                        //
 ////////////////////////////////////////////////////////////////////////////////
@@ -28,6 +28,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Azure/sonic-mgmt-common/internal/dbauth"
 	"github.com/go-redis/redis/v7"
 	"github.com/golang/glog"
 )
@@ -64,8 +65,8 @@ var goRedisOptsOnce sync.Once // Command line options handled only once
 
 func setGoRedisOpts(optsString string) {
 	// Command Line Options have higher priority on startup. After that, on
-	// receipt of a signal (SIGUSR2?), the TRANSLIB_DB|default
-	// "go_redis_opts" will have higher priority.
+	// a runtime config reload (SIGUSR2, or a change notification), the
+	// TRANSLIB_DB|default "go_redis_opts" will have higher priority.
 	if optsString != "" {
 		glog.Infof("setGoRedisOpts: optsString: %s", optsString)
 		// On startup, command-line has priority. Skip reconfigure from DB
@@ -88,18 +89,22 @@ func adjustRedisOpts(dbOpt *Options) *redis.Options {
 	addr := DefaultRedisLocalTCPEP
 	dbId := int(dbOpt.DBNo)
 	dbPassword := ""
-	if dbInstName := getDBInstName(dbOpt.DBNo); dbInstName != "" {
-		if isDbInstPresent(dbInstName) {
-			if dbSock = getDbSock(dbInstName); dbSock != "" {
+	dbAuth := false
+	ns := dbOpt.Namespace
+	dbInstName := getDBInstName(dbOpt.DBNo)
+	if dbInstName != "" {
+		if isDbInstPresent(ns, dbInstName) {
+			dbAuth = true
+			if dbSock = getDbSock(ns, dbInstName); dbSock != "" {
 				dbNetwork = DefaultRedisUNIXNetwork
 				addr = dbSock
 			} else {
 				dbNetwork = DefaultRedisTCPNetwork
-				addr = getDbTcpAddr(dbInstName)
+				addr = getDbTcpAddr(ns, dbInstName)
 			}
-			dbId = getDbId(dbInstName)
-			dbSepStr := getDbSeparator(dbInstName)
-			dbPassword = getDbPassword(dbInstName)
+			dbId = getDbId(ns, dbInstName)
+			dbSepStr := getDbSeparator(ns, dbInstName)
+			dbPassword = getDbPassword(ns, dbInstName)
 			if len(dbSepStr) > 0 {
 				if len(dbOpt.TableNameSeparator) > 0 &&
 					dbOpt.TableNameSeparator != dbSepStr {
@@ -116,7 +121,7 @@ func adjustRedisOpts(dbOpt *Options) *redis.Options {
 			}
 		} else {
 			glog.Warning("Database instance not present for the Db name: ",
-				dbInstName)
+				dbInstName, " namespace: ", ns)
 		}
 	} else {
 		glog.Errorf("Invalid database number %d", dbId)
@@ -126,6 +131,11 @@ func adjustRedisOpts(dbOpt *Options) *redis.Options {
 	redisOpts.Addr = addr
 	redisOpts.Password = dbPassword
 	redisOpts.DB = dbId
+	redisOpts.Dialer = backendDialer()
+
+	if dbAuth {
+		setDbAuth(&redisOpts, ns, dbInstName)
+	}
 
 	// redisOpts.DialTimeout = 0 // Default
 
@@ -144,8 +154,32 @@ func adjustRedisOpts(dbOpt *Options) *redis.Options {
 	return &redisOpts
 }
 
+// setDbAuth sets the redis ACL user, and the TLS config (TCP only) of the
+// DB instance in the redis.Options. See dbauth.SetOptions.
+func setDbAuth(redisOpts *redis.Options, ns, dbInstName string) {
+	err := dbauth.SetOptions(redisOpts, ns+"/"+dbInstName, getDbInst(ns, dbInstName))
+	if err != nil {
+		glog.Errorf("setDbAuth: %s/%s TLS config: %v", ns, dbInstName, err)
+	}
+}
+
 func init() {
 	flag.StringVar(&goRedisOpts, "go_redis_opts", "", "Options for go-redis")
+
+	RegisterRuntimeConfig(RuntimeConfigComponent[_DBRedisOptsConfig]{
+		Name: "go_redis_opts",
+		Parse: func(fields map[string]string) _DBRedisOptsConfig {
+			var config _DBRedisOptsConfig
+			config.parseFields(fields)
+			return config
+		},
+		OnChange: func(_, config _DBRedisOptsConfig) { setDBRedisOptsConfig(config) },
+		Current: func() _DBRedisOptsConfig {
+			mutexRedisOptsConfig.Lock()
+			defer mutexRedisOptsConfig.Unlock()
+			return *dbRedisOptsConfig
+		},
+	})
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -174,17 +208,20 @@ func (config *_DBRedisOptsConfig) reconfigure() error {
 		glog.Infof("_DBRedisOptsConfig:reconfigure: Handling signal.")
 		var readDBRedisOptsConfig _DBRedisOptsConfig
 		readDBRedisOptsConfig.readFromDB()
-
-		mutexRedisOptsConfig.Lock()
-		if !reflect.DeepEqual(*config, readDBRedisOptsConfig) {
-			glog.Infof("_DBRedisOptsConfig:reconfigure: Change Detected.")
-			dbRedisOptsConfig = &readDBRedisOptsConfig
-		}
-		mutexRedisOptsConfig.Unlock()
+		setDBRedisOptsConfig(readDBRedisOptsConfig)
 	}
 	return nil
 }
 
+func setDBRedisOptsConfig(config _DBRedisOptsConfig) {
+	mutexRedisOptsConfig.Lock()
+	if !reflect.DeepEqual(*dbRedisOptsConfig, config) {
+		glog.Infof("_DBRedisOptsConfig:reconfigure: Change Detected.")
+		dbRedisOptsConfig = &config
+	}
+	mutexRedisOptsConfig.Unlock()
+}
+
 func (config *_DBRedisOptsConfig) handleReconfigureSignal() error {
 	mutexRedisOptsConfig.Lock()
 	reconfigureRedisOptsConfig = true
@@ -197,16 +234,21 @@ func (config *_DBRedisOptsConfig) handleReconfigureSignal() error {
 ////////////////////////////////////////////////////////////////////////////////
 
 func (config *_DBRedisOptsConfig) readFromDB() error {
-	fields, e := readRedis("TRANSLIB_DB|default")
+	fields, e := readRuntimeConfig()
 	if e == nil {
-		if optsString, ok := fields["go_redis_opts"]; ok {
-			// Parse optsString into config.opts
-			config.parseRedisOptsConfig(optsString)
-		}
+		config.parseFields(fields)
 	}
 	return e
 }
 
+// parseFields sets the config from the TRANSLIB_DB|default fields
+func (config *_DBRedisOptsConfig) parseFields(fields map[string]string) {
+	if optsString, ok := fields["go_redis_opts"]; ok {
+		// Parse optsString into config.opts
+		config.parseRedisOptsConfig(optsString)
+	}
+}
+
 func (config *_DBRedisOptsConfig) parseRedisOptsConfig(optsString string) error {
 	var e, optSAErr error
 	var intVal int64
@@ -556,3 +556,58 @@ func TestGetEntries5(t *testing.T) {
 
 	deleteTableAndDb(d, &ts, t)
 }
+
+func TestGetMultiTableEntries(t *testing.T) {
+	useMemoryBackend(t)
+	d := openTestDB(t, ConfigDB, false)
+	port := &TableSpec{Name: "PIPE_PORT"}
+	intf := &TableSpec{Name: "PIPE_INTERFACE"}
+	d.SetEntry(port, *NewKey("Ethernet0"), Value{Field: map[string]string{"mtu": "9100"}})
+	d.SetEntry(port, *NewKey("Ethernet4"), Value{Field: map[string]string{"mtu": "1500"}})
+	d.SetEntry(intf, *NewKey("Ethernet0", "10.0.0.1/31"), Value{Field: map[string]string{"NULL": "NULL"}})
+
+	// Tx cache entries are not read from the DB
+	if err := d.StartTx(nil, nil); err != nil {
+		t.Fatalf("StartTx() fails e = %v", err)
+	}
+	defer d.AbortTx()
+	d.ModEntry(port, *NewKey("Ethernet4"), Value{Field: map[string]string{"mtu": "9000"}})
+
+	values, errors := d.GetMultiTableEntries([]TableKeys{
+		{Ts: port, Keys: []Key{*NewKey("Ethernet0"), *NewKey("Ethernet4"), *NewKey("Ethernet8")}},
+		{Ts: intf, Keys: []Key{*NewKey("Ethernet0", "10.0.0.1/31")}},
+	})
+
+	if len(values) != 2 || len(values[0]) != 3 || len(values[1]) != 1 {
+		t.Fatalf("GetMultiTableEntries() = %v", values)
+	}
+	if values[0][0].Get("mtu") != "9100" || values[0][1].Get("mtu") != "9000" ||
+		values[1][0].Get("NULL") != "NULL" {
+		t.Errorf("GetMultiTableEntries() = %v", values)
+	}
+	if errors[0] == nil || errors[0][0] != nil || !isNotExist(errors[0][2]) || errors[1] != nil {
+		t.Errorf("GetMultiTableEntries() errors = %v", errors)
+	}
+}
+
+// TestGetMultiTableEntriesPipeError tests that an error of one key fails all
+// the keys, of all the tables, read by the pipeline.
+func TestGetMultiTableEntriesPipeError(t *testing.T) {
+	useMemoryBackend(t)
+	d := openTestDB(t, ConfigDB, false)
+	port := &TableSpec{Name: "PIPE_PORT"}
+	intf := &TableSpec{Name: "PIPE_INTERFACE"}
+	d.SetEntry(port, *NewKey("Ethernet0"), Value{Field: map[string]string{"mtu": "9100"}})
+	openTestClient(t, ConfigDB).Set("PIPE_INTERFACE|Ethernet0", "not-a-hash", 0)
+
+	values, errors := d.GetMultiTableEntries([]TableKeys{
+		{Ts: port, Keys: []Key{*NewKey("Ethernet0")}},
+		{Ts: intf, Keys: []Key{*NewKey("Ethernet0")}},
+	})
+	for i := range values {
+		if errors[i] == nil || !isNotExist(errors[i][0]) || values[i][0].IsPopulated() {
+			t.Errorf("GetMultiTableEntries()[%d] = %v, %v; expected the pipeline error",
+				i, values[i], errors[i])
+		}
+	}
+}
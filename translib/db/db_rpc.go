@@ -0,0 +1,351 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Azure/sonic-mgmt-common/translib/tlerr"
+	"github.com/go-redis/redis/v7"
+	"github.com/golang/glog"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+//  Exported Types                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+// RpcError is the structured error of an RPC response.
+type RpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// RpcError Codes. Handlers may use their own (positive) codes.
+const (
+	RpcErrNoListener     = -32000 // No responder subscribed (client side)
+	RpcErrParse          = -32700 // Request could not be decoded
+	RpcErrMethodNotFound = -32601
+	RpcErrInvalidParams  = -32602
+	RpcErrInternal       = -32603 // Handler returned a non RpcError error
+)
+
+// RpcHandler serves an RPC request. The result is JSON encoded into the
+// response. An *RpcError is returned to the caller as is; any other error
+// as RpcErrInternal.
+type RpcHandler func(ctx context.Context, params json.RawMessage) (interface{}, error)
+
+// RpcClient sends requests on the request channels, and correlates the
+// responses received on its response channel by the request ID. It is safe
+// for concurrent use; several RpcClients (even across processes) may share
+// a response channel, since the responses of other requests are ignored.
+type RpcClient struct {
+	d       *DB
+	channel string // Response channel
+
+	mu      sync.Mutex
+	pending map[string]chan *rpcResponse // Request ID -> waiting caller
+	closed  bool
+	done    chan struct{} // Closed on Close()
+}
+
+// RpcServer serves the requests received on its request channel, with the
+// RpcHandlers registered for the methods. Each request is served in its own
+// goroutine.
+type RpcServer struct {
+	d       *DB
+	channel string // Request channel
+
+	mu       sync.RWMutex
+	handlers map[string]RpcHandler
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Exported Functions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+func (e *RpcError) Error() string {
+	return fmt.Sprintf("RPC error %d: %s", e.Code, e.Message)
+}
+
+// NewRpcClient opens an RpcClient, subscribed to the responseChannel.
+func NewRpcClient(opt Options, responseChannel string) (*RpcClient, error) {
+	d, e := PubSubRpcDB(opt, responseChannel)
+	if e != nil {
+		return nil, e
+	}
+
+	c := &RpcClient{
+		d:       d,
+		channel: responseChannel,
+		pending: make(map[string]chan *rpcResponse),
+		done:    make(chan struct{}),
+	}
+	go c.receive(d.rPubSub.Channel())
+
+	return c, nil
+}
+
+// Call sends the method request, with the params JSON encoded, on the
+// requestChannel, and waits for its response, or the ctx to be done. The
+// response result is JSON decoded into the result, if not nil. An error
+// response is returned as an *RpcError.
+func (c *RpcClient) Call(ctx context.Context, requestChannel string,
+	method string, params interface{}, result interface{}) error {
+
+	req := rpcRequest{ID: newRpcId(), ReplyTo: c.channel, Method: method}
+	if deadline, ok := ctx.Deadline(); ok {
+		req.Deadline = deadline.UnixNano() / int64(time.Millisecond)
+	}
+
+	var e error
+	if params != nil {
+		if req.Params, e = json.Marshal(params); e != nil {
+			return e
+		}
+	}
+	msg, e := json.Marshal(&req)
+	if e != nil {
+		return e
+	}
+
+	respCh := make(chan *rpcResponse, 1)
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return ConnectionClosed
+	}
+	c.pending[req.ID] = respCh
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, req.ID)
+		c.mu.Unlock()
+	}()
+
+	if glog.V(3) {
+		glog.Info("RpcClient.Call: ", requestChannel, ": ", string(msg))
+	}
+
+	listeners, e := c.d.client.Publish(requestChannel, msg).Result()
+	if e != nil {
+		return e
+	} else if listeners == 0 {
+		return &RpcError{Code: RpcErrNoListener,
+			Message: "No listener on " + requestChannel}
+	}
+
+	select {
+	case resp := <-respCh:
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result != nil && len(resp.Result) != 0 {
+			return json.Unmarshal(resp.Result, result)
+		}
+		return nil
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return tlerr.TranslibTimeoutError{}
+		}
+		return ctx.Err()
+	case <-c.done:
+		return ConnectionClosed
+	}
+}
+
+// Close closes the RpcClient. The pending Calls return ConnectionClosed.
+func (c *RpcClient) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	close(c.done)
+	c.mu.Unlock()
+
+	return c.d.ClosePubSubRpcDB()
+}
+
+// NewRpcServer opens an RpcServer, subscribed to the requestChannel. The
+// requests are served once Serve is called.
+func NewRpcServer(opt Options, requestChannel string) (*RpcServer, error) {
+	d, e := PubSubRpcDB(opt, requestChannel)
+	if e != nil {
+		return nil, e
+	}
+
+	return &RpcServer{
+		d:        d,
+		channel:  requestChannel,
+		handlers: make(map[string]RpcHandler),
+	}, nil
+}
+
+// Handle registers the handler of the method.
+func (s *RpcServer) Handle(method string, handler RpcHandler) {
+	s.mu.Lock()
+	s.handlers[method] = handler
+	s.mu.Unlock()
+}
+
+// Serve serves the requests till the ctx is done, or the RpcServer is
+// closed. The handlers get a ctx derived from this ctx, with the deadline
+// of the caller, if any.
+func (s *RpcServer) Serve(ctx context.Context) error {
+	ch := s.d.rPubSub.Channel()
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				return ConnectionClosed
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.serve(ctx, msg.Payload)
+			}()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Close closes the RpcServer; Serve returns.
+func (s *RpcServer) Close() error {
+	return s.d.ClosePubSubRpcDB()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Internal Types                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+type rpcRequest struct {
+	ID       string          `json:"id"`
+	ReplyTo  string          `json:"reply_to"`
+	Method   string          `json:"method"`
+	Params   json.RawMessage `json:"params,omitempty"`
+	Deadline int64           `json:"deadline,omitempty"` // Unix time (ms)
+}
+
+type rpcResponse struct {
+	ID     string          `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *RpcError       `json:"error,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Internal Functions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+var rpcIdPrefix = strconv.Itoa(os.Getpid()) + "." +
+	strconv.FormatInt(time.Now().UnixNano(), 36) + "."
+var rpcIdSeq uint64
+
+// newRpcId gives a request ID, unique across the processes.
+func newRpcId() string {
+	return rpcIdPrefix + strconv.FormatUint(atomic.AddUint64(&rpcIdSeq, 1), 10)
+}
+
+// receive hands the responses over to the waiting callers.
+func (c *RpcClient) receive(ch <-chan *redis.Message) {
+	for msg := range ch {
+		var resp rpcResponse
+		if e := json.Unmarshal([]byte(msg.Payload), &resp); e != nil {
+			glog.Warning("RpcClient: ", c.channel, ": bad response: ", e)
+			continue
+		}
+
+		c.mu.Lock()
+		respCh, ok := c.pending[resp.ID]
+		c.mu.Unlock()
+
+		if !ok {
+			if glog.V(3) {
+				glog.Info("RpcClient: ", c.channel, ": ignore response: ",
+					resp.ID)
+			}
+			continue
+		}
+		// Only the first response counts, if several responders
+		select {
+		case respCh <- &resp:
+		default:
+		}
+	}
+}
+
+// serve runs the handler of the request, and publishes its response.
+func (s *RpcServer) serve(ctx context.Context, payload string) {
+	var req rpcRequest
+	if e := json.Unmarshal([]byte(payload), &req); e != nil ||
+		len(req.ID) == 0 || len(req.ReplyTo) == 0 {
+		glog.Warning("RpcServer: ", s.channel, ": bad request: ", payload)
+		return
+	}
+
+	resp := rpcResponse{ID: req.ID}
+
+	s.mu.RLock()
+	handler, ok := s.handlers[req.Method]
+	s.mu.RUnlock()
+
+	if !ok {
+		resp.Error = &RpcError{Code: RpcErrMethodNotFound,
+			Message: "Method not found: " + req.Method}
+	} else {
+		if req.Deadline != 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithDeadline(ctx,
+				time.Unix(0, req.Deadline*int64(time.Millisecond)))
+			defer cancel()
+		}
+
+		result, e := handler(ctx, req.Params)
+		if e == nil && result != nil {
+			resp.Result, e = json.Marshal(result)
+		}
+		if rpcErr, ok := e.(*RpcError); ok {
+			resp.Error = rpcErr
+		} else if e != nil {
+			resp.Error = &RpcError{Code: RpcErrInternal, Message: e.Error()}
+		}
+	}
+
+	msg, e := json.Marshal(&resp)
+	if e == nil {
+		e = s.d.client.Publish(req.ReplyTo, msg).Err()
+	}
+	if e != nil {
+		glog.Warning("RpcServer: ", s.channel, ": ", req.ReplyTo,
+			": response: ", e)
+	}
+}
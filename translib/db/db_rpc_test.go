@@ -0,0 +1,144 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/sonic-mgmt-common/translib/tlerr"
+)
+
+type rpcTestAdd struct {
+	A int `json:"a"`
+	B int `json:"b"`
+}
+
+func startRpcTestServer(t *testing.T) {
+	s, e := NewRpcServer(Options{DBNo: LogLevelDB}, "RPC_TEST_REQ")
+	if e != nil {
+		t.Fatalf("NewRpcServer() fails e = %v", e)
+	}
+	s.Handle("add", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p rpcTestAdd
+		if e := json.Unmarshal(params, &p); e != nil {
+			return nil, &RpcError{Code: RpcErrInvalidParams, Message: e.Error()}
+		}
+		return p.A + p.B, nil
+	})
+	s.Handle("fail", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		return nil, errors.New("failed")
+	})
+	s.Handle("slow", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Serve(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		s.Close()
+	})
+}
+
+func TestRpcCall(t *testing.T) {
+	useMemoryBackend(t)
+	startRpcTestServer(t)
+
+	c, e := NewRpcClient(Options{DBNo: LogLevelDB}, "RPC_TEST_RESP")
+	if e != nil {
+		t.Fatalf("NewRpcClient() fails e = %v", e)
+	}
+	defer c.Close()
+
+	// Concurrent callers share the response channel
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var sum int
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if e := c.Call(ctx, "RPC_TEST_REQ", "add", rpcTestAdd{A: i, B: 100},
+				&sum); e != nil {
+				errs <- e
+			} else if sum != i+100 {
+				errs <- errors.New("wrong sum")
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for e := range errs {
+		t.Fatalf("Call(add) fails e = %v", e)
+	}
+}
+
+func TestRpcCallErrors(t *testing.T) {
+	useMemoryBackend(t)
+	startRpcTestServer(t)
+
+	c, e := NewRpcClient(Options{DBNo: LogLevelDB}, "RPC_TEST_RESP")
+	if e != nil {
+		t.Fatalf("NewRpcClient() fails e = %v", e)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for method, code := range map[string]int{
+		"nosuch": RpcErrMethodNotFound,
+		"fail":   RpcErrInternal,
+	} {
+		e = c.Call(ctx, "RPC_TEST_REQ", method, nil, nil)
+		if rpcErr, ok := e.(*RpcError); !ok || rpcErr.Code != code {
+			t.Errorf("Call(%s) = %v; expected code %d", method, e, code)
+		}
+	}
+
+	e = c.Call(ctx, "RPC_TEST_REQ", "add", "bad", nil)
+	if rpcErr, ok := e.(*RpcError); !ok || rpcErr.Code != RpcErrInvalidParams {
+		t.Errorf("Call(add) = %v; expected code %d", e, RpcErrInvalidParams)
+	}
+
+	e = c.Call(ctx, "RPC_TEST_NOSUCH", "add", nil, nil)
+	if rpcErr, ok := e.(*RpcError); !ok || rpcErr.Code != RpcErrNoListener {
+		t.Errorf("Call() = %v; expected code %d", e, RpcErrNoListener)
+	}
+
+	sctx, scancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer scancel()
+	if e = c.Call(sctx, "RPC_TEST_REQ", "slow", nil, nil); e != (tlerr.TranslibTimeoutError{}) {
+		t.Errorf("Call(slow) = %v; expected timeout", e)
+	}
+}
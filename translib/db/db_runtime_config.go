@@ -0,0 +1,408 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/Azure/sonic-mgmt-common/internal/runtimeconfig"
+	"github.com/golang/glog"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+//  Exported Types                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+// RuntimeConfigOptions selects the source of the runtime config, i.e. the
+// TRANSLIB_DB|default fields.
+type RuntimeConfigOptions struct {
+	// File is a config_db.json style file, with the TRANSLIB_DB "default"
+	// entry. The CONFIG_DB TRANSLIB_DB|default entry is watched if empty.
+	File string
+	// PollInterval is the interval at which the File is checked for
+	// modifications, and the CONFIG_DB is resubscribed after losing the
+	// subscription. Default 5s.
+	PollInterval time.Duration
+}
+
+// RuntimeConfigComponent is a component reconfigured at runtime by the
+// TRANSLIB_DB|default fields. T is the config type of the component.
+type RuntimeConfigComponent[T any] struct {
+	Name string
+	// Parse returns the config from the fields. The fields are empty if the
+	// TRANSLIB_DB|default entry does not exist.
+	Parse func(fields map[string]string) T
+	// OnChange is called when the parsed config changes. The calls are
+	// serialized across all the components.
+	OnChange func(old, new T)
+	// Current returns the effective config, for GetRuntimeConfig(). The last
+	// parsed config is reported, if nil.
+	Current func() T
+}
+
+// RuntimeConfigInfo is the currently effective runtime config
+type RuntimeConfigInfo struct {
+	Source     string                 // "CONFIG_DB", or the file path
+	Watching   bool                   // Source is watched for changes
+	Fields     map[string]string      // Last loaded TRANSLIB_DB|default fields
+	Loaded     time.Time              // Time of the last load
+	Components map[string]interface{} // Effective config, by component Name
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Exported Functions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+// RegisterRuntimeConfig registers the component for the runtime config
+// changes. Typically called from the init() of the component's package.
+// The component should load its initial config itself (Eg: lazily, through
+// Parse() of the fields), since it is not notified of the initial config.
+// The components which can not depend on this package register a
+// runtimeconfig.Hook instead.
+func RegisterRuntimeConfig[T any](c RuntimeConfigComponent[T]) error {
+	if len(c.Name) == 0 || c.Parse == nil || c.OnChange == nil {
+		return fmt.Errorf("Invalid runtime config component %q", c.Name)
+	}
+
+	mutexRuntimeConfig.Lock()
+	defer mutexRuntimeConfig.Unlock()
+
+	h := &runtimeConfigEntry[T]{c: c}
+	if runtimeConfigFields != nil {
+		h.last, h.valid = c.Parse(runtimeConfigFields), true
+	}
+	return addRuntimeConfigHandler(h)
+}
+
+// StartRuntimeConfig starts watching the runtime config source for changes,
+// which are dispatched to the registered components. The components are
+// notified of the initial config only for a File source; since they load
+// their initial config from the CONFIG_DB themselves.
+func StartRuntimeConfig(opts *RuntimeConfigOptions) error {
+	if opts == nil {
+		opts = &RuntimeConfigOptions{}
+	}
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = runtimeConfigPollInterval
+	}
+
+	mutexRuntimeConfig.Lock()
+	if runtimeConfigStop != nil {
+		mutexRuntimeConfig.Unlock()
+		return errors.New("Runtime config is already started")
+	}
+	stop := make(chan struct{})
+	runtimeConfigStop = stop
+	runtimeConfigFile = opts.File
+	mutexRuntimeConfig.Unlock()
+
+	glog.Infof("StartRuntimeConfig: source: %s", runtimeConfigSource(opts.File))
+
+	var modTime time.Time
+	if len(opts.File) != 0 {
+		if fi, err := os.Stat(opts.File); err == nil {
+			modTime = fi.ModTime()
+		}
+	}
+	loadRuntimeConfig(len(opts.File) != 0)
+
+	go watchRuntimeConfig(opts.File, modTime, interval, stop)
+	return nil
+}
+
+// StopRuntimeConfig stops watching the runtime config source. The CONFIG_DB
+// is the source thereafter.
+func StopRuntimeConfig() {
+	mutexRuntimeConfig.Lock()
+	if runtimeConfigStop != nil {
+		close(runtimeConfigStop)
+		runtimeConfigStop = nil
+	}
+	runtimeConfigFile = ""
+	mutexRuntimeConfig.Unlock()
+}
+
+// ReloadRuntimeConfig reads the runtime config source, and notifies the
+// components whose config has changed.
+func ReloadRuntimeConfig() error {
+	return loadRuntimeConfig(true)
+}
+
+// GetRuntimeConfig returns the currently effective runtime config
+func GetRuntimeConfig() RuntimeConfigInfo {
+	registerRuntimeConfigHooks()
+
+	mutexRuntimeConfigDispatch.Lock()
+	defer mutexRuntimeConfigDispatch.Unlock()
+
+	mutexRuntimeConfig.Lock()
+	info := RuntimeConfigInfo{
+		Source:     runtimeConfigSource(runtimeConfigFile),
+		Watching:   runtimeConfigStop != nil,
+		Fields:     make(map[string]string, len(runtimeConfigFields)),
+		Loaded:     runtimeConfigLoaded,
+		Components: make(map[string]interface{}, len(runtimeConfigHandlers)),
+	}
+	for k, v := range runtimeConfigFields {
+		info.Fields[k] = v
+	}
+	handlers := append([]runtimeConfigHandler(nil), runtimeConfigHandlers...)
+	mutexRuntimeConfig.Unlock()
+
+	for _, h := range handlers {
+		info.Components[h.name()] = h.current()
+	}
+	return info
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Internal Types                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+// runtimeConfigHandler is the type independent RuntimeConfigComponent
+type runtimeConfigHandler interface {
+	name() string
+	update(fields map[string]string, notify bool)
+	current() interface{}
+}
+
+type runtimeConfigEntry[T any] struct {
+	c           RuntimeConfigComponent[T]
+	last        T    // Last parsed config
+	valid       bool // last is valid
+	notifyFirst bool // Notify the first config, even if not notify
+}
+
+func (h *runtimeConfigEntry[T]) name() string {
+	return h.c.Name
+}
+
+func (h *runtimeConfigEntry[T]) update(fields map[string]string, notify bool) {
+	config := h.c.Parse(fields)
+	old, valid := h.last, h.valid
+	h.last, h.valid = config, true
+
+	if (notify || (h.notifyFirst && !valid)) && (!valid || !reflect.DeepEqual(old, config)) {
+		glog.Infof("RuntimeConfig: %s: %+v", h.c.Name, config)
+		h.c.OnChange(old, config)
+	}
+}
+
+func (h *runtimeConfigEntry[T]) current() interface{} {
+	if h.c.Current != nil {
+		return h.c.Current()
+	}
+	return h.last
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Internal Functions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+const runtimeConfigPollInterval = 5 * time.Second
+
+var runtimeConfigHandlers []runtimeConfigHandler
+var runtimeConfigHookCount int            // runtimeconfig.Hooks registered
+var runtimeConfigFields map[string]string // nil until loaded
+var runtimeConfigLoaded time.Time
+var runtimeConfigFile string        // "" == CONFIG_DB
+var runtimeConfigStop chan struct{} // nil == not watching
+var mutexRuntimeConfig sync.Mutex
+
+// mutexRuntimeConfigDispatch serializes the loads, and hence the OnChange
+// calls.
+var mutexRuntimeConfigDispatch sync.Mutex
+
+func runtimeConfigSource(file string) string {
+	if len(file) == 0 {
+		return "CONFIG_DB"
+	}
+	return file
+}
+
+// addRuntimeConfigHandler adds the handler, unless its name is already
+// registered. Should be called with the mutexRuntimeConfig held.
+func addRuntimeConfigHandler(h runtimeConfigHandler) error {
+	for _, x := range runtimeConfigHandlers {
+		if x.name() == h.name() {
+			return fmt.Errorf("Runtime config component %q is already registered", h.name())
+		}
+	}
+	runtimeConfigHandlers = append(runtimeConfigHandlers, h)
+	return nil
+}
+
+// registerRuntimeConfigHooks registers the runtimeconfig.Hooks, registered
+// since the last call, as components. The hooks are notified of the first
+// config loaded thereafter; since they can not load it themselves.
+func registerRuntimeConfigHooks() {
+	hooks := runtimeconfig.Hooks()
+
+	mutexRuntimeConfig.Lock()
+	defer mutexRuntimeConfig.Unlock()
+
+	for _, hk := range hooks[runtimeConfigHookCount:] {
+		h := &runtimeConfigEntry[interface{}]{notifyFirst: true,
+			c: RuntimeConfigComponent[interface{}]{
+				Name:     hk.Name,
+				Parse:    hk.Parse,
+				OnChange: hk.OnChange,
+				Current:  hk.Current,
+			}}
+		if err := addRuntimeConfigHandler(h); err != nil {
+			glog.Errorf("RuntimeConfig: %v", err)
+		}
+	}
+	runtimeConfigHookCount = len(hooks)
+}
+
+// readRuntimeConfig returns the TRANSLIB_DB|default fields from the runtime
+// config source.
+func readRuntimeConfig() (map[string]string, error) {
+	mutexRuntimeConfig.Lock()
+	file := runtimeConfigFile
+	mutexRuntimeConfig.Unlock()
+
+	if len(file) == 0 {
+		return readRedis("TRANSLIB_DB|default")
+	}
+
+	data, err := readImportFile(file)
+	if err != nil {
+		return nil, err
+	}
+	entry := data["TRANSLIB_DB"]["default"]
+	fields := make(map[string]string, len(entry.Field))
+	for k, v := range entry.Field {
+		if k != "NULL" {
+			fields[k] = v
+		}
+	}
+	return fields, nil
+}
+
+// loadRuntimeConfig reads the runtime config source, and updates the config
+// of the components; notifying the changed ones, if notify.
+func loadRuntimeConfig(notify bool) error {
+	fields, err := readRuntimeConfig()
+	if err != nil {
+		glog.Errorf("RuntimeConfig: %v", err)
+		return err
+	}
+
+	registerRuntimeConfigHooks()
+
+	mutexRuntimeConfigDispatch.Lock()
+	defer mutexRuntimeConfigDispatch.Unlock()
+
+	mutexRuntimeConfig.Lock()
+	runtimeConfigFields = fields
+	runtimeConfigLoaded = time.Now()
+	handlers := append([]runtimeConfigHandler(nil), runtimeConfigHandlers...)
+	mutexRuntimeConfig.Unlock()
+
+	for _, h := range handlers {
+		h.update(fields, notify)
+	}
+	return nil
+}
+
+// watchRuntimeConfig reloads the runtime config when the file is modified,
+// or on the CONFIG_DB TRANSLIB_DB|default keyspace notifications; until
+// stopped.
+func watchRuntimeConfig(file string, modTime time.Time, interval time.Duration,
+	stop chan struct{}) {
+
+	var sdb *DB
+	lost := make(chan struct{}, 1)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for first := true; ; first = false {
+		if len(file) == 0 && sdb == nil {
+			if sdb = subscribeRuntimeConfig(lost); sdb != nil && !first {
+				// Changes may have been missed while unsubscribed
+				ReloadRuntimeConfig()
+			}
+		}
+
+		select {
+		case <-stop:
+			if sdb != nil {
+				sdb.UnsubscribeDB()
+			}
+			return
+		case <-lost:
+			sdb.UnsubscribeDB()
+			sdb = nil
+		case <-ticker.C:
+			if len(file) == 0 {
+				continue
+			}
+			var mt time.Time
+			if fi, err := os.Stat(file); err == nil {
+				mt = fi.ModTime()
+			}
+			if !mt.Equal(modTime) {
+				modTime = mt
+				ReloadRuntimeConfig()
+			}
+		}
+	}
+}
+
+// subscribeRuntimeConfig subscribes to the CONFIG_DB TRANSLIB_DB|default
+// notifications. Returns nil on failure. The lost channel is signalled on
+// losing the subscription; the subscription should then be unsubscribed.
+func subscribeRuntimeConfig(lost chan struct{}) *DB {
+	sdb, err := SubscribeDB(Options{
+		DBNo:               ConfigDB,
+		TableNameSeparator: "|",
+		KeySeparator:       "|",
+	}, []*SKey{{Ts: &TableSpec{Name: "TRANSLIB_DB"}, Key: &Key{Comp: []string{"default"}}}},
+		func(sdb *DB, skey *SKey, key *Key, event SEvent) error {
+			switch event {
+			case SEventClose:
+				return nil
+			case SEventErr:
+				glog.Warningf("RuntimeConfig: subscription lost")
+				select {
+				case lost <- struct{}{}:
+				default:
+				}
+				return nil
+			}
+			// Includes SEventResync, i.e. missed notifications
+			ReloadRuntimeConfig()
+			return nil
+		})
+
+	if err != nil {
+		glog.Warningf("RuntimeConfig: SubscribeDB: %v", err)
+		return nil
+	}
+	return sdb
+}
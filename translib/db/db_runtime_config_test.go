@@ -0,0 +1,211 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Azure/sonic-mgmt-common/internal/runtimeconfig"
+)
+
+// useTestRuntimeConfig replaces the registered runtime config components
+// with a test component; for the duration of the test. Returns the channel
+// of its OnChange calls.
+func useTestRuntimeConfig(t *testing.T) chan [2]string {
+	mutexRuntimeConfig.Lock()
+	prev := runtimeConfigHandlers
+	runtimeConfigHandlers = nil
+	runtimeConfigFields = nil
+	runtimeConfigHookCount = len(runtimeconfig.Hooks())
+	mutexRuntimeConfig.Unlock()
+	t.Cleanup(func() {
+		StopRuntimeConfig()
+		mutexRuntimeConfig.Lock()
+		runtimeConfigHandlers = prev
+		runtimeConfigFields = nil
+		// The hooks of the test are not registered thereafter
+		runtimeConfigHookCount = len(runtimeconfig.Hooks())
+		mutexRuntimeConfig.Unlock()
+	})
+
+	changes := make(chan [2]string, 10)
+	c := RuntimeConfigComponent[string]{
+		Name:     "test",
+		Parse:    func(fields map[string]string) string { return fields["test_mode"] },
+		OnChange: func(old, new string) { changes <- [2]string{old, new} },
+	}
+	if err := RegisterRuntimeConfig(c); err != nil {
+		t.Fatalf("RegisterRuntimeConfig() failed; err=%v", err)
+	}
+	if err := RegisterRuntimeConfig(c); err == nil {
+		t.Fatalf("RegisterRuntimeConfig() with duplicate name did not fail")
+	}
+	return changes
+}
+
+// useTestRuntimeConfigHook registers a test runtimeconfig.Hook. Returns the
+// channel of its OnChange calls.
+func useTestRuntimeConfigHook(t *testing.T) chan [2]interface{} {
+	changes := make(chan [2]interface{}, 10)
+	err := runtimeconfig.Register(runtimeconfig.Hook{
+		Name:  fmt.Sprintf("test_hook_%d", len(runtimeconfig.Hooks())),
+		Parse: func(fields map[string]string) interface{} { return fields["test_mode"] },
+		OnChange: func(old, new interface{}) {
+			select {
+			case changes <- [2]interface{}{old, new}:
+			default:
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("runtimeconfig.Register() failed; err=%v", err)
+	}
+	return changes
+}
+
+// expectRuntimeConfigChange waits for the OnChange(old, new) call
+func expectRuntimeConfigChange[T comparable](t *testing.T, changes chan [2]T, old, new T) {
+	t.Helper()
+	select {
+	case c := <-changes:
+		if c != [2]T{old, new} {
+			t.Errorf("OnChange(%v, %v); expected (%v, %v)", c[0], c[1], old, new)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("OnChange(%v, %v) not called", old, new)
+	}
+}
+
+// expectNoRuntimeConfigChange verifies that OnChange is not called
+func expectNoRuntimeConfigChange[T any](t *testing.T, changes chan [2]T) {
+	t.Helper()
+	select {
+	case c := <-changes:
+		t.Errorf("OnChange(%v, %v) called without a change", c[0], c[1])
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRuntimeConfigFile(t *testing.T) {
+	changes := useTestRuntimeConfig(t)
+	cfgFile := filepath.Join(t.TempDir(), "translib_db.json")
+	writeCfg := func(mode string, mtime time.Time) {
+		t.Helper()
+		ioutil.WriteFile(cfgFile, []byte(`{"TRANSLIB_DB": {"default": {"test_mode": "`+mode+`"}}}`), 0600)
+		if err := os.Chtimes(cfgFile, mtime, mtime); err != nil {
+			t.Fatalf("Chtimes() failed; err=%v", err)
+		}
+	}
+
+	now := time.Now()
+	writeCfg("a", now.Add(-time.Minute))
+	if err := StartRuntimeConfig(&RuntimeConfigOptions{
+		File: cfgFile, PollInterval: 10 * time.Millisecond}); err != nil {
+		t.Fatalf("StartRuntimeConfig() failed; err=%v", err)
+	}
+	expectRuntimeConfigChange(t, changes, "", "a")
+	if err := StartRuntimeConfig(nil); err == nil {
+		t.Fatalf("StartRuntimeConfig() again did not fail")
+	}
+
+	writeCfg("b", now)
+	expectRuntimeConfigChange(t, changes, "a", "b")
+
+	info := GetRuntimeConfig()
+	if info.Source != cfgFile || !info.Watching || info.Fields["test_mode"] != "b" ||
+		info.Components["test"] != "b" {
+		t.Errorf("GetRuntimeConfig() = %+v", info)
+	}
+
+	// No change
+	if err := ReloadRuntimeConfig(); err != nil {
+		t.Fatalf("ReloadRuntimeConfig() failed; err=%v", err)
+	}
+	expectNoRuntimeConfigChange(t, changes)
+
+	StopRuntimeConfig()
+	if info = GetRuntimeConfig(); info.Watching || info.Source != "CONFIG_DB" {
+		t.Errorf("GetRuntimeConfig() after stop = %+v", info)
+	}
+}
+
+func TestRuntimeConfigDB(t *testing.T) {
+	mb := useMemoryBackend(t)
+	changes := useTestRuntimeConfig(t)
+	hookChanges := useTestRuntimeConfigHook(t)
+	client := openTestClient(t, ConfigDB)
+	client.HSet("TRANSLIB_DB|default", "test_mode", "a")
+
+	if err := StartRuntimeConfig(&RuntimeConfigOptions{PollInterval: 10 * time.Millisecond}); err != nil {
+		t.Fatalf("StartRuntimeConfig() failed; err=%v", err)
+	}
+	// Only the hooks are notified of the initial CONFIG_DB config
+	expectRuntimeConfigChange(t, hookChanges, nil, "a")
+	expectNoRuntimeConfigChange(t, changes)
+	if info := GetRuntimeConfig(); info.Source != "CONFIG_DB" || !info.Watching ||
+		info.Components["test"] != "a" {
+		t.Errorf("GetRuntimeConfig() = %+v", info)
+	}
+
+	// The subscription is made in the background; HSET till notified.
+	var c [2]string
+	if !eventually(func() bool {
+		client.HSet("TRANSLIB_DB|default", "test_mode", "b")
+		select {
+		case c = <-changes:
+			return true
+		default:
+			return false
+		}
+	}) {
+		t.Fatalf("OnChange() not called on HSET")
+	}
+	if c != [2]string{"a", "b"} {
+		t.Errorf("OnChange(%q, %q); expected (\"a\", \"b\")", c[0], c[1])
+	}
+	expectRuntimeConfigChange(t, hookChanges, "a", "b")
+
+	// The change missed, without the keyspace notifications, is loaded on
+	// the resync after the redis restart.
+	client.ConfigSet("notify-keyspace-events", "")
+	client.HSet("TRANSLIB_DB|default", "test_mode", "c")
+	expectNoRuntimeConfigChange(t, changes)
+	mb.Restart()
+	expectRuntimeConfigChange(t, changes, "b", "c")
+	expectRuntimeConfigChange(t, hookChanges, "b", "c")
+}
+
+func TestRuntimeConfigSIGUSR2(t *testing.T) {
+	useMemoryBackend(t)
+	changes := useTestRuntimeConfig(t)
+	openTestClient(t, ConfigDB).HSet("TRANSLIB_DB|default", "test_mode", "x")
+
+	// Not watched; reloaded on SIGUSR2
+	HandleSIGUSR2()
+	expectRuntimeConfigChange(t, changes, "", "x")
+	if info := GetRuntimeConfig(); info.Watching || info.Fields["test_mode"] != "x" {
+		t.Errorf("GetRuntimeConfig() = %+v", info)
+	}
+}
@@ -0,0 +1,122 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/Azure/sonic-mgmt-common/translib/tlerr"
+	"github.com/golang/glog"
+)
+
+// RetryPolicy controls the retries of RunTx() on transaction conflicts.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	// Values less than 1 are treated as 1, i.e, no retries.
+	MaxAttempts int
+	// MinBackoff is the delay before the first retry. It is doubled for
+	// every subsequent retry, up to MaxBackoff. Actual delay is randomized
+	// between half and full of this value, to avoid retries in lock-step
+	// with the other writers.
+	MinBackoff time.Duration
+	// MaxBackoff is the upper limit for the delay between the retries.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is the RetryPolicy used by RunTx() when none is specified.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	MinBackoff:  10 * time.Millisecond,
+	MaxBackoff:  500 * time.Millisecond,
+}
+
+// RunTx runs fn within a transaction and commits it. The transaction is
+// started without any watch keys; fn should add the keys and tables it reads
+// through AppendWatchTx(). If the commit fails because another writer modified
+// any of the watched keys (tlerr.TranslibTransactionFail), the transaction is
+// started again and fn is re-executed after a backoff delay, as per the policy.
+// Hence fn should (re)read all the data it needs from d, and should not have
+// side effects other than the DB operations. fn must not commit or abort the
+// transaction by itself. The transaction is aborted if fn returns an error,
+// and the error is returned as is. DefaultRetryPolicy is used if policy is nil.
+// Retries are stopped when ctx is done.
+func (d *DB) RunTx(ctx context.Context, fn func(d *DB) error, policy *RetryPolicy) error {
+	if policy == nil {
+		policy = &DefaultRetryPolicy
+	}
+
+	backoff := policy.MinBackoff
+	for attempt := 1; ; attempt++ {
+		err := d.StartTx(nil, nil)
+		if err != nil {
+			return err
+		}
+
+		if err = fn(d); err != nil {
+			d.AbortTx()
+			return err
+		}
+
+		err = d.CommitTx()
+		if !isTxConflict(err) || attempt >= policy.MaxAttempts {
+			return err
+		}
+
+		delay := backoff
+		if delay > 0 {
+			delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+		}
+		glog.Infof("RunTx: %s: Transaction conflict in attempt %d; retrying after %v",
+			d.Name(), attempt, delay)
+
+		select {
+		case <-ctx.Done():
+			glog.Warningf("RunTx: %s: Retry cancelled; %v", d.Name(), ctx.Err())
+			return err
+		case <-time.After(delay):
+		}
+
+		if backoff *= 2; backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+		d.clearTxRetryCache()
+	}
+}
+
+// isTxConflict returns true if the error indicates a CommitTx failure
+// due to the modification of watched keys by other writers.
+func isTxConflict(err error) bool {
+	return errors.As(err, &tlerr.TranslibTransactionFail{})
+}
+
+// clearTxRetryCache clears the per connection cache before retrying a
+// transaction, since the cached entries may have been modified by the
+// conflicting writer.
+func (d *DB) clearTxRetryCache() {
+	if d.dbCacheConfig.PerConnection {
+		d.cache = dbCache{
+			Tables: make(map[string]Table, InitialTablesCount),
+			Maps:   make(map[string]MAP, InitialMapsCount),
+		}
+	}
+}
@@ -0,0 +1,103 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Azure/sonic-mgmt-common/translib/tlerr"
+)
+
+func TestRunTx(t *testing.T) {
+	useMemoryBackend(t)
+	d1 := openTestDB(t, ConfigDB, false)
+	d2 := openTestDB(t, ConfigDB, true)
+	c2 := openTestClient(t, ConfigDB) // other writer
+	ts := &TableSpec{Name: "RUNTX_TEST"}
+	key := *NewKey("counter")
+	d1.SetEntry(ts, key, Value{Field: map[string]string{"n": "0"}})
+
+	policy := &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+	// increment reads and increments the counter; other writer (c2)
+	// modifies the counter during the first numConflicts attempts.
+	increment := func(numConflicts int) (int, error) {
+		attempts := 0
+		err := d1.RunTx(context.Background(), func(d *DB) error {
+			attempts++
+			if err := d.AppendWatchTx([]WatchKeys{{Ts: ts, Key: &key}}, nil); err != nil {
+				return err
+			}
+			v, err := d.GetEntry(ts, key)
+			if err != nil {
+				return err
+			}
+			n, _ := strconv.Atoi(v.Get("n"))
+			if attempts <= numConflicts {
+				c2.HSet("RUNTX_TEST|counter", "n", strconv.Itoa(n+10))
+			}
+			return d.ModEntry(ts, key, Value{Field: map[string]string{"n": strconv.Itoa(n + 1)}})
+		}, policy)
+		return attempts, err
+	}
+
+	verify := func(exp string) {
+		t.Helper()
+		if v, _ := d2.GetEntry(ts, key); v.Get("n") != exp {
+			t.Fatalf("counter = %v; expected %s", v.Field, exp)
+		}
+	}
+
+	if attempts, err := increment(0); err != nil || attempts != 1 {
+		t.Fatalf("RunTx() without conflict; attempts=%d, err=%v", attempts, err)
+	}
+	verify("1")
+
+	// Retried with the latest value after a conflict
+	if attempts, err := increment(1); err != nil || attempts != 2 {
+		t.Fatalf("RunTx() with conflict; attempts=%d, err=%v", attempts, err)
+	}
+	verify("12")
+
+	// Gives up after MaxAttempts
+	attempts, err := increment(5)
+	if !reflect.DeepEqual(err, tlerr.TranslibTransactionFail{}) || attempts != 3 {
+		t.Fatalf("RunTx() with persistent conflicts; attempts=%d, err=%v", attempts, err)
+	}
+	verify("42")
+
+	// Error from fn is returned without retries
+	fnErr := errors.New("fn error")
+	attempts = 0
+	err = d1.RunTx(context.Background(), func(d *DB) error {
+		attempts++
+		d.ModEntry(ts, key, Value{Field: map[string]string{"n": "100"}})
+		return fnErr
+	}, policy)
+	if err != fnErr || attempts != 1 {
+		t.Fatalf("RunTx() with fn error; attempts=%d, err=%v", attempts, err)
+	}
+	verify("42")
+}
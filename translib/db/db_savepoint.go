@@ -23,6 +23,8 @@ package db
 // Support for nested transactions. i.e. a Savepoint is a point to which the
 // transaction can be rolled back to without affecting any operations
 // performed before the savepoint.
+// Savepoints are per DB, and can be nested (and named). They are supported
+// on the Config Session DB, and within a Transaction on other DBs.
 //
 
 import (
@@ -33,15 +35,21 @@ import (
 	"github.com/golang/glog"
 )
 
-// SavePoint type currently assumes it is singleton (only one object is
-// instantiated). However, it could be extended to be a stack of SavePoint
-// objects.
+// SavePoint is one of the stack of savepoints of the DB (DB.savePoints).
+// The changes are recorded only in the innermost savepoint. On a release,
+// they are merged into the enclosing savepoint.
 // Note: Any change to the underlying datastructures it is trying to save,
 // can result in a change being required to savePoint as well.
 type _savePoint struct {
+	// Name of the savepoint. (Optional)
+	name string
+
 	// CAS Transaction Operations (txCmds)
 	txCmdsLen int
 
+	// Conditions of the conditional writes (txConds)
+	txCondsLen int
+
 	// CVL Edit Operations (cvlEditConfigData)
 	// When appending to cvlEditConfigData,
 	// there is always one op, unless the ReplaceOp is true, in which case
@@ -65,83 +73,142 @@ type _savePoint struct {
 	cHints map[int]map[string]interface{}
 }
 
+// txSavePoint is the savepoint of a transaction (StartTx) on the Session DB.
+// CommitTx, and AbortTx release, or roll back to it, with any savepoints
+// declared (and not released) in the transaction.
+const txSavePoint = "StartTx"
+
 type origEntry struct {
 	value  Value
 	absent bool
 }
 
-var savePoint *_savePoint
-
 func (d *DB) HasSP() bool {
-	if d == nil || !d.Opts.IsSession {
-		return false
-	}
-	return savePoint != nil // TODO keep savePoint in DB struct
+	return d != nil && len(d.savePoints) != 0
 }
 
+// DeclareSP declares an unnamed savepoint, nested in the savepoints declared
+// earlier.
 func (d *DB) DeclareSP() error {
-	glog.Infof("DeclareSP: Begin")
+	return d.DeclareNamedSP("")
+}
+
+// ReleaseSP releases the latest savepoint.
+func (d *DB) ReleaseSP() error {
+	return d.ReleaseNamedSP("")
+}
+
+// Rollback2SP rolls back to the latest savepoint, and releases it.
+func (d *DB) Rollback2SP() error {
+	return d.Rollback2NamedSP("")
+}
 
-	if (d == nil) || !d.Opts.IsSession {
+// DeclareNamedSP declares a savepoint, nested in the savepoints declared
+// earlier. The name is optional, and need not be unique. ReleaseNamedSP(name),
+// and Rollback2NamedSP(name) use the latest savepoint of the name, or the
+// latest savepoint if the name is "".
+func (d *DB) DeclareNamedSP(name string) error {
+	glog.Infof("DeclareSP: Begin: %q", name)
+
+	if d == nil {
 		glog.Error("DeclareSP: Invalid Session")
 		return tlerr.TranslibInvalidSession{}
 	}
 
-	if savePoint != nil {
-		glog.Error("DeclareSP: Only one SavePoint Supported")
-		return tlerr.TranslibDBNotSupported{}
+	if !d.Opts.IsSession && (d.txState == txStateNone) {
+		glog.Error("DeclareSP: No Transaction active")
+		return tlerr.TranslibDBNotSupported{
+			Description: "SavePoint requires a Transaction"}
 	}
 
-	savePoint = &_savePoint{txCmdsLen: len(d.txCmds), // Record CAS Tx Ops
+	savePoint := &_savePoint{name: name,
+		txCmdsLen:        len(d.txCmds),            // Record CAS Tx Ops
+		txCondsLen:       len(d.txConds),           // Record CAS Tx Conditions
 		cECDLen:          len(d.cvlEditConfigData), // Record CVL Edit Ops
 		txTsOrigEntryMap: make(map[string]map[string]origEntry),
 	}
+	d.savePoints = append(d.savePoints, savePoint)
 
-	glog.Infof("DeclareSP: End: %# v", savePoint)
+	glog.Infof("DeclareSP: End: #%d %# v", len(d.savePoints), savePoint)
 	return nil
 }
 
-func (d *DB) ReleaseSP() error {
-	glog.Infof("ReleaseSP: Begin")
+// ReleaseNamedSP releases the savepoint, and the savepoints declared after
+// it. The changes made after it are retained (in the enclosing savepoint, if
+// any).
+func (d *DB) ReleaseNamedSP(name string) error {
+	glog.Infof("ReleaseSP: Begin: %q", name)
 
-	if (d == nil) || !d.Opts.IsSession {
+	if d == nil {
 		glog.Error("ReleaseSP: Invalid Session")
 		return tlerr.TranslibInvalidSession{}
 	}
 
-	if savePoint == nil {
-		glog.Error("DeclareSP: SavePoint Absent")
+	spIndex := d.findSP(name)
+	if spIndex < 0 {
+		glog.Errorf("ReleaseSP: SavePoint %q Absent", name)
 		return tlerr.TranslibDBNotSupported{}
 	}
 
 	if glog.V(3) {
-		glog.Infof("ReleaseSP: End: Releasing %# v", savePoint)
+		glog.Infof("ReleaseSP: End: Releasing %# v", d.savePoints[spIndex:])
 	} else {
 		glog.Infof("ReleaseSP: End:")
 	}
 
-	savePoint = nil
+	if spIndex > 0 {
+		d.mergeSPs(spIndex - 1)
+	}
+	d.savePoints = d.savePoints[:spIndex]
 
 	return nil
 }
 
-func (d *DB) Rollback2SP() error {
-	if glog.V(3) {
-		glog.Infof("Rollback2SP: Begin: %# v", savePoint)
-	} else {
-		glog.Infof("Rollback2SP: Begin:")
-	}
-
-	if (d == nil) || !d.Opts.IsSession {
+// Rollback2NamedSP rolls back the changes made after the savepoint, and
+// releases it (and the savepoints declared after it).
+func (d *DB) Rollback2NamedSP(name string) error {
+	if d == nil {
 		glog.Error("Rollback2SP: Invalid Session")
 		return tlerr.TranslibInvalidSession{}
 	}
 
-	if savePoint == nil {
-		glog.Error("Rollback2SP: SavePoint Absent")
+	spIndex := d.findSP(name)
+	if spIndex < 0 {
+		glog.Errorf("Rollback2SP: SavePoint %q Absent", name)
 		return tlerr.TranslibDBNotSupported{}
 	}
 
+	// The savepoint needs the original entries of all the changes after it.
+	d.mergeSPs(spIndex)
+	savePoint := d.savePoints[spIndex]
+
+	if glog.V(3) {
+		glog.Infof("Rollback2SP: Begin: %q %# v", name, savePoint)
+	} else {
+		glog.Infof("Rollback2SP: Begin: %q", name)
+	}
+
+	// The CAS Tx cache, as it was at the savepoint.
+	txTsEntryMap := make(map[string]map[string]Value, len(d.txTsEntryMap))
+	for tn, tb := range d.txTsEntryMap {
+		txTsEntryMap[tn] = make(map[string]Value, len(tb))
+		for k, v := range tb {
+			txTsEntryMap[tn][k] = v.Copy()
+		}
+	}
+	for otn, otbl := range savePoint.txTsOrigEntryMap {
+		if _, ok := txTsEntryMap[otn]; !ok {
+			txTsEntryMap[otn] = make(map[string]Value)
+		}
+		for oRedisKey, oEntry := range otbl {
+			if oEntry.absent {
+				delete(txTsEntryMap[otn], oRedisKey)
+			} else {
+				txTsEntryMap[otn][oRedisKey] = oEntry.value.Copy()
+			}
+		}
+	}
+
 	// Collect the CandidateConfigNotifs to be sent.
 	notifOps := make([]_txCmd, 0, len(savePoint.txTsOrigEntryMap))
 	for otn, otbl := range savePoint.txTsOrigEntryMap {
@@ -188,6 +255,8 @@ func (d *DB) Rollback2SP() error {
 
 	// Rollback CAS Tx Operations
 	d.txCmds = d.txCmds[0:savePoint.txCmdsLen]
+	d.stats.AllTables.TxCmdsLen = uint(len(d.txCmds))
+	d.txConds = d.txConds[0:savePoint.txCondsLen]
 
 	// The redis CAS Tx cache needs to be rebuilt from scratch, because
 	// while reopening (and recreating) the CVL Session, there might be
@@ -205,7 +274,7 @@ func (d *DB) Rollback2SP() error {
 	}
 	for tn, tb := range d.txTsEntryHGetAll {
 		if _, ok := d.txTsEntryMap[tn]; !ok {
-			d.txTsEntryHGetAll[tn] = make(map[string]Value)
+			d.txTsEntryMap[tn] = make(map[string]Value)
 		}
 		for k := range tb {
 			d.txTsEntryMap[tn][k] = tb[k].Copy()
@@ -328,11 +397,17 @@ func (d *DB) Rollback2SP() error {
 		d.err = err
 	}
 
+	// The CVL playback rebuilt the CAS Tx cache from the HGetAll values, in
+	// lock-step with the CVL edit ops. Set it to what it was at the
+	// savepoint, since it also has the changes without the CVL edit ops
+	// (Eg: CVL Disabled), or made before the savepoint was declared.
+	d.txTsEntryMap = txTsEntryMap
+
 	// Send the Session Notifications for Subscribers to ConfigDB.
-	for _, txCmd := range notifOps {
-		// This should be a Config Session, because Rollback2SP
-		// is only invoked if d.Opts.IsSession is true.
-		d.sendSessionNotification(txCmd.ts, txCmd.key, txCmd.op, txOpNone)
+	if d.Opts.IsSession {
+		for _, txCmd := range notifOps {
+			d.sendSessionNotification(txCmd.ts, txCmd.key, txCmd.op, txOpNone)
+		}
 	}
 	notifOps = nil
 
@@ -341,15 +416,65 @@ func (d *DB) Rollback2SP() error {
 		Maps: make(map[string]MAP, InitialMapsCount),
 	}
 
-	savePoint = nil
+	d.savePoints = d.savePoints[:spIndex]
 
 	glog.Infof("Rollback2SP: End:")
 	return err
 }
 
+// findSP returns the index of the latest savepoint of the name, or the
+// latest savepoint if name is "". Returns -1 if not found.
+func (d *DB) findSP(name string) int {
+	for i := len(d.savePoints) - 1; i >= 0; i-- {
+		if len(name) == 0 || d.savePoints[i].name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// mergeSPs merges the changes recorded in the savepoints declared after the
+// savepoint at spIndex, into it. The original entry recorded by the earlier
+// savepoint wins.
+func (d *DB) mergeSPs(spIndex int) {
+	savePoint := d.savePoints[spIndex]
+	for _, inner := range d.savePoints[spIndex+1:] {
+		for tn, tbl := range inner.txTsOrigEntryMap {
+			if _, ok := savePoint.txTsOrigEntryMap[tn]; !ok {
+				savePoint.txTsOrigEntryMap[tn] = make(map[string]origEntry)
+			}
+			for redisKey, oEntry := range tbl {
+				if _, ok := savePoint.txTsOrigEntryMap[tn][redisKey]; !ok {
+					savePoint.txTsOrigEntryMap[tn][redisKey] = oEntry
+				}
+			}
+		}
+		for ix, hints := range inner.cHints {
+			if savePoint.cHints == nil {
+				savePoint.cHints = make(map[int]map[string]interface{})
+			}
+			if savePoint.cHints[ix] == nil {
+				savePoint.cHints[ix] = make(map[string]interface{})
+			}
+			for hKey, hValue := range hints {
+				savePoint.cHints[ix][hKey] = hValue
+			}
+		}
+	}
+}
+
+// innerSP returns the innermost savepoint, or nil if none.
+func (d *DB) innerSP() *_savePoint {
+	if (d == nil) || (len(d.savePoints) == 0) {
+		return nil
+	}
+	return d.savePoints[len(d.savePoints)-1]
+}
+
 // doTxSPsave should be called before every change to the CAS Tx Cache.
 func (d *DB) doTxSPsave(ts *TableSpec, key Key) {
-	if (d == nil) || (savePoint == nil) || !d.Opts.IsSession {
+	savePoint := d.innerSP()
+	if savePoint == nil {
 		return
 	}
 
@@ -378,7 +503,7 @@ func (d *DB) doTxSPsave(ts *TableSpec, key Key) {
 // doTxSPsaveHGetAll is a sister func of doTxSPsave, and saves HGetAll() made
 // just prior to the time of change to CAS Tx Cache for the first time.
 func (d *DB) doTxSPsaveHGetAll(ts *TableSpec, key Key, value Value) {
-	if (d == nil) || (savePoint == nil) || !d.Opts.IsSession {
+	if d.innerSP() == nil {
 		return
 	}
 
@@ -398,7 +523,8 @@ func (d *DB) doTxSPsaveHGetAll(ts *TableSpec, key Key, value Value) {
 
 // doCHintSave should be called on successfully Storing a Hint to CVL
 func (d *DB) doCHintSave(key string, value interface{}) {
-	if (d == nil) || (savePoint == nil) || !d.Opts.IsSession {
+	savePoint := d.innerSP()
+	if savePoint == nil {
 		return
 	}
 
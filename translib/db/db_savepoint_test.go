@@ -279,3 +279,134 @@ var basicTests = []spTC{
 		},
 	},
 }
+
+// TestSPNested tests nested, named savepoints on a non-session DB
+func TestSPNested(t *testing.T) {
+	useMemoryBackend(t)
+	d := openTestDB(t, ConfigDB, false)
+	ts := &TableSpec{Name: "SP_NESTED"}
+	k1, k2 := *NewKey("k1"), *NewKey("k2")
+	d.SetEntry(ts, k1, Value{Field: map[string]string{"a": "1"}})
+
+	verify := func(key Key, exp map[string]string) {
+		t.Helper()
+		v, err := d.GetEntry(ts, key)
+		if exp == nil {
+			if err == nil {
+				t.Fatalf("GetEntry(%v) = %v; expected not found", key, v)
+			}
+		} else if err != nil || !reflect.DeepEqual(v.Field, exp) {
+			t.Fatalf("GetEntry(%v) = %v, %v; expected %v", key, v, err, exp)
+		}
+	}
+
+	if e := d.DeclareNamedSP("step1"); e == nil {
+		t.Fatalf("DeclareNamedSP() without transaction did not fail")
+	}
+
+	if e := d.StartTx(nil, nil); e != nil {
+		t.Fatalf("StartTx() fails e: %v", e)
+	}
+	d.ModEntry(ts, k1, Value{Field: map[string]string{"b": "2"}})
+	if e := d.DeclareNamedSP("step1"); e != nil {
+		t.Fatalf("DeclareNamedSP(step1) fails e: %v", e)
+	}
+	d.ModEntry(ts, k1, Value{Field: map[string]string{"c": "3"}})
+	d.SetEntry(ts, k2, Value{Field: map[string]string{"x": "1"}})
+	if e := d.DeclareNamedSP("step2"); e != nil {
+		t.Fatalf("DeclareNamedSP(step2) fails e: %v", e)
+	}
+	d.DeleteEntry(ts, k1)
+	verify(k1, nil)
+
+	// Rollback of the innermost step
+	if e := d.Rollback2NamedSP("step2"); e != nil {
+		t.Fatalf("Rollback2NamedSP(step2) fails e: %v", e)
+	}
+	verify(k1, map[string]string{"a": "1", "b": "2", "c": "3"})
+	verify(k2, map[string]string{"x": "1"})
+	if !d.HasSP() {
+		t.Fatalf("HasSP() = false; expected step1")
+	}
+	if e := d.Rollback2NamedSP("step2"); e == nil {
+		t.Fatalf("Rollback2NamedSP(step2) again did not fail")
+	}
+
+	// Released step changes are rolled back with the enclosing step
+	if e := d.DeclareNamedSP("step3"); e != nil {
+		t.Fatalf("DeclareNamedSP(step3) fails e: %v", e)
+	}
+	d.ModEntry(ts, k2, Value{Field: map[string]string{"y": "2"}})
+	if e := d.ReleaseNamedSP("step3"); e != nil {
+		t.Fatalf("ReleaseNamedSP(step3) fails e: %v", e)
+	}
+	verify(k2, map[string]string{"x": "1", "y": "2"})
+	if e := d.Rollback2SP(); e != nil {
+		t.Fatalf("Rollback2SP() fails e: %v", e)
+	}
+	verify(k1, map[string]string{"a": "1", "b": "2"})
+	verify(k2, nil)
+	if d.HasSP() {
+		t.Fatalf("HasSP() = true after rolling back all")
+	}
+
+	if e := d.CommitTx(); e != nil {
+		t.Fatalf("CommitTx() fails e: %v", e)
+	}
+	rd := openTestDB(t, ConfigDB, true)
+	if v, e := rd.GetEntry(ts, k1); e != nil || !reflect.DeepEqual(v.Field, map[string]string{"a": "1", "b": "2"}) {
+		t.Fatalf("Committed GetEntry(k1) = %v, %v", v, e)
+	}
+	if v, e := rd.GetEntry(ts, k2); e == nil {
+		t.Fatalf("Committed GetEntry(k2) = %v", v)
+	}
+}
+
+// TestSPSessionTx tests that the Session DB CommitTx releases the savepoint
+// of its StartTx, with the savepoints declared in the transaction.
+func TestSPSessionTx(t *testing.T) {
+	ccd, e := NewDB(Options{
+		DBNo:               ConfigDB,
+		TableNameSeparator: "|",
+		KeySeparator:       "|",
+		IsSession:          true,
+	})
+	if e != nil {
+		t.Fatalf("Session NewDB() fails e: %v", e)
+	}
+	t.Cleanup(func() { ccd.DeleteDB() })
+
+	if e = ccd.DeclareSP(); e != nil {
+		t.Fatalf("DeclareSP() fails e: %v", e)
+	}
+	if e = ccd.StartTx(nil, nil); e != nil {
+		t.Fatalf("StartTx() fails e: %v", e)
+	}
+	if e = ccd.DeclareNamedSP("step1"); e != nil {
+		t.Fatalf("DeclareNamedSP(step1) fails e: %v", e)
+	}
+	if e = ccd.CommitTx(); e != nil {
+		t.Fatalf("CommitTx() fails e: %v", e)
+	}
+	if len(ccd.savePoints) != 1 || ccd.savePoints[0].name != "" {
+		t.Fatalf("Savepoints after CommitTx() = %+v; expected the outer one",
+			ccd.savePoints)
+	}
+
+	if e = ccd.StartTx(nil, nil); e != nil {
+		t.Fatalf("StartTx() fails e: %v", e)
+	}
+	if e = ccd.DeclareNamedSP("step1"); e != nil {
+		t.Fatalf("DeclareNamedSP(step1) fails e: %v", e)
+	}
+	if e = ccd.AbortTx(); e != nil {
+		t.Fatalf("AbortTx() fails e: %v", e)
+	}
+	if len(ccd.savePoints) != 1 {
+		t.Fatalf("Savepoints after AbortTx() = %+v; expected the outer one",
+			ccd.savePoints)
+	}
+	if e = ccd.Rollback2SP(); e != nil {
+		t.Fatalf("Rollback2SP() fails e: %v", e)
+	}
+}
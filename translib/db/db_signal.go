@@ -43,6 +43,9 @@ import (
 //  Exported Functions                                                        //
 ////////////////////////////////////////////////////////////////////////////////
 
+// SignalHandler reloads the runtime config on SIGUSR2. Retained for the
+// existing users; StartRuntimeConfig() watches the runtime config source
+// instead.
 func SignalHandler() {
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGUSR2)
@@ -56,7 +59,13 @@ func SignalHandler() {
 	}()
 }
 
+// HandleSIGUSR2 reloads the runtime config. If the source can not be read,
+// the DB components reload their config on next use.
 func HandleSIGUSR2() {
+	if ReloadRuntimeConfig() == nil {
+		return
+	}
+
 	if dbCacheConfig != nil {
 		dbCacheConfig.handleReconfigureSignal()
 	}
@@ -68,6 +77,10 @@ func HandleSIGUSR2() {
 	if dbRedisOptsConfig != nil {
 		dbRedisOptsConfig.handleReconfigureSignal()
 	}
+
+	if dbJournalConfig != nil {
+		dbJournalConfig.handleReconfigureSignal()
+	}
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -21,10 +21,12 @@ package db
 
 import (
 	"reflect"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/go-redis/redis/v7"
+	"github.com/golang/glog"
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -98,14 +100,26 @@ type Stats struct {
 	// Running Totals (i.e. over several DB connections) are not maintained
 	// reliably.
 	TxCmdsLen uint `json:"tx-cmds-len"`
+
+	// HistStats are being collected (true). Latency histograms per
+	// operation (category), Eg: OpGetEntry.
+
+	Histograms map[string]*Histogram `json:"histograms,omitempty"`
 }
 
 type DBStats struct {
 	Name      string           `json:"name"`
+	Namespace string           `json:"namespace,omitempty"`
 	AllTables Stats            `json:"all-tables"`
 	AllMaps   Stats            `json:"all-maps"`
 	Tables    map[string]Stats `json:"tables,omitempty"`
 	Maps      map[string]Stats `json:"maps,omitempty"`
+
+	// Global Cache (i.e. shared by all DB connections) Statistics
+
+	GlobalCache *DBGlobalCacheStats `json:"global-cache,omitempty"`
+
+	dbNo DBNum
 }
 
 type DBGlobalStats struct {
@@ -118,15 +132,28 @@ type DBGlobalStats struct {
 
 	ZeroGetHits uint `json:"zero-get-ops-db"`
 
-	// TableStats are being collected (true)
+	// TableStats are being collected (true). The DBs of the default
+	// namespace are indexed by their DBNum; those of the other namespaces
+	// follow.
 
 	Databases []DBStats `json:"dbs,omitempty"`
+
+	// SlowOpThreshold is set. The latest SlowOpLogSize slow operations.
+
+	SlowOps []SlowOp `json:"slow-ops,omitempty"`
 }
 
 type DBStatsConfig struct {
 	TimeStats  bool
 	TableStats bool
 	MapStats   bool
+
+	// HistStats and SlowOpThreshold imply TimeStats
+	HistStats       bool            // Latency histograms
+	HistBuckets     []time.Duration // Empty == 100us..3.2s, doubling
+	HistPercentiles []float64       // Empty == 50, 90, 99
+	SlowOpThreshold time.Duration   // 0 == No slow op log
+	SlowOpLogSize   int             // 0 == 100
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -183,6 +210,16 @@ func init() {
 	dbStatsConfig.handleReconfigureSignal()
 	dbStatsConfig.reconfigure()
 
+	RegisterRuntimeConfig(RuntimeConfigComponent[DBStatsConfig]{
+		Name: "db_stats",
+		Parse: func(fields map[string]string) DBStatsConfig {
+			var config DBStatsConfig
+			config.parseFields(fields)
+			return config
+		},
+		OnChange: func(_, config DBStatsConfig) { setDBStatsConfig(config) },
+		Current:  getDBStatsConfig,
+	})
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -194,11 +231,20 @@ func (stats *DBGlobalStats) getStats() (*DBGlobalStats, error) {
 	// Need to give a (deep)copy of the Stats
 	var dbGlobalStats DBGlobalStats
 
+	mutexStatsConfig.Lock()
+	percentiles := dbStatsConfig.histPercentiles()
+	mutexStatsConfig.Unlock()
+
 	mutexDBGlobalStats.Lock()
 
 	dbGlobalStats = *stats
+	dbGlobalStats.Databases = append([]DBStats(nil), stats.Databases...)
 	for dbnum, db := range stats.Databases {
-		dbGlobalStats.Databases[dbnum].Name = DBNum(dbnum).String()
+		dbNo := DBNum(dbnum)
+		if dbNo >= MaxDB {
+			dbNo = db.dbNo
+		}
+		dbGlobalStats.Databases[dbnum].Name = dbNo.String()
 
 		dbGlobalStats.Databases[dbnum].Tables = make(map[string]Stats, len(db.Tables))
 		for name, table := range db.Tables {
@@ -209,8 +255,25 @@ func (stats *DBGlobalStats) getStats() (*DBGlobalStats, error) {
 		for name, mAP := range db.Maps {
 			dbGlobalStats.Databases[dbnum].Maps[name] = mAP
 		}
+
+		dbGlobalStats.Databases[dbnum].GlobalCache = dbGlobalCache.getStats(db.Namespace, dbNo)
+
+		dbGlobalStats.Databases[dbnum].AllTables.Histograms =
+			copyHistograms(db.AllTables.Histograms, percentiles)
+		dbGlobalStats.Databases[dbnum].AllMaps.Histograms =
+			copyHistograms(db.AllMaps.Histograms, percentiles)
+		for name, table := range dbGlobalStats.Databases[dbnum].Tables {
+			table.Histograms = copyHistograms(table.Histograms, percentiles)
+			dbGlobalStats.Databases[dbnum].Tables[name] = table
+		}
+		for name, mAP := range dbGlobalStats.Databases[dbnum].Maps {
+			mAP.Histograms = copyHistograms(mAP.Histograms, percentiles)
+			dbGlobalStats.Databases[dbnum].Maps[name] = mAP
+		}
 	}
 
+	dbGlobalStats.SlowOps = append([]SlowOp(nil), stats.SlowOps...)
+
 	mutexDBGlobalStats.Unlock()
 
 	return &dbGlobalStats, nil
@@ -269,14 +332,17 @@ func (stats *DBGlobalStats) clearStats() error {
 	*stats = DBGlobalStats{Databases: make([]DBStats, MaxDB)}
 	mutexDBGlobalStats.Unlock()
 
+	dbGlobalCache.clearStats()
+
 	return nil
 }
 
-func (stats *DBGlobalStats) updateStats(dbNo DBNum, isNew bool, dur time.Duration, connStats *DBStats) error {
+func (stats *DBGlobalStats) updateStats(ns string, dbNo DBNum, isNew bool, dur time.Duration, connStats *DBStats) error {
 
 	mutexDBGlobalStats.Lock()
 
 	if isNew {
+		stats.dbStats(ns, dbNo) // Lists the DB, for its global cache stats
 		stats.NewTime += dur
 		if dur > stats.NewPeak {
 			stats.NewPeak = dur
@@ -290,7 +356,7 @@ func (stats *DBGlobalStats) updateStats(dbNo DBNum, isNew bool, dur time.Duratio
 			(len(connStats.Tables) == 0) && (len(connStats.Maps) == 0) {
 			(stats.ZeroGetHits)++
 		} else {
-			stats.Databases[dbNo].updateStats(connStats)
+			stats.dbStats(ns, dbNo).updateStats(connStats)
 		}
 	}
 
@@ -299,6 +365,23 @@ func (stats *DBGlobalStats) updateStats(dbNo DBNum, isNew bool, dur time.Duratio
 	return nil
 }
 
+// dbStats returns the statistics of the DB of the namespace. Those of the
+// other namespaces are appended by their first NewDB. Called with the
+// mutexDBGlobalStats held.
+func (stats *DBGlobalStats) dbStats(ns string, dbNo DBNum) *DBStats {
+	if len(ns) == 0 {
+		return &stats.Databases[dbNo]
+	}
+	for i := int(MaxDB); i < len(stats.Databases); i++ {
+		if dbs := &stats.Databases[i]; dbs.Namespace == ns && dbs.dbNo == dbNo {
+			return dbs
+		}
+	}
+	stats.Databases = append(stats.Databases,
+		DBStats{Name: dbNo.String(), Namespace: ns, dbNo: dbNo})
+	return &stats.Databases[len(stats.Databases)-1]
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 //  DBStats functions                                                         //
 ////////////////////////////////////////////////////////////////////////////////
@@ -432,6 +515,18 @@ func (stats *Stats) updateStats(connStats *Stats) error {
 				stats.ExistsKeyPatternPeak = connStats.ExistsKeyPatternPeak
 			}
 
+			for op, h := range connStats.Histograms {
+				if stats.Histograms == nil {
+					stats.Histograms = make(map[string]*Histogram,
+						len(connStats.Histograms))
+				}
+				if sh, ok := stats.Histograms[op]; ok {
+					sh.merge(h)
+				} else {
+					stats.Histograms[op] = h.copy()
+				}
+			}
+
 		}
 
 	}
@@ -464,20 +559,25 @@ func (config *DBStatsConfig) reconfigure() error {
 	if doReconfigure {
 		var readDBStatsConfig DBStatsConfig
 		readDBStatsConfig.readFromDB()
+		setDBStatsConfig(readDBStatsConfig)
+	}
+	return nil
+}
 
-		mutexStatsConfig.Lock()
-		configChanged := !reflect.DeepEqual(*config, readDBStatsConfig)
-		mutexStatsConfig.Unlock()
-
-		if configChanged {
-			ClearDBStats()
-		}
+// setDBStatsConfig makes the config effective. The stats are cleared, if
+// the config has changed.
+func setDBStatsConfig(config DBStatsConfig) {
+	mutexStatsConfig.Lock()
+	configChanged := !reflect.DeepEqual(*dbStatsConfig, config)
+	mutexStatsConfig.Unlock()
 
-		mutexStatsConfig.Lock()
-		dbStatsConfig = &readDBStatsConfig
-		mutexStatsConfig.Unlock()
+	if configChanged {
+		ClearDBStats()
 	}
-	return nil
+
+	mutexStatsConfig.Lock()
+	dbStatsConfig = &config
+	mutexStatsConfig.Unlock()
 }
 
 func (config *DBStatsConfig) handleReconfigureSignal() error {
@@ -492,30 +592,67 @@ func (config *DBStatsConfig) handleReconfigureSignal() error {
 ////////////////////////////////////////////////////////////////////////////////
 
 func (config *DBStatsConfig) readFromDB() error {
-	fields, e := readRedis("TRANSLIB_DB|default")
+	fields, e := readRuntimeConfig()
 	if e != nil {
 		config.TimeStats = defaultDBStatsConfig.TimeStats
 		config.TableStats = defaultDBStatsConfig.TableStats
 		config.MapStats = defaultDBStatsConfig.MapStats
 	} else {
-		for k, v := range fields {
-			switch {
-			case k == "time_stats" && v == "True":
-				config.TimeStats = true
-			case k == "time_stats" && v == "False":
-				config.TimeStats = false
-			case k == "table_stats" && v == "True":
-				config.TableStats = true
-			case k == "table_stats" && v == "False":
-				config.TableStats = false
-			case k == "map_stats" && v == "True":
-				config.MapStats = true
-			case k == "map_stats" && v == "False":
-				config.MapStats = false
+		config.parseFields(fields)
+	}
+	return e
+}
+
+// parseFields sets the config from the TRANSLIB_DB|default fields
+func (config *DBStatsConfig) parseFields(fields map[string]string) {
+	for k, v := range fields {
+		switch {
+		case k == "time_stats" && v == "True":
+			config.TimeStats = true
+		case k == "time_stats" && v == "False":
+			config.TimeStats = false
+		case k == "table_stats" && v == "True":
+			config.TableStats = true
+		case k == "table_stats" && v == "False":
+			config.TableStats = false
+		case k == "map_stats" && v == "True":
+			config.MapStats = true
+		case k == "map_stats" && v == "False":
+			config.MapStats = false
+		case k == "hist_stats" && v == "True":
+			config.HistStats = true
+		case k == "hist_stats" && v == "False":
+			config.HistStats = false
+		case k == "@hist_buckets":
+			if buckets, err := parseHistBuckets(v); err != nil {
+				glog.Errorf("DBStatsConfig: %s: %v", k, err)
+			} else {
+				config.HistBuckets = buckets
+			}
+		case k == "@hist_percentiles":
+			if percentiles, err := parseHistPercentiles(v); err != nil {
+				glog.Errorf("DBStatsConfig: %s: %v", k, err)
+			} else {
+				config.HistPercentiles = percentiles
+			}
+		case k == "slow_op_threshold":
+			if threshold, err := time.ParseDuration(v); err != nil {
+				glog.Errorf("DBStatsConfig: %s: %v", k, err)
+			} else {
+				config.SlowOpThreshold = threshold
+			}
+		case k == "slow_op_log_size":
+			if size, err := strconv.Atoi(v); err != nil {
+				glog.Errorf("DBStatsConfig: %s: %v", k, err)
+			} else {
+				config.SlowOpLogSize = size
 			}
 		}
 	}
-	return e
+
+	if config.HistStats || config.SlowOpThreshold != 0 {
+		config.TimeStats = true
+	}
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -527,22 +664,28 @@ func readRedis(key string) (map[string]string, error) {
 	ipAddr := DefaultRedisLocalTCPEP
 	dbId := int(ConfigDB)
 	dbPassword := ""
-	if dbInstName := getDBInstName(ConfigDB); dbInstName != "" {
-		if isDbInstPresent(dbInstName) {
-			ipAddr = getDbTcpAddr(dbInstName)
-			dbId = getDbId(dbInstName)
-			dbPassword = getDbPassword(dbInstName)
-		}
+	dbInstName := getDBInstName(ConfigDB)
+	dbAuth := dbInstName != "" && isDbInstPresent("", dbInstName)
+	if dbAuth {
+		ipAddr = getDbTcpAddr("", dbInstName)
+		dbId = getDbId("", dbInstName)
+		dbPassword = getDbPassword("", dbInstName)
 	}
 
-	client := redis.NewClient(&redis.Options{
+	opts := &redis.Options{
 		Network:     "tcp",
 		Addr:        ipAddr,
 		Password:    dbPassword,
 		DB:          dbId,
 		DialTimeout: 0,
 		PoolSize:    1,
-	})
+		Dialer:      backendDialer(),
+	}
+	if dbAuth {
+		setDbAuth(opts, "", dbInstName)
+	}
+
+	client := redis.NewClient(opts)
 
 	fields, e := client.HGetAll(key).Result()
 
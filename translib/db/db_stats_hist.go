@@ -0,0 +1,290 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+//  Exported Types                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+// Histogram is a latency histogram. Counts[i] is the number of operations
+// that took at most Buckets[i] (and more than Buckets[i-1]). The last count,
+// i.e. Counts[len(Buckets)], is of the ones that took longer.
+type Histogram struct {
+	Buckets []time.Duration `json:"buckets"`
+	Counts  []uint          `json:"counts"`
+
+	// Percentiles (Eg: "p99"), as per DBStatsConfig.HistPercentiles. Filled
+	// in by GetDBStats() only.
+	Percentiles map[string]time.Duration `json:"percentiles,omitempty"`
+}
+
+// SlowOp is an operation that took longer than DBStatsConfig.SlowOpThreshold
+type SlowOp struct {
+	Time     time.Time     `json:"time"`
+	DB       string        `json:"db"`
+	Op       string        `json:"op"`
+	Table    string        `json:"table"`
+	Key      string        `json:"key,omitempty"` // Key, or key pattern
+	Duration time.Duration `json:"duration"`
+	Stack    []string      `json:"stack,omitempty"` // Caller stack
+}
+
+// Operation (category) names of the Stats.Histograms, and the SlowOps
+const (
+	OpGetEntry         = "get-entry"
+	OpGetKeysPattern   = "get-keys-pattern"
+	OpGetMap           = "get-map"
+	OpGetMapAll        = "get-map-all"
+	OpGetEntries       = "get-entries"
+	OpGetTablePattern  = "get-table-pattern"
+	OpExistsKeyPattern = "exists-key-pattern"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+//  Exported Functions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+// Percentile returns the upper bound of the bucket of the p-th (0 < p <= 100)
+// percentile. It is the last bucket (i.e. the histogram's max. measurable
+// latency), when the percentile is beyond the buckets.
+func (h *Histogram) Percentile(p float64) time.Duration {
+	var total uint
+	for _, c := range h.Counts {
+		total += c
+	}
+	if total == 0 || len(h.Buckets) == 0 {
+		return 0
+	}
+
+	rank := uint(math.Ceil(float64(total) * p / 100))
+	if rank == 0 {
+		rank = 1
+	}
+
+	var count uint
+	for i, c := range h.Counts {
+		if count += c; count >= rank && i < len(h.Buckets) {
+			return h.Buckets[i]
+		}
+	}
+	return h.Buckets[len(h.Buckets)-1]
+}
+
+////////////////////////////////////////////////////////////////////////////////
+//  Internal Functions                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+// defaultHistBuckets are 100us .. ~3.3s, doubling.
+var defaultHistBuckets = func() []time.Duration {
+	buckets := make([]time.Duration, 16)
+	for i := range buckets {
+		buckets[i] = (100 * time.Microsecond) << uint(i)
+	}
+	return buckets
+}()
+
+var defaultHistPercentiles = []float64{50, 90, 99}
+
+// defaultSlowOpLogSize is the number of the (latest) SlowOps kept.
+const defaultSlowOpLogSize = 100
+
+func newHistogram(buckets []time.Duration) *Histogram {
+	return &Histogram{
+		Buckets: buckets,
+		Counts:  make([]uint, len(buckets)+1),
+	}
+}
+
+func (h *Histogram) observe(dur time.Duration) {
+	i := sort.Search(len(h.Buckets), func(i int) bool {
+		return dur <= h.Buckets[i]
+	})
+	h.Counts[i]++
+}
+
+// merge adds the counts of the other histogram. A histogram of different
+// buckets (i.e. before a reconfigure) is ignored.
+func (h *Histogram) merge(o *Histogram) {
+	if len(h.Counts) != len(o.Counts) {
+		return
+	}
+	for i := range h.Buckets {
+		if h.Buckets[i] != o.Buckets[i] {
+			return
+		}
+	}
+	for i, c := range o.Counts {
+		h.Counts[i] += c
+	}
+}
+
+func (h *Histogram) copy() *Histogram {
+	hc := &Histogram{Buckets: h.Buckets, Counts: make([]uint, len(h.Counts))}
+	copy(hc.Counts, h.Counts)
+	return hc
+}
+
+// observeOp records the op latency in the histogram of the stats, and the op
+// in the slow op log, as configured. key is the key, or the key pattern of
+// the op, if any.
+func (d *DB) observeOp(stats *Stats, op string, ts *TableSpec, key string,
+	dur time.Duration) {
+
+	if d.dbStatsConfig.HistStats {
+		if stats.Histograms == nil {
+			stats.Histograms = make(map[string]*Histogram)
+		}
+		h, ok := stats.Histograms[op]
+		if !ok {
+			h = newHistogram(d.dbStatsConfig.histBuckets())
+			stats.Histograms[op] = h
+		}
+		h.observe(dur)
+	}
+
+	if threshold := d.dbStatsConfig.SlowOpThreshold; threshold != 0 &&
+		dur >= threshold {
+
+		slowOp := SlowOp{
+			Time:     time.Now(),
+			DB:       d.Name(),
+			Op:       op,
+			Table:    ts.Name,
+			Key:      key,
+			Duration: dur,
+			Stack:    callerStack(3),
+		}
+		glog.Warningf("DB Slow Op: %s: %s %s %q: %v", slowOp.DB, op, ts.Name,
+			key, dur)
+		dbGlobalStats.addSlowOp(slowOp, d.dbStatsConfig.slowOpLogSize())
+	}
+}
+
+// callerStack gives the "function file:line" of the callers, skipping the
+// skip frames.
+func callerStack(skip int) []string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(skip+1, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	stack := make([]string, 0, n)
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, fmt.Sprintf("%s %s:%d", frame.Function,
+			frame.File, frame.Line))
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
+func (stats *DBGlobalStats) addSlowOp(slowOp SlowOp, size int) {
+	mutexDBGlobalStats.Lock()
+	if len(stats.SlowOps) >= size {
+		stats.SlowOps = append(stats.SlowOps[:0],
+			stats.SlowOps[len(stats.SlowOps)-size+1:]...)
+	}
+	stats.SlowOps = append(stats.SlowOps, slowOp)
+	mutexDBGlobalStats.Unlock()
+}
+
+func (config *DBStatsConfig) histBuckets() []time.Duration {
+	if len(config.HistBuckets) == 0 {
+		return defaultHistBuckets
+	}
+	return config.HistBuckets
+}
+
+func (config *DBStatsConfig) histPercentiles() []float64 {
+	if len(config.HistPercentiles) == 0 {
+		return defaultHistPercentiles
+	}
+	return config.HistPercentiles
+}
+
+func (config *DBStatsConfig) slowOpLogSize() int {
+	if config.SlowOpLogSize <= 0 {
+		return defaultSlowOpLogSize
+	}
+	return config.SlowOpLogSize
+}
+
+// copyHistograms gives a deep copy of the histograms, with the percentiles.
+func copyHistograms(hists map[string]*Histogram, percentiles []float64) map[string]*Histogram {
+	if len(hists) == 0 {
+		return nil
+	}
+	hc := make(map[string]*Histogram, len(hists))
+	for op, h := range hists {
+		hc[op] = h.copy()
+		hc[op].Percentiles = make(map[string]time.Duration, len(percentiles))
+		for _, p := range percentiles {
+			hc[op].Percentiles["p"+strconv.FormatFloat(p, 'f', -1, 64)] =
+				h.Percentile(p)
+		}
+	}
+	return hc
+}
+
+// parseHistBuckets parses the comma separated durations (Eg: "1ms,10ms"),
+// which must be ascending.
+func parseHistBuckets(v string) ([]time.Duration, error) {
+	var buckets []time.Duration
+	for _, s := range strings.Split(v, ",") {
+		b, e := time.ParseDuration(strings.TrimSpace(s))
+		if e != nil {
+			return nil, e
+		}
+		if len(buckets) != 0 && b <= buckets[len(buckets)-1] {
+			return nil, fmt.Errorf("hist buckets not ascending: %s", v)
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, nil
+}
+
+// parseHistPercentiles parses the comma separated percentiles (Eg: "50,99.9")
+func parseHistPercentiles(v string) ([]float64, error) {
+	var percentiles []float64
+	for _, s := range strings.Split(v, ",") {
+		p, e := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if e != nil {
+			return nil, e
+		}
+		if p <= 0 || p > 100 {
+			return nil, fmt.Errorf("invalid percentile: %s", s)
+		}
+		percentiles = append(percentiles, p)
+	}
+	return percentiles, nil
+}
@@ -0,0 +1,117 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistogram(t *testing.T) {
+	h := newHistogram([]time.Duration{time.Millisecond, 10 * time.Millisecond,
+		100 * time.Millisecond})
+	for _, dur := range []time.Duration{time.Microsecond, time.Millisecond,
+		2 * time.Millisecond, 5 * time.Millisecond, 50 * time.Millisecond,
+		time.Second} {
+		h.observe(dur)
+	}
+	if exp := []uint{2, 2, 1, 1}; len(h.Counts) != 4 || h.Counts[0] != exp[0] ||
+		h.Counts[1] != exp[1] || h.Counts[2] != exp[2] || h.Counts[3] != exp[3] {
+		t.Fatalf("Counts = %v; expected %v", h.Counts, exp)
+	}
+
+	for p, exp := range map[float64]time.Duration{
+		30:  time.Millisecond,
+		50:  10 * time.Millisecond,
+		80:  100 * time.Millisecond,
+		100: 100 * time.Millisecond,
+	} {
+		if v := h.Percentile(p); v != exp {
+			t.Errorf("Percentile(%v) = %v; expected %v", p, v, exp)
+		}
+	}
+
+	h2 := h.copy()
+	h2.merge(h)
+	if h2.Counts[1] != 4 || h.Counts[1] != 2 {
+		t.Errorf("merge() Counts = %v, %v", h2.Counts, h.Counts)
+	}
+	h2.merge(newHistogram(defaultHistBuckets))
+	if h2.Counts[1] != 4 {
+		t.Errorf("merge() of different buckets Counts = %v", h2.Counts)
+	}
+}
+
+func TestHistStatsSlowOps(t *testing.T) {
+	useMemoryBackend(t)
+	prev := getDBStatsConfig()
+	t.Cleanup(func() {
+		mutexStatsConfig.Lock()
+		dbStatsConfig = &prev
+		mutexStatsConfig.Unlock()
+		ClearDBStats()
+	})
+	openTestClient(t, ConfigDB).HMSet("TRANSLIB_DB|default",
+		map[string]interface{}{
+			"hist_stats":        "True",
+			"@hist_buckets":     "1ns,1h",
+			"@hist_percentiles": "50,99.9",
+			"slow_op_threshold": "1ns",
+			"slow_op_log_size":  "2",
+		})
+	dbStatsConfig.handleReconfigureSignal()
+	ReconfigureStats()
+	ClearDBStats()
+
+	d := openTestDB(t, ConfigDB, true)
+	if !d.dbStatsConfig.TimeStats {
+		t.Fatalf("TimeStats not implied by HistStats")
+	}
+	ts := &TableSpec{Name: "HIST_TEST"}
+	for i := 0; i < 3; i++ {
+		d.GetEntry(ts, *NewKey("k1"))
+	}
+	d.GetKeys(ts)
+	d.DeleteDB()
+
+	stats, _ := GetDBStats()
+	h := stats.Databases[ConfigDB].AllTables.Histograms[OpGetEntry]
+	if h == nil || len(h.Counts) != 3 || h.Counts[1] != 3 {
+		t.Fatalf("Histograms[get-entry] = %+v", h)
+	}
+	if p := h.Percentiles["p99.9"]; p != time.Hour {
+		t.Errorf("Percentiles = %v", h.Percentiles)
+	}
+	if h := stats.Databases[ConfigDB].AllTables.Histograms[OpGetKeysPattern]; h == nil {
+		t.Errorf("Histograms[get-keys-pattern] missing")
+	}
+
+	if len(stats.SlowOps) != 2 {
+		t.Fatalf("SlowOps = %+v; expected 2", stats.SlowOps)
+	}
+	slowOp := stats.SlowOps[1]
+	if slowOp.Op != OpGetKeysPattern || slowOp.Table != "HIST_TEST" ||
+		slowOp.Key != "HIST_TEST|*" || len(slowOp.Stack) == 0 {
+		t.Errorf("SlowOps[1] = %+v", slowOp)
+	}
+	if slowOp = stats.SlowOps[0]; slowOp.Op != OpGetEntry || slowOp.Key != "HIST_TEST|k1" {
+		t.Errorf("SlowOps[0] = %+v", slowOp)
+	}
+}
@@ -480,3 +480,51 @@ func TestSubscribeNoCCNotif2HFuncSA(t *testing.T) {
 		t.Errorf("Unknown Notification Received")
 	}
 }
+
+func TestSubscribeDBResync(t *testing.T) {
+	mb := useMemoryBackend(t)
+	ts := &TableSpec{Name: "SUB_RESYNC"}
+	key := *NewKey("*")
+
+	events := make(chan SEvent, 10)
+	s, err := SubscribeDB(Options{
+		DBNo:               ConfigDB,
+		TableNameSeparator: "|",
+		KeySeparator:       "|",
+		DisableCVLCheck:    true,
+	}, []*SKey{{Ts: ts, Key: &key}}, func(s *DB, skey *SKey, key *Key, event SEvent) error {
+		events <- event
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("SubscribeDB() fails e = %v", err)
+	}
+
+	expect := func(exp SEvent) {
+		t.Helper()
+		select {
+		case e := <-events:
+			if e != exp {
+				t.Fatalf("SubscribeDB() event = %v; expected %v", e, exp)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("SubscribeDB() event %v not received", exp)
+		}
+	}
+
+	openTestClient(t, ConfigDB).HSet("SUB_RESYNC|k1", "a", "1")
+	expect(SEventHSet)
+
+	// The redis restart loses the connections, and the keyspace
+	// notifications config.
+	mb.Restart()
+	expect(SEventResync)
+
+	openTestClient(t, ConfigDB).HSet("SUB_RESYNC|k2", "a", "1")
+	expect(SEventHSet)
+
+	if err = s.UnsubscribeDB(); err != nil {
+		t.Fatalf("UnsubscribeDB() fails e = %v", err)
+	}
+	expect(SEventClose)
+}
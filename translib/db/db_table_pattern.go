@@ -119,6 +119,15 @@ func (d *DB) GetTablePattern(ts *TableSpec, pat Key) (Table, error) {
 		}
 	}
 
+	// Checkpoint Datastore has the whole data in memory
+	if d.cpDs != nil {
+		for _, redisKey := range d.cpDs.keys(d.key2redis(ts, pat)) {
+			table.entry[redisKey] = d.cpDs.getEntry(redisKey)
+			keys = append(keys, d.redis2key(ts, redisKey))
+		}
+		goto GetTablePatternFoundCache
+	}
+
 	// Run the Lua script
 	luaTable, err = luaScriptGetTable.Run(d.client,
 		[]string{d.key2redis(ts, pat)}).Result()
@@ -258,6 +267,8 @@ GetTablePatternFoundCache:
 			stats.GetTablePatternPeak = dur
 		}
 		stats.GetTablePatternTime += dur
+
+		d.observeOp(&stats, OpGetTablePattern, ts, d.key2redis(ts, pat), dur)
 	}
 
 	if d.dbStatsConfig.TableStats {
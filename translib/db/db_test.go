@@ -210,6 +210,8 @@ func testTableTearDown(tableEntries int) {
 
 }
 
+// TestMain runs against the redis-server; set TRANSLIB_DB_BACKEND=memory
+// (BackendEnv) to run hermetically on the in-memory backend.
 func TestMain(m *testing.M) {
 
 	exitCode := 0
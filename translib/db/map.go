@@ -56,6 +56,7 @@ func (d *DB) GetMap(ts *TableSpec, mapKey string) (string, error) {
 	var mAP MAP
 	var e error
 	var v string
+	var gen uint64
 
 	// If cache GetFromCache (CacheHit?)
 	if d.dbCacheConfig.PerConnection && d.dbCacheConfig.isCacheMap(ts.Name) {
@@ -67,6 +68,11 @@ func (d *DB) GetMap(ts *TableSpec, mapKey string) (string, error) {
 		}
 	}
 
+	useGCache := d.dbCacheConfig.Global && d.dbCacheConfig.isCacheMap(ts.Name)
+	if !cacheHit && useGCache {
+		v, gen, cacheHit = dbGlobalCache.getMap(d, ts, mapKey)
+	}
+
 	if !cacheHit {
 
 		glog.Info("GetMap: RedisCmd: ", d.Name(), ": ", "HGET ", ts.Name,
@@ -86,6 +92,10 @@ func (d *DB) GetMap(ts *TableSpec, mapKey string) (string, error) {
 			d.cache.Maps[ts.Name].mapMap[mapKey] = v
 		}
 
+		if useGCache && e == nil {
+			dbGlobalCache.putMap(d, ts, mapKey, v, gen)
+		}
+
 	}
 
 	// Time End, Time, Peak
@@ -113,6 +123,8 @@ func (d *DB) GetMap(ts *TableSpec, mapKey string) (string, error) {
 			stats.GetMapPeak = dur
 		}
 		stats.GetMapTime += dur
+
+		d.observeOp(&stats, OpGetMap, ts, mapKey, dur)
 	}
 
 	if d.dbStatsConfig.MapStats {
@@ -152,6 +164,7 @@ func (d *DB) GetMapAll(ts *TableSpec) (Value, error) {
 	var e error
 	var value Value
 	var v map[string]string
+	var gen uint64
 
 	// If cache GetFromCache (CacheHit?)
 	if d.dbCacheConfig.PerConnection && d.dbCacheConfig.isCacheMap(ts.Name) {
@@ -164,6 +177,11 @@ func (d *DB) GetMapAll(ts *TableSpec) (Value, error) {
 		}
 	}
 
+	useGCache := d.dbCacheConfig.Global && d.dbCacheConfig.isCacheMap(ts.Name)
+	if !cacheHit && useGCache {
+		value, gen, cacheHit = dbGlobalCache.getMapAll(d, ts)
+	}
+
 	if !cacheHit {
 
 		glog.Info("GetMapAll: RedisCmd: ", d.Name(), ": ", "HGETALL ", ts.Name)
@@ -183,6 +201,10 @@ func (d *DB) GetMapAll(ts *TableSpec) (Value, error) {
 				}
 			}
 
+			if useGCache {
+				dbGlobalCache.putMapAll(d, ts, value, gen)
+			}
+
 		} else {
 			if glog.V(1) {
 				glog.Info("GetMapAll: HGetAll(): empty map")
@@ -223,6 +245,8 @@ func (d *DB) GetMapAll(ts *TableSpec) (Value, error) {
 			stats.GetMapAllPeak = dur
 		}
 		stats.GetMapAllTime += dur
+
+		d.observeOp(&stats, OpGetMapAll, ts, "", dur)
 	}
 
 	if d.dbStatsConfig.MapStats {
@@ -24,14 +24,21 @@ package db
 
 import (
 	"errors"
+	"net"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Azure/sonic-mgmt-common/translib/tlerr"
+	"github.com/go-redis/redis/v7"
 	"github.com/golang/glog"
 )
 
 // SKey is (TableSpec, Key, []SEvent) 3-tuples to be watched in a Transaction.
+// A TableSpec Name "*" watches across the tables (with an empty Key, all the
+// keys of the DB); the notified Key then is the redis key split at the
+// TableNameSeparator, i.e. {table, rest of the key} (or {map name}).
 type SKey struct {
 	Ts     *TableSpec
 	Key    *Key
@@ -48,9 +55,11 @@ const (
 	SEventDel                 // DEL, & also if key gets deleted (empty HASH, expire,..)
 	SEventOther               // Some other command not covered above.
 
-	// The below two are always sent regardless of SEMap.
-	SEventClose // Close requested due to Unsubscribe() called.
-	SEventErr   // Error condition. Call Unsubscribe, after return.
+	// The below are always sent regardless of SEMap.
+	SEventClose  // Close requested due to Unsubscribe() called.
+	SEventErr    // Error condition. Call Unsubscribe, after return.
+	SEventResync // Resubscribed after a connection loss. Sent per SKey.
+	// Notifications may have been lost, i.e. the SKey must be re-read.
 )
 
 var redisPayload2sEventMap map[string]SEvent = map[string]SEvent{
@@ -87,6 +96,19 @@ func (sN SessNotif) String() string {
 	return sN2StringMap[sN]
 }
 
+// subscribeRetryMin and subscribeRetryMax bound the (exponential) backoff
+// of resubscribing after a connection loss.
+var subscribeRetryMin = 100 * time.Millisecond
+var subscribeRetryMax = 10 * time.Second
+
+// subscribeHealthCheck is the idle time after which the connection is
+// PINGed. No reply within another subscribeHealthCheck is a connection loss.
+var subscribeHealthCheck = 15 * time.Second
+
+// sPubSubMu guards the sPubSub replacement (on resubscribe) against the
+// UnsubscribeDB.
+var sPubSubMu sync.Mutex
+
 func init() {
 	// Optimization: Start the goroutine that is scanning the SubscribeDB
 	// channels. Instead of one goroutine per Subscribe.
@@ -132,7 +154,6 @@ func iSubscribeDB(opt Options, skeys []*SKey, handler interface{}) (*DB, error)
 
 	patterns := make([]string, 0, len(skeys))
 	patMap := make(map[string]([]int), len(skeys))
-	var s string
 
 	if !opt.IsWriteDisabled {
 		glog.Info("SubscribeDB: Setting IsWriteDisabled")
@@ -148,15 +169,6 @@ func iSubscribeDB(opt Options, skeys []*SKey, handler interface{}) (*DB, error)
 		goto SubscribeDBExit
 	}
 
-	// Make sure that the DB is configured for key space notifications
-	// Optimize with LUA scripts to atomically add "Kgshxe".
-	s, e = d.client.ConfigSet("notify-keyspace-events", "AKE").Result()
-
-	if e != nil {
-		glog.Error("SubscribeDB: ConfigSet(): e: ", e, " s: ", s)
-		goto SubscribeDBExit
-	}
-
 	for i := 0; i < len(skeys); i++ {
 		pattern := d.key2redisChannel(skeys[i].Ts, *(skeys[i].Key))
 		if _, present := patMap[pattern]; !present {
@@ -169,22 +181,9 @@ func iSubscribeDB(opt Options, skeys []*SKey, handler interface{}) (*DB, error)
 
 	glog.Info("SubscribeDB: patterns: ", patterns)
 
-	d.sPubSub = d.client.PSubscribe(patterns[:]...)
-
-	if d.sPubSub == nil {
-		glog.Error("SubscribeDB: PSubscribe() nil: pats: ", patterns)
-		e = tlerr.TranslibDBSubscribeFail{}
-		goto SubscribeDBExit
-	}
-
 	d.sOnCCacheDB = d.Opts.SDB
 
-	// Wait for confirmation, of channel creation
-	_, e = d.sPubSub.Receive()
-
-	if e != nil {
-		glog.Error("SubscribeDB: Receive() fails: e: ", e)
-		e = tlerr.TranslibDBSubscribeFail{}
+	if d.sPubSub, e = psubscribe(d.client, patterns); e != nil {
 		goto SubscribeDBExit
 	}
 
@@ -192,47 +191,13 @@ func iSubscribeDB(opt Options, skeys []*SKey, handler interface{}) (*DB, error)
 	d.registerSubscribeDB(isSA, skeys, handler)
 
 	// Start a goroutine to read messages and call handler.
-	go func() {
-		for msg := range d.sPubSub.Channel() {
-			if glog.V(4) {
-				glog.Info("SubscribeDB: msg: ", msg)
-			}
-
-			// Should this be a goroutine, in case each notification CB
-			// takes a long time to run ?
-			for _, skeyIndex := range patMap[msg.Pattern] {
-				skey := skeys[skeyIndex]
-				key := d.redisChannel2key(skey.Ts, msg.Channel)
-				sevent := d.redisPayload2sEvent(msg.Payload)
-
-				if len(skey.SEMap) == 0 || skey.SEMap[sevent] {
-
-					if glog.V(2) {
-						glog.Info("SubscribeDB: handler( ",
-							&d, ", ", skey, ", ", key, ", ", sevent, " )")
-					}
-
-					if isSA {
-						hFuncSA(d, RunningConfigNotif, "", skey, &key, sevent)
-					} else {
-						hFunc(d, skey, &key, sevent)
-					}
-				}
-			}
-		}
-
-		// Send the Close|Err notification.
-		var sEvent = SEventClose
-		if !d.sCIP {
-			sEvent = SEventErr
-		}
-		glog.Info("SubscribeDB: SEventClose|Err: ", sEvent)
+	go d.sReceive(skeys, patterns, func(skey *SKey, key *Key, sevent SEvent) {
 		if isSA {
-			hFuncSA(d, RunningConfigNotif, "", &SKey{}, &Key{}, sEvent)
+			hFuncSA(d, RunningConfigNotif, "", skey, key, sevent)
 		} else {
-			hFunc(d, &SKey{}, &Key{}, sEvent)
+			hFunc(d, skey, key, sevent)
 		}
-	}()
+	}, patMap)
 
 SubscribeDBExit:
 
@@ -259,22 +224,26 @@ SubscribeDBExit:
 func (d *DB) UnsubscribeDB() error {
 
 	var e error = nil
+	var sPubSub *redis.PubSub
 
 	if glog.V(3) {
 		glog.Info("UnsubscribeDB: d:", d)
 	}
 
-	if d.sCIP {
+	if d.isSubscribeClosed() {
 		glog.Error("UnsubscribeDB: Close in Progress")
 		e = errors.New("UnsubscribeDB: Close in Progress")
 		goto UnsubscribeDBExit
 	}
 
 	// Mark close in progress.
+	sPubSubMu.Lock()
 	d.sCIP = true
+	sPubSub = d.sPubSub
+	sPubSubMu.Unlock()
 
 	// Do the close, ch gets closed too.
-	d.sPubSub.Close()
+	sPubSub.Close()
 
 	// Wait for the goroutine to complete ? TBD
 	// Should not this happen because of the range statement on ch?
@@ -294,6 +263,159 @@ UnsubscribeDBExit:
 	return e
 }
 
+// sReceive reads the notifications, and calls the notify for the matching
+// SKeys, till UnsubscribeDB. On a connection loss, it resubscribes with
+// backoff, and sends SEventResync for each SKey.
+func (d *DB) sReceive(skeys []*SKey, patterns []string,
+	notify func(*SKey, *Key, SEvent), patMap map[string][]int) {
+
+	sPubSubMu.Lock()
+	ps := d.sPubSub
+	sPubSubMu.Unlock()
+
+	var pinged bool
+	for {
+		msg, e := ps.ReceiveTimeout(subscribeHealthCheck)
+		if e == nil {
+			pinged = false
+			if msg, ok := msg.(*redis.Message); ok {
+				d.sDispatch(skeys, msg, notify, patMap)
+			}
+			continue
+		}
+
+		if d.isSubscribeClosed() {
+			break
+		}
+
+		if ne, ok := e.(net.Error); ok && ne.Timeout() && !pinged {
+			pinged = true
+			if e = ps.Ping(); e == nil {
+				continue
+			}
+		}
+
+		glog.Warning("SubscribeDB: connection lost: ", e)
+		if ps = d.sResubscribe(patterns); ps == nil {
+			break
+		}
+		pinged = false
+
+		for _, skey := range skeys {
+			glog.Info("SubscribeDB: SEventResync: ", skey)
+			notify(skey, &Key{}, SEventResync)
+		}
+	}
+
+	// Send the Close|Err notification.
+	var sEvent = SEventClose
+	if !d.isSubscribeClosed() {
+		sEvent = SEventErr
+	}
+	glog.Info("SubscribeDB: SEventClose|Err: ", sEvent)
+	notify(&SKey{}, &Key{}, sEvent)
+}
+
+// sDispatch calls the notify for the SKeys of the message pattern.
+func (d *DB) sDispatch(skeys []*SKey, msg *redis.Message,
+	notify func(*SKey, *Key, SEvent), patMap map[string][]int) {
+
+	if glog.V(4) {
+		glog.Info("SubscribeDB: msg: ", msg)
+	}
+
+	// Should this be a goroutine, in case each notification CB
+	// takes a long time to run ?
+	for _, skeyIndex := range patMap[msg.Pattern] {
+		skey := skeys[skeyIndex]
+		key := d.redisChannel2key(skey.Ts, msg.Channel)
+		sevent := d.redisPayload2sEvent(msg.Payload)
+
+		if len(skey.SEMap) == 0 || skey.SEMap[sevent] {
+
+			if glog.V(2) {
+				glog.Info("SubscribeDB: handler( ",
+					&d, ", ", skey, ", ", key, ", ", sevent, " )")
+			}
+
+			notify(skey, &key, sevent)
+		}
+	}
+}
+
+// sResubscribe replaces the sPubSub with a new subscription to the patterns,
+// retrying with backoff. Returns nil, if UnsubscribeDB is called meanwhile.
+func (d *DB) sResubscribe(patterns []string) *redis.PubSub {
+	sPubSubMu.Lock()
+	closed, client := d.sCIP, d.client
+	d.sPubSub.Close()
+	sPubSubMu.Unlock()
+
+	for retry := subscribeRetryMin; !closed; {
+		ps, e := psubscribe(client, patterns)
+
+		sPubSubMu.Lock()
+		if closed = d.sCIP; e == nil && !closed {
+			d.sPubSub = ps
+		}
+		sPubSubMu.Unlock()
+
+		if e == nil {
+			if closed {
+				ps.Close()
+				break
+			}
+			glog.Info("SubscribeDB: resubscribed: ", patterns)
+			return ps
+		}
+
+		glog.Warningf("SubscribeDB: resubscribe fails: %v; retry in %v",
+			e, retry)
+		time.Sleep(retry)
+		if retry *= 2; retry > subscribeRetryMax {
+			retry = subscribeRetryMax
+		}
+		closed = d.isSubscribeClosed()
+	}
+
+	return nil
+}
+
+// psubscribe configures the DB for key space notifications, and subscribes
+// to the patterns.
+func psubscribe(client *redis.Client, patterns []string) (*redis.PubSub, error) {
+	// Make sure that the DB is configured for key space notifications
+	// Optimize with LUA scripts to atomically add "Kgshxe".
+	s, e := client.ConfigSet("notify-keyspace-events", "AKE").Result()
+
+	if e != nil {
+		glog.Error("SubscribeDB: ConfigSet(): e: ", e, " s: ", s)
+		return nil, e
+	}
+
+	ps := client.PSubscribe(patterns[:]...)
+
+	if ps == nil {
+		glog.Error("SubscribeDB: PSubscribe() nil: pats: ", patterns)
+		return nil, tlerr.TranslibDBSubscribeFail{}
+	}
+
+	// Wait for confirmation, of channel creation
+	if _, e = ps.Receive(); e != nil {
+		glog.Error("SubscribeDB: Receive() fails: e: ", e)
+		ps.Close()
+		return nil, tlerr.TranslibDBSubscribeFail{}
+	}
+
+	return ps, nil
+}
+
+func (d *DB) isSubscribeClosed() bool {
+	sPubSubMu.Lock()
+	defer sPubSubMu.Unlock()
+	return d.sCIP
+}
+
 func (d *DB) key2redisChannel(ts *TableSpec, key Key) string {
 
 	if glog.V(5) {
@@ -301,6 +423,9 @@ func (d *DB) key2redisChannel(ts *TableSpec, key Key) string {
 	}
 
 	dbId := strconv.Itoa(d.Opts.DBNo.ID())
+	if ts.Name == "*" && len(key.Comp) == 0 {
+		return "__keyspace@" + dbId + "__:*"
+	}
 	return "__keyspace@" + dbId + "__:" + d.key2redis(ts, key)
 }
 
@@ -312,6 +437,11 @@ func (d *DB) redisChannel2key(ts *TableSpec, redisChannel string) Key {
 
 	splitRedisKey := strings.SplitN(redisChannel, ":", 2)
 
+	if len(splitRedisKey) > 1 && ts.Name == "*" {
+		return Key{Comp: strings.SplitN(splitRedisKey[1],
+			d.Opts.TableNameSeparator, 2)}
+	}
+
 	if len(splitRedisKey) > 1 {
 		return d.redis2key(ts, splitRedisKey[1])
 	}
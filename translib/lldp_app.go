@@ -26,7 +26,9 @@ import (
 	"strings"
 
 	"github.com/Azure/sonic-mgmt-common/translib/db"
+	"github.com/Azure/sonic-mgmt-common/translib/internal/apis"
 	"github.com/Azure/sonic-mgmt-common/translib/ocbinds"
+	"github.com/Azure/sonic-mgmt-common/translib/path"
 	"github.com/Azure/sonic-mgmt-common/translib/tlerr"
 	log "github.com/golang/glog"
 	"github.com/openconfig/ygot/ygot"
@@ -139,11 +141,157 @@ func (app *lldpApp) translateAction(dbs [db.MaxDB]*db.DB) error {
 }
 
 func (app *lldpApp) translateSubscribe(req translateSubRequest) (translateSubResponse, error) {
-	return emptySubscribeResponse(req.path)
+	ymap := yangMapTree{
+		subtree: map[string]*yangMapTree{
+			"interfaces/interface": {
+				subtree: map[string]*yangMapTree{
+					"neighbors/neighbor": {
+						mapFunc: app.translateSubscribeNeighbor,
+						subtree: map[string]*yangMapTree{
+							"capabilities": {
+								mapFunc: app.translateSubscribeNeighborCaps,
+							},
+						},
+					},
+				},
+			},
+		}}
+
+	nb := notificationInfoBuilder{
+		pathInfo: NewPathInfo(req.path),
+		yangMap:  ymap,
+	}
+	return nb.Build()
+}
+
+// newNeighborInfo creates a notificationAppInfo for LLDP_ENTRY_TABLE entries.
+// Both interface name and neighbor id are mapped to the table key.
+func (app *lldpApp) newNeighborInfo(nb *notificationInfoBuilder) {
+	ifName := nb.pathInfo.StringVar("name", "*")
+	nbrID := nb.pathInfo.StringVar("id", "*")
+
+	nb.New().PathKey("id", nbrID)
+	path.SetKeyAt(nb.currentPath, lldpIntfElemIndex, "name", ifName)
+	nb.Table(db.ApplDB, "LLDP_ENTRY_TABLE").Key(ifName)
+}
+
+func (app *lldpApp) translateSubscribeNeighbor(nb *notificationInfoBuilder) error {
+	app.newNeighborInfo(nb)
+	if nb.SetFieldPrefix("state") {
+		nb.Field("system-name", LLDP_REMOTE_SYS_NAME)
+		nb.Field("system-description", LLDP_REMOTE_SYS_DESC)
+		nb.Field("chassis-id", LLDP_REMOTE_CHASS_ID)
+		nb.Field("chassis-id-type", LLDP_REMOTE_CHASS_ID_SUBTYPE)
+		nb.Field("port-id", LLDP_REMOTE_PORT_ID)
+		nb.Field("port-id-type", LLDP_REMOTE_PORT_ID_SUBTYPE)
+		nb.Field("port-description", LLDP_REMOTE_PORT_DESC)
+		nb.Field("id", LLDP_REMOTE_REM_ID)
+		nb.Field("management-address", LLDP_REMOTE_MAN_ADDR)
+	}
+	return nil
+}
+
+func (app *lldpApp) translateSubscribeNeighborCaps(nb *notificationInfoBuilder) error {
+	// Capabilities are decoded from the bitmap fields; cannot be mapped
+	// to yang leaves directly. Use a custom handler to diff them.
+	// Entry create/delete are handled by the parent neighbor mapping,
+	// unless the capabilities container is the subscribe target.
+	isTarget := (nb.treeDepth == 0)
+	app.newNeighborInfo(nb)
+	nb.HandlerFunc(processLldpCapsOnChange).Opaque(isTarget)
+	return nil
 }
 
 func (app *lldpApp) processSubscribe(req processSubRequest) (processSubResponse, error) {
-	return processSubResponse{}, tlerr.New("not implemented")
+	resp := processSubResponse{
+		path: req.path,
+	}
+
+	if req.table == nil || req.table.Name != "LLDP_ENTRY_TABLE" {
+		return resp, tlerr.New("Unknown table: %v", tableInfo(req.table))
+	}
+
+	ifName := req.key.Get(0)
+	path.SetKeyAt(resp.path, lldpIntfElemIndex, "name", ifName)
+	path.SetKeyAt(resp.path, lldpNeighElemIndex, "id", ifName)
+
+	return resp, nil
+}
+
+// Path element indices of the interface, neighbor and capability
+// lists in the /openconfig-lldp:lldp/interfaces paths
+const (
+	lldpIntfElemIndex  = 2
+	lldpNeighElemIndex = 4
+	lldpCapsElemIndex  = 5
+)
+
+// processLldpCapsOnChange is the on_change handler for the neighbor
+// capabilities. Notifies the capabilities added, removed or whose
+// enabled status changed.
+func processLldpCapsOnChange(nc *apis.NotificationContext, ns apis.NotificationSender) {
+	isTarget, _ := nc.Opaque.(bool)
+	if !isTarget && (nc.EntryCreated || nc.EntryDeleted) {
+		return
+	}
+
+	ifName := nc.Key.Get(0)
+	capsPath := path.SubPath(nc.Path, 0, lldpCapsElemIndex+1)
+	path.SetKeyAt(capsPath, lldpIntfElemIndex, "name", ifName)
+	path.SetKeyAt(capsPath, lldpNeighElemIndex, "id", ifName)
+
+	n := apis.Notification{Path: path.String(capsPath)}
+	if nc.EntryDeleted {
+		n.Delete = []string{""}
+		ns.Send(&n)
+		return
+	}
+
+	// Subscribe path can point to a specific capability
+	capFilter := "*"
+	if path.Len(nc.Path) > lldpCapsElemIndex+1 {
+		capFilter = nc.Path.Elem[lldpCapsElemIndex+1].Key["name"]
+		if i := strings.IndexByte(capFilter, ':'); i >= 0 {
+			capFilter = capFilter[i+1:]
+		}
+	}
+
+	oldCaps := decodeLldpCapabilities(nc.OldValue)
+	newCaps := decodeLldpCapabilities(nc.NewValue)
+
+	for capName := range oldCaps {
+		if _, ok := newCaps[capName]; !ok && wildcardMatch(capFilter, capName) {
+			n.Delete = append(n.Delete, "/capability[name="+capName+"]")
+		}
+	}
+	for capName, enabled := range newCaps {
+		if old, ok := oldCaps[capName]; (!ok || old != enabled) && wildcardMatch(capFilter, capName) {
+			n.UpdatePaths = append(n.UpdatePaths, "/capability[name="+capName+"]")
+		}
+	}
+
+	log.V(2).Infof("LLDP capabilities of %s: old=%v, new=%v", ifName, oldCaps, newCaps)
+	if len(n.Delete) != 0 || len(n.UpdatePaths) != 0 {
+		ns.Send(&n)
+	}
+}
+
+// decodeLldpCapabilities returns the supported capabilities and their enabled
+// status from a LLDP_ENTRY_TABLE entry. Map keys are the capability names
+// as in the openconfig-lldp-types:LLDP_SYSTEM_CAPABILITY identities.
+func decodeLldpCapabilities(entry db.Value) map[string]bool {
+	caps := make(map[string]bool)
+	for _, c := range decodeLldpSysCap(entry.Get(LLDP_REMOTE_CAP_SUPPORTED)) {
+		if name, err := ygot.EnumName(lldpCapabilityTypes[c]); err == nil {
+			caps[name] = false
+		}
+	}
+	for _, c := range decodeLldpSysCap(entry.Get(LLDP_REMOTE_CAP_ENABLED)) {
+		if name, err := ygot.EnumName(lldpCapabilityTypes[c]); err == nil {
+			caps[name] = true
+		}
+	}
+	return caps
 }
 
 func (app *lldpApp) processCreate(d *db.DB) (SetResponse, error) {
@@ -210,7 +358,7 @@ func (app *lldpApp) processGet(dbs [db.MaxDB]*db.DB, fmtType TranslibFmtType) (G
 			ygot.BuildEmptyTree(oneIfInfo)
 			app.getLldpNeighInfoFromInternalMap(&ifname, oneIfInfo)
 		}
-	} else if targetUriPath == "/openconfig-lldp:lldp/interfaces/interface" {
+	} else if strings.HasPrefix(targetUriPath, "/openconfig-lldp:lldp/interfaces/interface") {
 		intfObj := lldpIntfObj.Interfaces
 		ygot.BuildEmptyTree(intfObj)
 		if intfObj.Interface != nil && len(intfObj.Interface) > 0 {
@@ -239,13 +387,20 @@ func (app *lldpApp) processAction(dbs [db.MaxDB]*db.DB) (ActionResponse, error)
 /** Helper function to populate JSON response for GET request **/
 func (app *lldpApp) getLldpNeighInfoFromInternalMap(ifName *string, ifInfo *ocbinds.OpenconfigLldp_Lldp_Interfaces_Interface) {
 
-	ngInfo, err := ifInfo.Neighbors.NewNeighbor(*ifName)
-	if err != nil {
-		log.Info("Creation of subinterface subtree failed!")
+	neighAttrMap, ok := app.lldpNeighTableMap[*ifName]
+	if !ok {
+		log.Infof("No LLDP neighbor on %s", *ifName)
 		return
 	}
+	ngInfo, ok := ifInfo.Neighbors.Neighbor[*ifName]
+	if !ok {
+		var err error
+		if ngInfo, err = ifInfo.Neighbors.NewNeighbor(*ifName); err != nil {
+			log.Info("Creation of subinterface subtree failed!")
+			return
+		}
+	}
 	ygot.BuildEmptyTree(ngInfo)
-	neighAttrMap := app.lldpNeighTableMap[*ifName]
 	for attr, value := range neighAttrMap {
 		switch attr {
 		case LLDP_REMOTE_SYS_NAME:
@@ -294,30 +449,20 @@ func (app *lldpApp) getLldpNeighInfoFromInternalMap(ifName *string, ifInfo *ocbi
 	}
 	capLst := app.lldpCapTableMap[*ifName]
 	for capName, enabled := range capLst {
-		if capName == "Router" {
-			capInfo, err := ngInfo.Capabilities.NewCapability(6)
-			if err == nil {
-				ygot.BuildEmptyTree(capInfo)
-				capInfo.State.Name = 6
-				capInfo.State.Enabled = &enabled
-			}
-		} else if capName == "Repeater" {
-			capInfo, err := ngInfo.Capabilities.NewCapability(5)
-			if err == nil {
-				ygot.BuildEmptyTree(capInfo)
-				capInfo.State.Name = 5
-				capInfo.State.Enabled = &enabled
-			}
-		} else if capName == "Bridge" {
-			capInfo, err := ngInfo.Capabilities.NewCapability(3)
-			if err == nil {
-				ygot.BuildEmptyTree(capInfo)
-				capInfo.State.Name = 3
-				capInfo.State.Enabled = &enabled
+		capType, ok := lldpCapabilityTypes[capName]
+		if !ok {
+			continue
+		}
+		capInfo, ok := ngInfo.Capabilities.Capability[capType]
+		if !ok {
+			var err error
+			if capInfo, err = ngInfo.Capabilities.NewCapability(capType); err != nil {
+				continue
 			}
-		} else {
-
 		}
+		ygot.BuildEmptyTree(capInfo)
+		capInfo.State.Name = capType
+		capInfo.State.Enabled = ygot.Bool(enabled)
 	}
 }
 
@@ -394,43 +539,53 @@ func (app *lldpApp) getLldpInfoFromDB(ifname *string) {
 
 /** Helper function to get remote system capabilities into a map **/
 func (app *lldpApp) getRemoteSysCap(capb string, ifname string, setCap bool) {
-	num_str := strings.Split(capb, " ")
-	byte, _ := hex.DecodeString(num_str[0] + num_str[1])
-	sysCap := byte[0]
-	sysCap |= byte[1]
-
-	log.Info("sysCap: ", sysCap)
-
-	if (sysCap & (128 >> 1)) != 0 {
+	for _, capName := range decodeLldpSysCap(capb) {
 		if app.lldpCapTableMap[ifname] == nil {
 			app.lldpCapTableMap[ifname] = make(map[string]bool)
-			app.lldpCapTableMap[ifname]["Repeater"] = false
+		}
+		if _, ok := app.lldpCapTableMap[ifname][capName]; !ok {
+			app.lldpCapTableMap[ifname][capName] = false
 		}
 		if setCap {
-			log.Info("Repeater ENABLED")
-			app.lldpCapTableMap[ifname]["Repeater"] = true
+			log.Info(capName, " ENABLED")
+			app.lldpCapTableMap[ifname][capName] = true
 		}
 	}
+}
 
-	if (sysCap & (128 >> 2)) != 0 {
-		if app.lldpCapTableMap[ifname] == nil {
-			app.lldpCapTableMap[ifname] = make(map[string]bool)
-			app.lldpCapTableMap[ifname]["Bridge"] = false
-		}
-		if setCap {
-			log.Info("Bridge  ENABLED")
-			app.lldpCapTableMap[ifname]["Bridge"] = true
-		}
+// lldpCapabilityTypes maps the capability names used in lldpCapTableMap
+// to the openconfig-lldp-types:LLDP_SYSTEM_CAPABILITY identities.
+var lldpCapabilityTypes = map[string]ocbinds.E_OpenconfigLldpTypes_LLDP_SYSTEM_CAPABILITY{
+	"Repeater": ocbinds.OpenconfigLldpTypes_LLDP_SYSTEM_CAPABILITY_REPEATER,
+	"Bridge":   ocbinds.OpenconfigLldpTypes_LLDP_SYSTEM_CAPABILITY_MAC_BRIDGE,
+	"Router":   ocbinds.OpenconfigLldpTypes_LLDP_SYSTEM_CAPABILITY_ROUTER,
+}
+
+/** Helper function to decode the system capabilities bitmap **/
+func decodeLldpSysCap(capb string) []string {
+	num_str := strings.Fields(capb)
+	if len(num_str) < 2 {
+		return nil
+	}
+	byte, err := hex.DecodeString(num_str[0] + num_str[1])
+	if err != nil || len(byte) < 2 {
+		log.Infof("Invalid system capabilities \"%s\"", capb)
+		return nil
 	}
+	sysCap := byte[0]
+	sysCap |= byte[1]
 
+	log.Info("sysCap: ", sysCap)
+
+	var caps []string
+	if (sysCap & (128 >> 1)) != 0 {
+		caps = append(caps, "Repeater")
+	}
+	if (sysCap & (128 >> 2)) != 0 {
+		caps = append(caps, "Bridge")
+	}
 	if (sysCap & (128 >> 4)) != 0 {
-		if app.lldpCapTableMap[ifname] == nil {
-			app.lldpCapTableMap[ifname] = make(map[string]bool)
-			app.lldpCapTableMap[ifname]["Router"] = false
-		}
-		if setCap {
-			log.Info("Router ENABLED")
-			app.lldpCapTableMap[ifname]["Router"] = true
-		}
+		caps = append(caps, "Router")
 	}
+	return caps
 }
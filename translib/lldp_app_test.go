@@ -0,0 +1,126 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package translib
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Azure/sonic-mgmt-common/translib/db"
+)
+
+func Test_decodeLldpSysCap(t *testing.T) {
+	tests := map[string][]string{
+		"":            nil,
+		"28":          nil,
+		"zz 00":       nil,
+		"28 00":       {"Bridge", "Router"},
+		"00 40":       {"Repeater"},
+		"68 00 extra": {"Repeater", "Bridge", "Router"},
+		"00 00":       nil,
+	}
+	for capb, exp := range tests {
+		if caps := decodeLldpSysCap(capb); !reflect.DeepEqual(caps, exp) {
+			t.Errorf("decodeLldpSysCap(%q) = %v; expected %v", capb, caps, exp)
+		}
+	}
+}
+
+func Test_decodeLldpCapabilities(t *testing.T) {
+	entry := db.Value{Field: map[string]string{
+		LLDP_REMOTE_CAP_SUPPORTED: "28 00",
+		LLDP_REMOTE_CAP_ENABLED:   "20 00",
+	}}
+	exp := map[string]bool{"MAC_BRIDGE": true, "ROUTER": false}
+	if caps := decodeLldpCapabilities(entry); !reflect.DeepEqual(caps, exp) {
+		t.Errorf("decodeLldpCapabilities(%v) = %v; expected %v", entry.Field, caps, exp)
+	}
+}
+
+func Test_LldpApp_Subscribe(t *testing.T) {
+	intfPath := "/openconfig-lldp:lldp/interfaces/interface"
+	stateFields := `{"state": {` +
+		`"lldp_rem_sys_name": "system-name", "lldp_rem_sys_desc": "system-description", ` +
+		`"lldp_rem_chassis_id": "chassis-id", "lldp_rem_chassis_id_subtype": "chassis-id-type", ` +
+		`"lldp_rem_port_id": "port-id", "lldp_rem_port_id_subtype": "port-id-type", ` +
+		`"lldp_rem_port_desc": "port-description", "lldp_rem_index": "id", ` +
+		`"lldp_rem_man_addr": "management-address"}}`
+
+	t.Run("top", func(t *testing.T) {
+		tv := testTranslateSubscribe(t, "/openconfig-lldp:lldp")
+		tv.VerifyCount(1, 1)
+		tv.VerifyTarget(intfPath+"[name=*]/neighbors/neighbor[id=*]", lldpNeighborNInfo("*", stateFields))
+		tv.VerifyChild(intfPath+"[name=*]/neighbors/neighbor[id=*]/capabilities", lldpCapsNInfo("*"))
+	})
+
+	t.Run("interface", func(t *testing.T) {
+		tv := testTranslateSubscribe(t, intfPath+"[name=Ethernet0]")
+		tv.VerifyCount(1, 1)
+		tv.VerifyTarget(intfPath+"[name=Ethernet0]/neighbors/neighbor[id=*]", lldpNeighborNInfo("Ethernet0", stateFields))
+		tv.VerifyChild(intfPath+"[name=Ethernet0]/neighbors/neighbor[id=*]/capabilities", lldpCapsNInfo("Ethernet0"))
+	})
+
+	t.Run("neighbor", func(t *testing.T) {
+		nPath := intfPath + "[name=Ethernet0]/neighbors/neighbor[id=Ethernet0]"
+		tv := testTranslateSubscribe(t, nPath)
+		tv.VerifyCount(1, 1)
+		tv.VerifyTarget(nPath, lldpNeighborNInfo("Ethernet0", stateFields))
+		tv.VerifyChild(nPath+"/capabilities", lldpCapsNInfo("Ethernet0"))
+	})
+
+	t.Run("neighbor_state", func(t *testing.T) {
+		nPath := intfPath + "[name=*]/neighbors/neighbor[id=*]/state"
+		tv := testTranslateSubscribe(t, nPath)
+		tv.VerifyCount(1, 0)
+		tv.VerifyTarget(nPath, lldpNeighborNInfo("*", strings.Replace(stateFields, `"state"`, `""`, 1)))
+	})
+
+	t.Run("neighbor_leaf", func(t *testing.T) {
+		nPath := intfPath + "[name=Ethernet4]/neighbors/neighbor[id=*]/state/system-name"
+		tv := testTranslateSubscribe(t, nPath)
+		tv.VerifyCount(1, 0)
+		tv.VerifyTarget(nPath, lldpNeighborNInfo("Ethernet4", `{"": {"lldp_rem_sys_name": ""}}`))
+	})
+
+	t.Run("capabilities", func(t *testing.T) {
+		nPath := intfPath + "[name=*]/neighbors/neighbor[id=*]/capabilities"
+		tv := testTranslateSubscribe(t, nPath)
+		tv.VerifyCount(1, 0)
+		tv.VerifyTarget(nPath, lldpCapsNInfo("*"))
+	})
+}
+
+func lldpNeighborNInfo(ifName, fieldsJson string) *notificationAppInfo {
+	return &notificationAppInfo{
+		dbno:                db.ApplDB,
+		table:               &db.TableSpec{Name: "LLDP_ENTRY_TABLE"},
+		key:                 db.NewKey(ifName),
+		dbFldYgPathInfoList: parseFieldsJSON(fieldsJson),
+		isOnChangeSupported: true,
+		pType:               OnChange,
+	}
+}
+
+func lldpCapsNInfo(ifName string) *notificationAppInfo {
+	nInfo := lldpNeighborNInfo(ifName, "")
+	nInfo.handlerFunc = processLldpCapsOnChange
+	return nInfo
+}
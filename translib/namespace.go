@@ -0,0 +1,205 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package translib
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Azure/sonic-mgmt-common/translib/db"
+	"github.com/Azure/sonic-mgmt-common/translib/tlerr"
+	"github.com/Workiva/go-datastructures/queue"
+	log "github.com/golang/glog"
+)
+
+// GetNamespaces returns the DB namespaces. Only the default namespace ("")
+// is present on single ASIC platforms.
+func GetNamespaces() []string {
+	return db.GetNamespaces()
+}
+
+// GetAllNamespaces performs the Get in each of the DB namespaces, and
+// returns the responses indexed by the namespace. Namespaces which do not
+// have the requested data are omitted; returns the not found error if none
+// of them have it. Other errors fail the whole request.
+func GetAllNamespaces(req GetRequest) (map[string]GetResponse, error) {
+	var notFound error
+	resps := make(map[string]GetResponse)
+
+	for _, ns := range db.GetNamespaces() {
+		req.Namespace = ns
+		resp, err := Get(req)
+		if tlerr.IsNotFound(err) {
+			log.V(3).Infof("GetAllNamespaces: %s: %v", ns, err)
+			if notFound == nil {
+				notFound = err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		resps[ns] = resp
+	}
+
+	if len(resps) == 0 && notFound != nil {
+		return nil, notFound
+	}
+	return resps, nil
+}
+
+// SubscribeAllNamespaces performs the Subscribe in each of the DB namespaces.
+// Notifications of all the namespaces are pushed to req.Q, with their
+// SubscribeResponse.Namespace set. A single SyncComplete notification is
+// pushed after the initial updates of all the namespaces. Closing req.Stop
+// stops the subscriptions of all the namespaces. Resume options are not
+// supported.
+func SubscribeAllNamespaces(req SubscribeRequest) error {
+	if len(req.ResumeID) != 0 {
+		return tlerr.NotSupported("Resume is not supported with all namespaces")
+	}
+
+	nsList := db.GetNamespaces()
+	fo := &nsFanout{q: req.Q, pendingSync: len(nsList)}
+
+	for _, ns := range nsList {
+		nsReq := req
+		nsReq.Namespace = ns
+		nsReq.Q = queue.NewPriorityQueue(1, true)
+		nsReq.Stop = make(chan struct{})
+
+		if err := Subscribe(nsReq); err != nil {
+			log.Warningf("SubscribeAllNamespaces: %s: %v", ns, err)
+			nsReq.Q.Dispose()
+			fo.stop()
+			return err
+		}
+
+		fo.add(nsReq.Q, nsReq.Stop)
+		go fo.forward(ns, nsReq.Q)
+	}
+
+	go func() {
+		<-req.Stop
+		fo.stop()
+	}()
+
+	return nil
+}
+
+// StreamAllNamespaces performs the Stream in each of the DB namespaces.
+// The values of all the namespaces are pushed to req.Q, with their
+// SubscribeResponse.Namespace set; followed by a single SyncComplete.
+func StreamAllNamespaces(req SubscribeRequest) error {
+	nsList := db.GetNamespaces()
+
+	for i, ns := range nsList {
+		nsReq := req
+		nsReq.Namespace = ns
+		nsReq.Q = queue.NewPriorityQueue(1, true)
+
+		err := Stream(nsReq)
+		items, _ := nsReq.Q.Get(nsReq.Q.Len())
+		nsReq.Q.Dispose()
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			resp := item.(*SubscribeResponse)
+			if resp.SyncComplete && i != len(nsList)-1 {
+				continue // Sync only after the last namespace
+			}
+			resp.Namespace = ns
+			if err = req.Q.Put(resp); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// nsFanout merges the notifications of the per namespace subscriptions
+// of SubscribeAllNamespaces.
+type nsFanout struct {
+	mu          sync.Mutex
+	q           *queue.PriorityQueue // Client queue
+	nsQs        []*queue.PriorityQueue
+	nsStops     []chan struct{}
+	pendingSync int // Number of namespaces yet to complete the sync
+	stopped     bool
+}
+
+func (fo *nsFanout) add(q *queue.PriorityQueue, stop chan struct{}) {
+	fo.mu.Lock()
+	defer fo.mu.Unlock()
+	fo.nsQs = append(fo.nsQs, q)
+	fo.nsStops = append(fo.nsStops, stop)
+}
+
+// stop stops the subscriptions of all the namespaces.
+func (fo *nsFanout) stop() {
+	fo.mu.Lock()
+	defer fo.mu.Unlock()
+	if fo.stopped {
+		return
+	}
+	fo.stopped = true
+	for i, stop := range fo.nsStops {
+		close(stop)
+		fo.nsQs[i].Dispose()
+	}
+}
+
+// forward pushes the notifications of a namespace to the client queue,
+// until the namespace queue is disposed.
+func (fo *nsFanout) forward(ns string, nsQ *queue.PriorityQueue) {
+	for {
+		items, err := nsQ.Get(1)
+		if err != nil {
+			return
+		}
+
+		resp := items[0].(*SubscribeResponse)
+		resp.Namespace = ns
+		if resp.SyncComplete && !resp.IsTerminated {
+			if !fo.syncDone() {
+				continue
+			}
+			// Order it after the updates of the other namespaces
+			resp.Timestamp = time.Now().UnixNano()
+		}
+
+		if err = fo.q.Put(resp); err != nil {
+			log.Warningf("SubscribeAllNamespaces: %s: %v", ns, err)
+			return
+		}
+	}
+}
+
+// syncDone marks the initial sync of a namespace is complete; and returns
+// true if all the namespaces have completed it.
+func (fo *nsFanout) syncDone() bool {
+	fo.mu.Lock()
+	defer fo.mu.Unlock()
+	fo.pendingSync--
+	return fo.pendingSync == 0
+}
@@ -0,0 +1,74 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package translib
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Workiva/go-datastructures/queue"
+)
+
+func TestNsFanout(t *testing.T) {
+	q := queue.NewPriorityQueue(1, true)
+	fo := &nsFanout{q: q, pendingSync: 2}
+	var ts int64
+
+	for _, ns := range []string{"", "asic0"} {
+		nsQ := queue.NewPriorityQueue(1, true)
+		fo.add(nsQ, make(chan struct{}))
+		go fo.forward(ns, nsQ)
+
+		ts++
+		nsQ.Put(&SubscribeResponse{Path: "/p/" + ns, Timestamp: ts})
+		ts++
+		nsQ.Put(&SubscribeResponse{SyncComplete: true, Timestamp: ts})
+	}
+
+	for i := 0; q.Len() < 3 && i < 50; i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+	var resps []*SubscribeResponse
+	items, _ := q.Get(q.Len())
+	for _, item := range items {
+		resps = append(resps, item.(*SubscribeResponse))
+	}
+	if len(resps) != 3 {
+		t.Fatalf("Expected 3 responses; got %v", resps)
+	}
+
+	syncCount := 0
+	for _, resp := range resps {
+		if resp.SyncComplete {
+			syncCount++
+		} else if resp.Path != "/p/"+resp.Namespace {
+			t.Errorf("Wrong namespace %q for %s", resp.Namespace, resp.Path)
+		}
+	}
+	if syncCount != 1 || !resps[2].SyncComplete {
+		t.Errorf("Expected one SyncComplete at the end; got %v", resps)
+	}
+
+	fo.stop()
+	time.Sleep(100 * time.Millisecond)
+	if q.Len() != 0 {
+		t.Errorf("Unexpected response after stop")
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"sync/atomic"
 	"syscall"
 
+	"github.com/Azure/sonic-mgmt-common/internal/runtimeconfig"
 	"github.com/golang/glog"
 	"github.com/openconfig/ygot/ygot"
 )
@@ -38,9 +39,14 @@ type emitJSONImpl func(s ygot.ValidatedGoStruct, opts *EmitJSONOptions) ([]byte,
 // It is defined as int32 instead of bool for using with atomic APIs.
 var useYgotEmitJSON int32
 
+// ygotEmitJSONConfig is the TRANSLIB_DB|default "use_ygot_emitjson" runtime
+// config; "True", "False", or "" to use the marker file.
+var ygotEmitJSONConfig atomic.Value
+
 func init() {
 	setEmitJSONImpl()
 	installSignalHandler()
+	registerRuntimeConfigHook()
 }
 
 // getEmitJSONImpl returns an emitJSONImpl function that should be used for
@@ -53,8 +59,20 @@ func getEmitJSONImpl() emitJSONImpl {
 }
 
 // setEmitJSONImpl configures the emitJSONImpl to be used by setting the useYgotEmitJSON
-// flag -- based on presence of a marker file /var/run/{exe_name}/use_ygot_emitjson.
+// flag -- based on the use_ygot_emitjson runtime config, if set; else the presence of a
+// marker file /var/run/{exe_name}/use_ygot_emitjson.
 func setEmitJSONImpl() {
+	switch cfg, _ := ygotEmitJSONConfig.Load().(string); cfg {
+	case "True":
+		glog.Infof("use_ygot_emitjson is %s; will use ygot.EmitJSON()", cfg)
+		atomic.StoreInt32(&useYgotEmitJSON, 1)
+		return
+	case "False":
+		glog.Infof("use_ygot_emitjson is %s; will use internal EmitJSON", cfg)
+		atomic.StoreInt32(&useYgotEmitJSON, 0)
+		return
+	}
+
 	exeName := filepath.Base(os.Args[0])
 	markerFile := filepath.Join("/var/run", exeName, "use_ygot_emitjson")
 	if root, ok := os.LookupEnv("SYSROOT"); ok {
@@ -83,6 +101,24 @@ func installSignalHandler() {
 	}()
 }
 
+// registerRuntimeConfigHook registers a runtime config hook which calls
+// setEmitJSONImpl when the use_ygot_emitjson config changes.
+func registerRuntimeConfigHook() {
+	runtimeconfig.Register(runtimeconfig.Hook{
+		Name: "use_ygot_emitjson",
+		Parse: func(fields map[string]string) interface{} {
+			return fields["use_ygot_emitjson"]
+		},
+		OnChange: func(_, cfg interface{}) {
+			ygotEmitJSONConfig.Store(cfg.(string))
+			setEmitJSONImpl()
+		},
+		Current: func() interface{} {
+			return atomic.LoadInt32(&useYgotEmitJSON) != 0
+		},
+	})
+}
+
 // ygotEmitJSON implements ygot-to-json translation using the ygot.EmitJSON
 // library API.
 func ygotEmitJSON(s ygot.ValidatedGoStruct, opts *EmitJSONOptions) ([]byte, error) {
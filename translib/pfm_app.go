@@ -19,16 +19,65 @@
 package translib
 
 import (
+	"encoding/binary"
 	"errors"
 	"github.com/Azure/sonic-mgmt-common/translib/db"
+	"github.com/Azure/sonic-mgmt-common/translib/internal/apis"
 	"github.com/Azure/sonic-mgmt-common/translib/ocbinds"
+	"github.com/Azure/sonic-mgmt-common/translib/path"
 	"github.com/Azure/sonic-mgmt-common/translib/tlerr"
 	log "github.com/golang/glog"
 	"github.com/openconfig/ygot/ygot"
+	"math"
 	"reflect"
 	"strconv"
+	"strings"
 )
 
+// Platform tables in STATE_DB. Keys of PSU_INFO, FAN_INFO and
+// TEMPERATURE_INFO tables are the component names.
+const (
+	EEPROM_INFO_TABLE      = "EEPROM_INFO"
+	PSU_INFO_TABLE         = "PSU_INFO"
+	FAN_INFO_TABLE         = "FAN_INFO"
+	TEMPERATURE_INFO_TABLE = "TEMPERATURE_INFO"
+)
+
+// SYS_EEPROM_NAME is the component name for the EEPROM_INFO data
+const SYS_EEPROM_NAME = "System Eeprom"
+
+// pfmComponentTables are the tables with one entry per component
+var pfmComponentTables = []string{PSU_INFO_TABLE, FAN_INFO_TABLE, TEMPERATURE_INFO_TABLE}
+
+// pfmCompElemIndex is the path element index of the component list
+// in /openconfig-platform:components/component paths
+const pfmCompElemIndex = 1
+
+// eepromLeafNames maps the EEPROM TLV names to the "System Eeprom"
+// component state leaves
+var eepromLeafNames = map[string]string{
+	"Product Name":     "id",
+	"Part Number":      "part-no",
+	"Serial Number":    "serial-no",
+	"Service Tag":      "serial-no",
+	"Manufacture Date": "mfg-date",
+	"Label Revision":   "hardware-version",
+	"Hardware Version": "hardware-version",
+	"Platform Name":    "description",
+	"Manufacturer":     "mfg-name",
+	"Vendor Name":      "mfg-name",
+	"Software Version": "software-version",
+}
+
+func eepromLeafExists(leaf string) bool {
+	for _, v := range eepromLeafNames {
+		if v == leaf {
+			return true
+		}
+	}
+	return false
+}
+
 type PlatformApp struct {
 	path        *PathInfo
 	reqData     []byte
@@ -63,7 +112,7 @@ func (app *PlatformApp) initialize(data appData) {
 	app.reqData = data.payload
 	app.ygotRoot = data.ygotRoot
 	app.ygotTarget = data.ygotTarget
-	app.eepromTs = &db.TableSpec{Name: "EEPROM_INFO"}
+	app.eepromTs = &db.TableSpec{Name: EEPROM_INFO_TABLE}
 
 }
 
@@ -78,11 +127,140 @@ func (app *PlatformApp) translateAction(dbs [db.MaxDB]*db.DB) error {
 }
 
 func (app *PlatformApp) translateSubscribe(req translateSubRequest) (translateSubResponse, error) {
-	return emptySubscribeResponse(req.path)
+	ymap := yangMapTree{
+		subtree: map[string]*yangMapTree{
+			"components/component": {
+				subtree: map[string]*yangMapTree{
+					"state": {
+						mapFunc: app.translateSubscribeComponentState,
+						subtree: map[string]*yangMapTree{
+							"temperature": {
+								mapFunc: app.translateSubscribeTemperature,
+							},
+						},
+					},
+					"power-supply/state": {
+						mapFunc: app.translateSubscribePsuState,
+					},
+				},
+			},
+		}}
+
+	nb := notificationInfoBuilder{
+		pathInfo: NewPathInfo(req.path),
+		yangMap:  ymap,
+	}
+	return nb.Build()
+}
+
+// componentName returns the component name from the subscribe path and
+// sets it as the component list key in the path being built.
+func (app *PlatformApp) componentName(nb *notificationInfoBuilder) string {
+	name := nb.pathInfo.StringVar("name", "*")
+	path.SetKeyAt(nb.currentPath, pfmCompElemIndex, "name", name)
+	return name
+}
+
+func (app *PlatformApp) translateSubscribeComponentState(nb *notificationInfoBuilder) error {
+	name := app.componentName(nb)
+
+	// EEPROM_INFO has one entry per TLV; each of them is mapped to
+	// a leaf of the "System Eeprom" component based on its Name field.
+	if wildcardMatch(name, SYS_EEPROM_NAME) {
+		leaf := ""
+		if i := nb.currentIndx + 1; i < path.Len(nb.currentPath) {
+			leaf = nb.currentPath.Elem[i].Name
+		}
+		if len(leaf) == 0 || eepromLeafExists(leaf) {
+			nb.New().Table(db.StateDB, EEPROM_INFO_TABLE).Key("*")
+			nb.HandlerFunc(processEepromOnChange).Opaque(leaf)
+		}
+	}
+	if name == SYS_EEPROM_NAME {
+		return nil
+	}
+
+	for _, table := range []string{PSU_INFO_TABLE, FAN_INFO_TABLE} {
+		nb.New().Table(db.StateDB, table).Key(name)
+		if nb.SetFieldPrefix("") {
+			nb.Field("empty", "presence")
+			nb.Field("oper-status", "presence")
+			nb.Field("oper-status", "status")
+			nb.Field("part-no", "model")
+			nb.Field("serial-no", "serial")
+			nb.Field("hardware-version", "revision")
+		}
+	}
+	return nil
+}
+
+// translateSubscribeTemperature maps the sensor readings. They change
+// continuously; hence supports only sample mode.
+func (app *PlatformApp) translateSubscribeTemperature(nb *notificationInfoBuilder) error {
+	name := app.componentName(nb)
+	if name == SYS_EEPROM_NAME {
+		return nil
+	}
+	for _, table := range []string{TEMPERATURE_INFO_TABLE, PSU_INFO_TABLE} {
+		nb.New().Table(db.StateDB, table).Key(name)
+		nb.OnChange(false).Preferred(Sample)
+	}
+	return nil
+}
+
+func (app *PlatformApp) translateSubscribePsuState(nb *notificationInfoBuilder) error {
+	name := app.componentName(nb)
+	if name == SYS_EEPROM_NAME {
+		return nil
+	}
+	nb.New().Table(db.StateDB, PSU_INFO_TABLE).Key(name)
+	nb.OnChange(false).Preferred(Sample)
+	return nil
 }
 
 func (app *PlatformApp) processSubscribe(req processSubRequest) (processSubResponse, error) {
-	return processSubResponse{}, tlerr.New("not implemented")
+	resp := processSubResponse{
+		path: req.path,
+	}
+
+	if req.table == nil {
+		return resp, tlerr.New("Unknown table: %v", tableInfo(req.table))
+	}
+
+	switch req.table.Name {
+	case EEPROM_INFO_TABLE:
+		path.SetKeyAt(resp.path, pfmCompElemIndex, "name", SYS_EEPROM_NAME)
+	case PSU_INFO_TABLE, FAN_INFO_TABLE, TEMPERATURE_INFO_TABLE:
+		path.SetKeyAt(resp.path, pfmCompElemIndex, "name", req.key.Get(0))
+	default:
+		return resp, tlerr.New("Unknown table: %s", req.table.Name)
+	}
+
+	return resp, nil
+}
+
+// processEepromOnChange is the on_change handler for EEPROM_INFO table.
+// Notifies the "System Eeprom" component leaf corresponding to the TLV.
+func processEepromOnChange(nc *apis.NotificationContext, ns apis.NotificationSender) {
+	tlvName := nc.NewValue.Get("Name")
+	if len(tlvName) == 0 {
+		tlvName = nc.OldValue.Get("Name")
+	}
+	leaf := eepromLeafNames[tlvName]
+	if len(leaf) == 0 {
+		log.V(2).Infof("Ignore EEPROM TLV %v (%s)", nc.Key, tlvName)
+		return
+	}
+	if filter, _ := nc.Opaque.(string); len(filter) != 0 && filter != leaf {
+		return
+	}
+
+	statePath := path.SubPath(nc.Path, 0, pfmCompElemIndex+2)
+	path.SetKeyAt(statePath, pfmCompElemIndex, "name", SYS_EEPROM_NAME)
+	ns.Send(&apis.Notification{
+		Path:        path.String(statePath),
+		UpdatePaths: []string{"/" + leaf},
+	})
 }
 
 func (app *PlatformApp) translateCreate(d *db.DB) ([]db.WatchKeys, error) {
@@ -192,7 +370,16 @@ func (app *PlatformApp) processGet(dbs [db.MaxDB]*db.DB, fmtType TranslibFmtType
 	var err error
 
 	if isSubtreeRequest(targetUriPath, "/openconfig-platform:components") {
-		err = app.doGetSysEeprom()
+		switch compName := app.path.Var("name"); compName {
+		case SYS_EEPROM_NAME:
+			err = app.doGetSysEeprom()
+		case "":
+			if err = app.doGetSysEeprom(); err == nil {
+				err = app.doGetAllComponents(stateDb)
+			}
+		default:
+			err = app.doGetComponent(stateDb, compName)
+		}
 	} else {
 		err = errors.New("Not supported component")
 	}
@@ -497,3 +684,130 @@ func (app *PlatformApp) doGetSysEeprom() error {
 	}
 	return err
 }
+
+// doGetAllComponents fills all the components from PSU_INFO, FAN_INFO
+// and TEMPERATURE_INFO tables.
+func (app *PlatformApp) doGetAllComponents(d *db.DB) error {
+	pf_cpts := app.getAppRootObject()
+
+	for _, table := range pfmComponentTables {
+		ts := &db.TableSpec{Name: table}
+		keys, err := d.GetKeys(ts)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			e, err := d.GetEntry(ts, key)
+			if err != nil {
+				log.Warningf("%s entry %v get failed; err=%v", table, key, err)
+				continue
+			}
+			compName := key.Get(0)
+			pf_comp := pf_cpts.Component[compName]
+			if pf_comp == nil {
+				pf_comp, _ = pf_cpts.NewComponent(compName)
+			}
+			fillComponentFromDb(pf_comp, table, e)
+		}
+	}
+	return nil
+}
+
+// doGetComponent fills a component from PSU_INFO, FAN_INFO and
+// TEMPERATURE_INFO tables. Returns NotFound error if none of
+// these tables have an entry for the component.
+func (app *PlatformApp) doGetComponent(d *db.DB, compName string) error {
+	pf_cpts := app.getAppRootObject()
+	pf_comp := pf_cpts.Component[compName]
+	found := false
+
+	for _, table := range pfmComponentTables {
+		e, err := d.GetEntry(&db.TableSpec{Name: table}, asKey(compName))
+		if err != nil {
+			continue
+		}
+		if pf_comp == nil {
+			pf_comp, _ = pf_cpts.NewComponent(compName)
+		}
+		fillComponentFromDb(pf_comp, table, e)
+		found = true
+	}
+
+	if !found {
+		return tlerr.NotFound("Invalid component name %s", compName)
+	}
+	return nil
+}
+
+// fillComponentFromDb fills the component data from a PSU_INFO,
+// FAN_INFO or TEMPERATURE_INFO table entry.
+func fillComponentFromDb(pf_comp *ocbinds.OpenconfigPlatform_Components_Component, table string, e db.Value) {
+	ygot.BuildEmptyTree(pf_comp)
+	state := pf_comp.State
+	state.Name = pf_comp.Name
+
+	switch table {
+	case PSU_INFO_TABLE, FAN_INFO_TABLE:
+		present := (e.Get("presence") == "true")
+		state.Empty = ygot.Bool(!present)
+		state.Removable = ygot.Bool(true)
+		switch {
+		case !present:
+			state.OperStatus = ocbinds.OpenconfigPlatformTypes_COMPONENT_OPER_STATUS_DISABLED
+		case e.Get("status") == "true":
+			state.OperStatus = ocbinds.OpenconfigPlatformTypes_COMPONENT_OPER_STATUS_ACTIVE
+		default:
+			state.OperStatus = ocbinds.OpenconfigPlatformTypes_COMPONENT_OPER_STATUS_INACTIVE
+		}
+		if v := e.Get("model"); v != "" && v != "N/A" {
+			state.PartNo = ygot.String(v)
+		}
+		if v := e.Get("serial"); v != "" && v != "N/A" {
+			state.SerialNo = ygot.String(v)
+		}
+		if v := e.Get("revision"); v != "" && v != "N/A" {
+			state.HardwareVersion = ygot.String(v)
+		}
+	}
+
+	switch table {
+	case PSU_INFO_TABLE:
+		psu := pf_comp.PowerSupply.State
+		psu.OutputVoltage = toIeeeFloat32(e.Get("voltage"))
+		psu.OutputCurrent = toIeeeFloat32(e.Get("current"))
+		psu.OutputPower = toIeeeFloat32(e.Get("power"))
+		if v, err := strconv.ParseFloat(e.Get("temp"), 64); err == nil {
+			state.Temperature.Instant = &v
+		}
+
+	case TEMPERATURE_INFO_TABLE:
+		temp := state.Temperature
+		if v, err := strconv.ParseFloat(e.Get("temperature"), 64); err == nil {
+			temp.Instant = &v
+		}
+		if v, err := strconv.ParseFloat(e.Get("minimum_temperature"), 64); err == nil {
+			temp.Min = &v
+		}
+		if v, err := strconv.ParseFloat(e.Get("maximum_temperature"), 64); err == nil {
+			temp.Max = &v
+		}
+		if v, err := strconv.ParseFloat(e.Get("high_threshold"), 64); err == nil && v >= 0 {
+			temp.AlarmThreshold = ygot.Uint32(uint32(math.Round(v)))
+		}
+		if v := e.Get("warning_status"); v != "" {
+			temp.AlarmStatus = ygot.Bool(strings.EqualFold(v, "true"))
+		}
+	}
+}
+
+// toIeeeFloat32 converts a decimal string to the binary representation
+// of openconfig-types:ieeefloat32. Returns nil if the value is invalid.
+func toIeeeFloat32(v string) ocbinds.Binary {
+	f, err := strconv.ParseFloat(v, 32)
+	if err != nil {
+		return nil
+	}
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, math.Float32bits(float32(f)))
+	return b
+}
@@ -34,6 +34,9 @@ const (
 	TEST_PLATFORM_NAME = "x86_64-pfm_test-platform"
 	TEST_SERVICE_TAG   = "6776X6776"
 	TEST_MANUF_NAME    = "TestManufacture"
+	TEST_PSU_NAME      = "PSU 1"
+	TEST_PSU_MODEL     = "PWR-500AC-F"
+	TEST_PSU_SERIAL    = "PSU0123456789"
 )
 
 type EepromEntry struct {
@@ -74,6 +77,111 @@ func Test_PfmApp_TopLevelPath(t *testing.T) {
 	t.Run("Get_Full_Pfm_Tree_Top_Level", processGetRequest(url, bulkPfmShowAllJsonResponse, false))
 }
 
+// This will test GET on PSU_INFO based component
+func Test_PfmApp_PsuComponent(t *testing.T) {
+	url := "/openconfig-platform:components/component[name=" + TEST_PSU_NAME + "]/state"
+
+	t.Run("Get_Unknown_Psu", processGetRequest(url, "", true))
+
+	if err := createPsuInfo(); err != nil {
+		t.Fatalf("Failed to add PSU_INFO to Db: %v", err)
+	}
+	defer clearPsuInfo()
+
+	t.Run("Get_Psu_State", processGetRequest(url, psuStateJsonResponse, false))
+}
+
+func Test_PfmApp_Subscribe(t *testing.T) {
+	compPath := "/openconfig-platform:components/component"
+
+	t.Run("top", func(t *testing.T) {
+		tv := testTranslateSubscribe(t, "/openconfig-platform:components")
+		tv.VerifyCount(4, 2)
+		tv.VerifyTarget(compPath+"[name=*]/power-supply/state", pfmTableNInfo(PSU_INFO_TABLE, "*", false))
+	})
+
+	t.Run("eeprom", func(t *testing.T) {
+		tv := testTranslateSubscribe(t, compPath+"[name=System Eeprom]")
+		tv.VerifyCount(1, 0)
+		tv.VerifyTarget(compPath+"[name=System Eeprom]/state", eepromNInfo())
+	})
+
+	t.Run("eeprom_leaf", func(t *testing.T) {
+		tv := testTranslateSubscribe(t, compPath+"[name=System Eeprom]/state/serial-no")
+		tv.VerifyCount(1, 0)
+		tv.VerifyTarget(compPath+"[name=System Eeprom]/state/serial-no", eepromNInfo())
+	})
+
+	t.Run("eeprom_nonexisting_leaf", func(t *testing.T) {
+		tv := testTranslateSubscribe(t, compPath+"[name=System Eeprom]/state/location")
+		tv.VerifyCount(translErr, 0)
+	})
+
+	t.Run("psu", func(t *testing.T) {
+		tv := testTranslateSubscribe(t, compPath+"[name=PSU 1]/state")
+		tv.VerifyCount(2, 2)
+		tv.VerifyChild(compPath+"[name=PSU 1]/state/temperature", pfmTableNInfo(TEMPERATURE_INFO_TABLE, "PSU 1", false))
+	})
+
+	t.Run("psu_leaf", func(t *testing.T) {
+		tv := testTranslateSubscribe(t, compPath+"[name=*]/state/oper-status")
+		tv.VerifyCount(2, 0)
+	})
+
+	t.Run("sensor", func(t *testing.T) {
+		tv := testTranslateSubscribeForMode(t, compPath+"[name=*]/state/temperature", Sample)
+		tv.VerifyCount(2, 0)
+		tv.VerifyTarget(compPath+"[name=*]/state/temperature", pfmTableNInfo(TEMPERATURE_INFO_TABLE, "*", false))
+	})
+}
+
+func pfmTableNInfo(table, key string, onchange bool) *notificationAppInfo {
+	nInfo := &notificationAppInfo{
+		dbno:                db.StateDB,
+		table:               &db.TableSpec{Name: table},
+		key:                 db.NewKey(key),
+		isOnChangeSupported: onchange,
+		pType:               OnChange,
+	}
+	if !onchange {
+		nInfo.pType = Sample
+	}
+	return nInfo
+}
+
+func eepromNInfo() *notificationAppInfo {
+	nInfo := pfmTableNInfo(EEPROM_INFO_TABLE, "*", true)
+	nInfo.handlerFunc = processEepromOnChange
+	return nInfo
+}
+
+func createPsuInfo() error {
+	d := getStateDB()
+	if d == nil {
+		return errors.New("Failed to connect to state Db")
+	}
+	defer d.DeleteDB()
+
+	return d.SetEntry(&db.TableSpec{Name: PSU_INFO_TABLE}, asKey(TEST_PSU_NAME),
+		db.Value{Field: map[string]string{
+			"presence": "true",
+			"status":   "true",
+			"model":    TEST_PSU_MODEL,
+			"serial":   TEST_PSU_SERIAL,
+			"revision": "A01",
+		}})
+}
+
+func clearPsuInfo() error {
+	d := getStateDB()
+	if d == nil {
+		return errors.New("Failed to connect to state Db")
+	}
+	defer d.DeleteDB()
+
+	return d.DeleteEntry(&db.TableSpec{Name: PSU_INFO_TABLE}, asKey(TEST_PSU_NAME))
+}
+
 // THis will delete Platform Table from DB
 func clearPfmDataFromDb() error {
 	var err error
@@ -133,3 +241,5 @@ func getStateDB() *db.DB {
 var bulkPfmShowDefaultResponse string = "{\"openconfig-platform:components\":{\"component\":[{\"name\":\"System Eeprom\",\"state\":{\"empty\":false,\"location\":\"Slot 1\",\"name\":\"System Eeprom\",\"oper-status\":\"openconfig-platform-types:ACTIVE\",\"removable\":false}}]}}"
 
 var bulkPfmShowAllJsonResponse string = "{\"openconfig-platform:components\":{\"component\":[{\"name\":\"System Eeprom\",\"state\":{\"description\":\"" + TEST_PLATFORM_NAME + "\",\"empty\":false,\"id\":\"" + TEST_PRODUCT_NAME + "\",\"location\":\"Slot 1\",\"mfg-name\":\"" + TEST_MANUF_NAME + "\",\"name\":\"System Eeprom\",\"oper-status\":\"openconfig-platform-types:ACTIVE\",\"part-no\":\"" + TEST_PART_NUMBER + "\",\"removable\":false,\"serial-no\":\"" + TEST_SERVICE_TAG + "\"}}]}}"
+
+var psuStateJsonResponse string = "{\"openconfig-platform:state\":{\"empty\":false,\"hardware-version\":\"A01\",\"name\":\"" + TEST_PSU_NAME + "\",\"oper-status\":\"openconfig-platform-types:ACTIVE\",\"part-no\":\"" + TEST_PSU_MODEL + "\",\"removable\":true,\"serial-no\":\"" + TEST_PSU_SERIAL + "\"}}"
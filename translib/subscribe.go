@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Azure/sonic-mgmt-common/translib/db"
 	"github.com/Azure/sonic-mgmt-common/translib/internal/apis"
@@ -43,6 +44,28 @@ type SubscribeRequest struct {
 	AuthEnabled   bool
 	ClientVersion Version
 	Session       *SubscribeSession
+	Interval      int // Sample interval in seconds; used only for reporting via GetSubscriptions
+
+	// Resume options, for Subscribe API only. A subscription with non-empty
+	// ResumeID and ReplayBufSize is retained for ResumeTimeout after Stop.
+	// A new Subscribe request with the same ResumeID, paths and user can resume
+	// it by passing the last received SubscribeResponse.Sequence as ResumeSeq.
+	// Only the missed responses will be replayed then. Falls back to full
+	// initial sync if the missed responses are no longer in the replay buffer.
+	ResumeID      string        // Client assigned id for resuming the subscription
+	ResumeSeq     uint64        // Last SubscribeResponse.Sequence received by the client
+	ReplayBufSize int           // Number of recent responses retained for replay
+	ResumeTimeout time.Duration // How long a stopped subscription waits for resume
+
+	// Thresholds suppress notifications for small changes in numeric leaf
+	// values. For the Stream API, they are evaluated across the Stream calls
+	// of a sample subscription; hence require a Session.
+	Thresholds []*Threshold
+
+	// Namespace is the DB namespace, for multi-ASIC; "" is default.
+	// See SubscribeAllNamespaces and StreamAllNamespaces for subscribing
+	// all the namespaces.
+	Namespace string
 }
 
 type SubscribeResponse struct {
@@ -52,6 +75,8 @@ type SubscribeResponse struct {
 	Timestamp    int64
 	SyncComplete bool
 	IsTerminated bool
+	Sequence     uint64 // Sequence number for resume; 0 if resume is not enabled
+	Namespace    string // DB namespace; set only by the *AllNamespaces APIs
 }
 
 type IsSubscribeRequest struct {
@@ -148,6 +173,25 @@ type subscribeInfo struct {
 	stop     chan struct{}
 	sDBs     []*db.DB         //Subscription DB should be used only for keyspace notification unsubscription
 	dbs      [db.MaxDB]*db.DB //used to perform get operations
+
+	namespace string // DB namespace of the dbs
+
+	// Client info, for GetSubscriptions
+	session  string
+	user     string
+	paths    []string
+	mode     NotificationType
+	interval int
+	sKeys    []SubscribedDBKey // db keys watched for on_change
+	stats    subscribeStats
+
+	// Resume info
+	resumeID      string
+	resumeTimeout time.Duration
+	resumeTimer   *time.Timer
+	replay        *replayBuffer
+
+	thresholds *thresholdFilter // nil if no thresholds are configured
 }
 
 // notificationGroup is the grouping of notificationInfo by the key pattern.
@@ -162,28 +206,51 @@ var subscribeCounter Counter
 var stopMap map[chan struct{}]*subscribeInfo
 var cleanupMap map[*db.DB]*subscribeInfo
 
+// activeMap holds all active Subscribe and Stream requests, indexed by
+// subscribeInfo id. Used by GetSubscriptions.
+var activeMap map[string]*subscribeInfo
+
 func init() {
 	stopMap = make(map[chan struct{}]*subscribeInfo)
 	cleanupMap = make(map[*db.DB]*subscribeInfo)
+	activeMap = make(map[string]*subscribeInfo)
 }
 
 // Subscribe - Subscribes to the paths requested and sends notifications when the data changes in DB
 func Subscribe(req SubscribeRequest) error {
+	if len(req.ResumeID) != 0 && resumeSubscribe(req) {
+		return nil
+	}
+
 	sid := subscribeContextId(req.Session)
 	paths := req.Paths
 	log.Infof("[%v] Subscribe: paths = %v", sid, paths)
 
-	dbs, err := getAllDbs(withWriteDisable, withOnChange)
+	thresholds, err := newThresholdFilter(req.Thresholds)
+	if err != nil {
+		return err
+	}
+
+	dbs, err := getAllDbs(withWriteDisable, withOnChange,
+		withNamespace(req.Namespace))
 	if err != nil {
 		return err
 	}
 
 	sInfo := &subscribeInfo{
-		id:   sid,
-		q:    req.Q,
-		stop: req.Stop,
-		dbs:  dbs,
+		id:        sid,
+		q:         req.Q,
+		stop:      req.Stop,
+		dbs:       dbs,
+		namespace: req.Namespace,
+		user:      req.User.Name,
+		paths:     paths,
+		mode:      OnChange,
+
+		thresholds: thresholds,
 	}
+	sInfo.setSession(req.Session)
+	sInfo.setResumeOptions(req)
 
 	sCtx := subscribeContext{
 		id:      sid,
@@ -220,7 +287,7 @@ func Stream(req SubscribeRequest) error {
 	sid := subscribeContextId(req.Session)
 	log.Infof("[%v] Stream: paths = %v", sid, req.Paths)
 
-	dbs, err := getAllDbs(withWriteDisable)
+	dbs, err := getAllDbs(withWriteDisable, withNamespace(req.Namespace))
 	if err != nil {
 		return err
 	}
@@ -241,10 +308,25 @@ func Stream(req SubscribeRequest) error {
 		}
 	}
 
-	sInfo := &subscribeInfo{
-		id:  sid,
-		q:   req.Q,
-		dbs: dbs,
+	thresholds, err := newThresholdFilter(req.Thresholds)
+	if err != nil {
+		return err
+	}
+
+	sInfo := sampleSubscription(&req, sid, dbs, thresholds)
+	if req.Session == nil {
+		defer removeActiveSubscription(sInfo)
+	}
+
+	if tf := sInfo.thresholds; tf != nil {
+		sMutex.Lock()
+		tf.beginSample()
+		sMutex.Unlock()
+		defer func() {
+			sMutex.Lock()
+			tf.endSample()
+			sMutex.Unlock()
+		}()
 	}
 
 	for _, nInfo := range sc.tgtInfos {
@@ -255,7 +337,9 @@ func Stream(req SubscribeRequest) error {
 	}
 
 	// Push a SyncComplete message at the end
+	sMutex.Lock()
 	sInfo.syncDone = true
+	sMutex.Unlock()
 	sendSyncNotification(sInfo, false)
 	return nil
 }
@@ -377,6 +461,14 @@ func startDBSubscribe(opt db.Options, nGroups map[db.TableSpec]*notificationGrou
 		}
 
 		sKeyList = append(sKeyList, skeys...)
+		for _, sKey := range skeys {
+			sInfo.sKeys = append(sInfo.sKeys, SubscribedDBKey{
+				DB:        opt.DBNo.Name(),
+				Namespace: opt.Namespace,
+				Table:     sKey.Ts.Name,
+				Key:       strings.Join(sKey.Key.Comp, d.Opts.KeySeparator),
+			})
+		}
 
 		d.RegisterTableForOnChangeCaching(&tSpec)
 	}
@@ -462,9 +554,11 @@ func (sc *subscribeContext) startSubscribe() error {
 	sInfo := sc.sInfo
 
 	stopMap[sInfo.stop] = sInfo
+	activeMap[sInfo.id] = sInfo
 
 	for dbno, nGroups := range sc.dbNInfos {
-		opt := getDBOptions(dbno, withWriteDisable)
+		opt := getDBOptions(dbno, withWriteDisable,
+			withNamespace(sInfo.namespace))
 		err = startDBSubscribe(opt, nGroups, sInfo)
 
 		if err != nil {
@@ -921,33 +1015,45 @@ func stophandler(stop chan struct{}) {
 		<-stop
 		sMutex.Lock()
 		defer sMutex.Unlock()
-		cleanup(stop)
+		if !detach(stop) {
+			cleanup(stop)
+		}
 		return
 	}
 }
 
 func cleanup(stop chan struct{}) {
 	if sInfo, ok := stopMap[stop]; ok {
-		log.Infof("[%v] stopping..", sInfo.id)
-
-		for _, sDB := range sInfo.sDBs {
-			sDB.UnsubscribeDB()
-		}
-
-		sInfo.sDBs = nil
-		closeAllDbs(sInfo.dbs[:])
-
 		delete(stopMap, stop)
+		cleanupSubscription(sInfo)
 	}
 	//printAllMaps()
 }
 
+// cleanupSubscription closes all db subscriptions and db objects
+// of a subscribeInfo. Caller should hold sMutex.
+func cleanupSubscription(sInfo *subscribeInfo) {
+	log.Infof("[%v] stopping..", sInfo.id)
+
+	for _, sDB := range sInfo.sDBs {
+		sDB.UnsubscribeDB()
+	}
+
+	sInfo.sDBs = nil
+	closeAllDbs(sInfo.dbs[:])
+	sInfo.thresholds.reset()
+
+	delete(activeMap, sInfo.id)
+}
+
 // SubscribeSession is used to share session data between subscription
 // related APIs - IsSubscribeSupported, Subscribe and Stream.
 type SubscribeSession struct {
 	ID          string  // session id
 	callCounter Counter // API call counter
 	translatedPathCache
+
+	samples map[string]*subscribeInfo // Sample subscriptions, by sampleKey
 }
 
 // NewSubscribeSession creates a new SubscribeSession. Caller
@@ -964,6 +1070,7 @@ func NewSubscribeSession() *SubscribeSession {
 func (ss *SubscribeSession) Close() {
 	if ss != nil {
 		ss.reset()
+		ss.removeSampleSubscriptions()
 	}
 }
 
@@ -0,0 +1,190 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package translib
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Azure/sonic-mgmt-common/translib/db"
+)
+
+// SubscriptionInfo describes an active Subscribe or Stream request.
+// Returned by GetSubscriptions API.
+type SubscriptionInfo struct {
+	ID                   string            // Subscribe request id
+	SessionID            string            // SubscribeSession id; empty if request had no session
+	User                 string            // User name
+	Paths                []string          // Subscribed paths
+	Mode                 NotificationType  // OnChange for Subscribe; Sample for Stream
+	Interval             int               // Sample interval in seconds, if known
+	DBKeys               []SubscribedDBKey // DB keys watched for on_change notifications
+	NotificationCount    uint64            // Number of SubscribeResponse sent
+	LastNotificationTime time.Time         // Time of last SubscribeResponse; zero if none sent
+	QueueDepth           int               // Number of SubscribeResponse pending in the queue
+}
+
+// SubscribedDBKey identifies a db key pattern watched by a subscription.
+type SubscribedDBKey struct {
+	DB        string // DB name, like CONFIG_DB
+	Namespace string // DB namespace; empty for the default namespace
+	Table     string // Table name
+	Key       string // Key or key pattern, joined by the db's key separator
+}
+
+// subscribeStats holds per-subscription notification statistics.
+// Values are updated atomically.
+type subscribeStats struct {
+	count    uint64 // number of notifications sent
+	lastTime int64  // timestamp of last notification, in nanoseconds
+}
+
+func (ss *subscribeStats) notified(timestamp int64) {
+	atomic.AddUint64(&ss.count, 1)
+	atomic.StoreInt64(&ss.lastTime, timestamp)
+}
+
+// GetSubscriptions returns information about all active Subscribe and
+// Stream requests, sorted by the request id.
+func GetSubscriptions() []*SubscriptionInfo {
+	sMutex.Lock()
+	defer sMutex.Unlock()
+
+	infos := make([]*SubscriptionInfo, 0, len(activeMap))
+	for _, sInfo := range activeMap {
+		infos = append(infos, sInfo.toSubscriptionInfo())
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+	return infos
+}
+
+// toSubscriptionInfo creates a SubscriptionInfo snapshot from this subscribeInfo.
+func (sInfo *subscribeInfo) toSubscriptionInfo() *SubscriptionInfo {
+	info := &SubscriptionInfo{
+		ID:                sInfo.id,
+		SessionID:         sInfo.session,
+		User:              sInfo.user,
+		Paths:             append([]string(nil), sInfo.paths...),
+		Mode:              sInfo.mode,
+		Interval:          sInfo.interval,
+		DBKeys:            append([]SubscribedDBKey(nil), sInfo.sKeys...),
+		NotificationCount: atomic.LoadUint64(&sInfo.stats.count),
+	}
+	if ts := atomic.LoadInt64(&sInfo.stats.lastTime); ts != 0 {
+		info.LastNotificationTime = time.Unix(0, ts)
+	}
+	if sInfo.q != nil {
+		info.QueueDepth = sInfo.q.Len()
+	}
+	return info
+}
+
+func (sInfo *subscribeInfo) setSession(ss *SubscribeSession) {
+	if ss != nil {
+		sInfo.session = ss.ID
+	}
+}
+
+func addActiveSubscription(sInfo *subscribeInfo) {
+	sMutex.Lock()
+	activeMap[sInfo.id] = sInfo
+	sMutex.Unlock()
+}
+
+func removeActiveSubscription(sInfo *subscribeInfo) {
+	sMutex.Lock()
+	delete(activeMap, sInfo.id)
+	sMutex.Unlock()
+}
+
+// sampleSubscription returns the subscribeInfo of the sample subscription
+// of a Stream request, after registering it for GetSubscriptions. Stream is
+// called for every sample interval; hence the subscribeInfo is retained in
+// the session until the session is closed, and its stats accumulate across
+// the intervals. Requests without a session are reported only during the
+// Stream call; caller should remove them. The dbs of the request are set in
+// the subscribeInfo, and its syncDone is cleared.
+func sampleSubscription(req *SubscribeRequest, sid string, dbs [db.MaxDB]*db.DB,
+	thresholds *thresholdFilter) *subscribeInfo {
+	sMutex.Lock()
+	defer sMutex.Unlock()
+
+	ss := req.Session
+	key := sampleSubscriptionKey(req)
+	if ss != nil {
+		if sInfo := ss.samples[key]; sInfo != nil {
+			sInfo.dbs = dbs
+			sInfo.syncDone = false
+			return sInfo
+		}
+	}
+
+	sInfo := &subscribeInfo{
+		id:        sid,
+		q:         req.Q,
+		namespace: req.Namespace,
+		user:      req.User.Name,
+		paths:     req.Paths,
+		mode:      Sample,
+		interval:  req.Interval,
+		dbs:       dbs,
+
+		thresholds: thresholds,
+	}
+	sInfo.setSession(ss)
+	activeMap[sInfo.id] = sInfo
+
+	if ss != nil {
+		if ss.samples == nil {
+			ss.samples = make(map[string]*subscribeInfo)
+		}
+		ss.samples[key] = sInfo
+	}
+	return sInfo
+}
+
+// sampleSubscriptionKey returns the key of the sample subscription of the
+// request, in the session. The requests of a session with the same key are
+// the intervals of a sample subscription.
+func sampleSubscriptionKey(req *SubscribeRequest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d|%s|%p|%s", req.Interval, req.Namespace, req.Q,
+		strings.Join(req.Paths, ","))
+	for _, t := range req.Thresholds {
+		fmt.Fprintf(&b, "|%+v", *t)
+	}
+	return b.String()
+}
+
+// removeSampleSubscriptions unregisters the sample subscriptions of the
+// session.
+func (ss *SubscribeSession) removeSampleSubscriptions() {
+	sMutex.Lock()
+	for _, sInfo := range ss.samples {
+		delete(activeMap, sInfo.id)
+		sInfo.thresholds.reset()
+	}
+	ss.samples = nil
+	sMutex.Unlock()
+}
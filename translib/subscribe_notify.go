@@ -74,6 +74,14 @@ func notificationHandler(d *db.DB, sKey *db.SKey, key *db.Key, event db.SEvent)
 			log.Warningf("[%v] notificationHandler: SKey corrupted; nil opaque. %v", nid, *sKey)
 		}
 
+	case db.SEventResync:
+		// Notifications were lost while db layer was reconnecting.
+		if nGrup, ok := sKey.Opaque.(*notificationGroup); ok {
+			resyncNotificationGroup(nid, sKey, nGrup)
+		} else {
+			log.Warningf("[%v] notificationHandler: SKey corrupted; nil opaque. %v", nid, *sKey)
+		}
+
 	case db.SEventClose:
 		// Close event would have been triggered due to unsubscribe on stop request
 		delete(cleanupMap, d)
@@ -90,6 +98,62 @@ func notificationHandler(d *db.DB, sKey *db.SKey, key *db.Key, event db.SEvent)
 	return nil
 }
 
+// resyncNotificationGroup re-reads the db keys of a notificationGroup after
+// the db notifications were lost. Notifications are sent for the entries
+// created, modified or deleted meanwhile, by comparing with on-change cache.
+func resyncNotificationGroup(nid string, sKey *db.SKey, nGrup *notificationGroup) {
+	var nInfo *notificationInfo
+	for _, n := range nGrup.nInfos {
+		nInfo = n[0] // Pick any one nInfo from notificationGroup to read dbno and table spec
+		break
+	}
+	if nInfo == nil {
+		return
+	}
+
+	d := nInfo.sInfo.dbs[nInfo.dbno]
+	if d == nil {
+		log.V(2).Infof("[%s] defunct subscription", nid)
+		return
+	}
+
+	keys, err := d.GetKeysPattern(nInfo.table, *sKey.Key)
+	if err != nil {
+		log.Warningf("[%s] resync: failed to read keys %s/%v; err=%v",
+			nid, nInfo.table.Name, sKey.Key, err)
+		return
+	}
+	cachedKeys, _ := d.OnChangeCacheKeys(nInfo.table, *sKey.Key)
+
+	log.Infof("[%s] resync: %s/%v has %d keys; %d cached", nid,
+		nInfo.table.Name, sKey.Key, len(keys), len(cachedKeys))
+
+	present := make(map[string]bool, len(keys))
+	for i := range keys {
+		present[keys[i].String()] = true
+		n := notificationEvent{
+			id:    fmt.Sprintf("%s-r%d", nid, i),
+			event: db.SEventHSet,
+			key:   &keys[i],
+			nGrup: nGrup,
+		}
+		n.process()
+	}
+
+	for i := range cachedKeys {
+		if present[cachedKeys[i].String()] {
+			continue
+		}
+		n := notificationEvent{
+			id:    fmt.Sprintf("%s-d%d", nid, i),
+			event: db.SEventDel,
+			key:   &cachedKeys[i],
+			nGrup: nGrup,
+		}
+		n.process()
+	}
+}
+
 // sendInitialUpdate sends the initial sync updates to the caller.
 // Performs following steps:
 //  1. Scan all keys for the table
@@ -388,6 +452,14 @@ func (ne *notificationEvent) findModifiedFields(nInfo *notificationInfo, entryDi
 		return yInfos
 	}
 
+	if tf := nInfo.sInfo.thresholds; tf != nil {
+		if entryDiff.EntryDeleted {
+			tf.forget(nInfo, ne.key)
+		} else if len(entryDiff.DeletedFields) != 0 {
+			tf.forget(nInfo, ne.key, entryDiff.DeletedFields...)
+		}
+	}
+
 	// When entry is deleted, mark the whole target path as deleted
 	if targetPathDelete {
 		log.V(2).Infof("[%s] Entry deleted;", ne.id)
@@ -399,9 +471,14 @@ func (ne *notificationEvent) findModifiedFields(nInfo *notificationInfo, entryDi
 		return yInfos
 	}
 
-	// Collect yang leaf info for updated fields
-	if len(entryDiff.UpdatedFields) != 0 {
-		yInfos.new = ne.createYangPathInfos(nInfo, entryDiff.UpdatedFields, "update")
+	// Collect yang leaf info for updated fields. Skip the fields that
+	// changed by less than their subscription thresholds.
+	updatedFields := entryDiff.UpdatedFields
+	if tf := nInfo.sInfo.thresholds; tf != nil && len(updatedFields) != 0 {
+		updatedFields = tf.filterUpdatedFields(ne, nInfo, entryDiff)
+	}
+	if len(updatedFields) != 0 {
+		yInfos.new = ne.createYangPathInfos(nInfo, updatedFields, "update")
 	}
 
 	// Collect yang leaf info for created fields
@@ -782,12 +859,26 @@ func (ne *notificationEvent) sendNotification(nInfo *notificationInfo, relativeP
 }
 
 func (ne *notificationEvent) send(resp *SubscribeResponse) {
+	if tf := ne.sInfo.thresholds; tf != nil && ne.sInfo.mode == Sample {
+		sMutex.Lock()
+		tf.filterSampleUpdate(resp)
+		sMutex.Unlock()
+	}
+	if ne.sInfo.replay != nil && !resp.SyncComplete && !resp.IsTerminated {
+		ne.sInfo.replay.add(resp)
+	}
 	if log.V(5) {
 		log.Infof("[%s] SubscribeResponse %s", ne.id, objPrinter.Sprint(resp))
 	}
+	if ne.sInfo.q == nil {
+		log.V(2).Infof("[%v] Subscription is detached; response saved for replay", ne.id)
+		return
+	}
 	if err := ne.sInfo.q.Put(resp); err != nil {
 		log.Warningf("[%v] Response queue error: %v", ne.id, err)
+		return
 	}
+	ne.sInfo.stats.notified(resp.Timestamp)
 }
 
 // pocCallContext holds context info about a ProcessOnChange handler call.
@@ -0,0 +1,181 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package translib
+
+import (
+	"reflect"
+	"time"
+
+	log "github.com/golang/glog"
+)
+
+// resumeMap holds the stopped subscriptions that are waiting for
+// resume, indexed by the resume id. Guarded by sMutex.
+var resumeMap = make(map[string]*subscribeInfo)
+
+// replayBuffer is a bounded ring buffer of recent SubscribeResponse
+// objects of a subscription. It assigns monotonically increasing sequence
+// numbers to the responses. Guarded by sMutex.
+type replayBuffer struct {
+	entries []*SubscribeResponse
+	next    int    // index for the next entry
+	count   int    // number of valid entries
+	lastSeq uint64 // sequence number of the last entry
+}
+
+func newReplayBuffer(size int) *replayBuffer {
+	return &replayBuffer{entries: make([]*SubscribeResponse, size)}
+}
+
+// add assigns next sequence number to the response and saves it
+// in the buffer. Oldest entry is discarded if buffer is full.
+func (rb *replayBuffer) add(resp *SubscribeResponse) {
+	rb.lastSeq++
+	resp.Sequence = rb.lastSeq
+	rb.entries[rb.next] = resp
+	rb.next = (rb.next + 1) % len(rb.entries)
+	if rb.count < len(rb.entries) {
+		rb.count++
+	}
+}
+
+// since returns all responses with sequence number greater than seq.
+// Returns false if some of them were already discarded from the buffer
+// or if seq is not a valid sequence number.
+func (rb *replayBuffer) since(seq uint64) ([]*SubscribeResponse, bool) {
+	firstSeq := rb.lastSeq - uint64(rb.count) + 1
+	if seq > rb.lastSeq || seq+1 < firstSeq {
+		return nil, false
+	}
+
+	n := int(rb.lastSeq - seq)
+	resps := make([]*SubscribeResponse, 0, n)
+	for i := rb.next - n; i < rb.next; i++ {
+		resps = append(resps, rb.entries[(i+len(rb.entries))%len(rb.entries)])
+	}
+	return resps, true
+}
+
+// setResumeOptions enables the resume and replay for this subscription,
+// if requested.
+func (sInfo *subscribeInfo) setResumeOptions(req SubscribeRequest) {
+	if len(req.ResumeID) == 0 || req.ReplayBufSize <= 0 || req.ResumeTimeout <= 0 {
+		return
+	}
+	sInfo.resumeID = req.ResumeID
+	sInfo.resumeTimeout = req.ResumeTimeout
+	sInfo.replay = newReplayBuffer(req.ReplayBufSize)
+}
+
+// canResume checks if a Subscribe request can be resumed using this
+// subscribeInfo. Request paths and user should match.
+func (sInfo *subscribeInfo) canResume(req SubscribeRequest) bool {
+	return !sInfo.termDone && sInfo.replay != nil &&
+		sInfo.user == req.User.Name && reflect.DeepEqual(sInfo.paths, req.Paths)
+}
+
+// detach retains the subscription associated with a stop channel for
+// resume. DB subscriptions remain active and the notifications are saved
+// only in the replay buffer. Subscription is cleaned up if not resumed
+// within the resume timeout. Returns false if the subscription does not
+// support resume. Caller should hold sMutex.
+func detach(stop chan struct{}) bool {
+	sInfo, ok := stopMap[stop]
+	if !ok || sInfo.replay == nil || sInfo.termDone {
+		return false
+	}
+
+	log.Infof("[%v] detaching; resumeID=%s, lastSeq=%d, timeout=%v",
+		sInfo.id, sInfo.resumeID, sInfo.replay.lastSeq, sInfo.resumeTimeout)
+
+	delete(stopMap, stop)
+	sInfo.q = nil
+	sInfo.stop = nil
+
+	if old := resumeMap[sInfo.resumeID]; old != nil && old != sInfo {
+		log.Infof("[%v] replaced by %v for resume", old.id, sInfo.id)
+		old.resumeTimer.Stop()
+		cleanupSubscription(old)
+	}
+
+	resumeMap[sInfo.resumeID] = sInfo
+	sInfo.resumeTimer = time.AfterFunc(sInfo.resumeTimeout, func() {
+		sMutex.Lock()
+		defer sMutex.Unlock()
+		if resumeMap[sInfo.resumeID] == sInfo {
+			log.Infof("[%v] resume timer expired", sInfo.id)
+			delete(resumeMap, sInfo.resumeID)
+			cleanupSubscription(sInfo)
+		}
+	})
+
+	return true
+}
+
+// resumeSubscribe tries to resume a detached subscription for the request.
+// Sends the responses missed by the client and a sync message.
+// Returns false if there was no matching subscription or if the missed
+// responses are not available in its replay buffer; caller should perform
+// a full subscribe then.
+func resumeSubscribe(req SubscribeRequest) bool {
+	sMutex.Lock()
+	defer sMutex.Unlock()
+
+	sInfo := resumeMap[req.ResumeID]
+	if sInfo == nil {
+		log.V(1).Infof("No subscription to resume for id %s", req.ResumeID)
+		return false
+	}
+
+	delete(resumeMap, req.ResumeID)
+	sInfo.resumeTimer.Stop()
+
+	if !sInfo.canResume(req) {
+		log.Infof("[%v] cannot be resumed; request paths or user do not match", sInfo.id)
+		cleanupSubscription(sInfo)
+		return false
+	}
+
+	missed, ok := sInfo.replay.since(req.ResumeSeq)
+	if !ok {
+		log.Infof("[%v] cannot be resumed from seq %d; replay buffer has only %d..%d",
+			sInfo.id, req.ResumeSeq, sInfo.replay.lastSeq-uint64(sInfo.replay.count)+1, sInfo.replay.lastSeq)
+		cleanupSubscription(sInfo)
+		return false
+	}
+
+	log.Infof("[%v] resuming from seq %d; replaying %d responses", sInfo.id, req.ResumeSeq, len(missed))
+
+	sInfo.q = req.Q
+	sInfo.stop = req.Stop
+	sInfo.setSession(req.Session)
+	stopMap[sInfo.stop] = sInfo
+
+	for _, resp := range missed {
+		if err := sInfo.q.Put(resp); err != nil {
+			log.Warningf("[%v] Response queue error: %v", sInfo.id, err)
+		}
+	}
+
+	sendSyncNotification(sInfo, false)
+
+	go stophandler(sInfo.stop)
+	return true
+}
@@ -24,9 +24,12 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/Azure/sonic-mgmt-common/translib/db"
+	"github.com/Azure/sonic-mgmt-common/translib/internal/apis"
 	"github.com/Azure/sonic-mgmt-common/translib/ocbinds"
+	"github.com/Workiva/go-datastructures/queue"
 	"github.com/openconfig/ygot/ygot"
 )
 
@@ -151,6 +154,379 @@ func Benchmark_clearListKeys(b *testing.B) {
 	}
 }
 
+func Test_GetSubscriptions(t *testing.T) {
+	sInfo := &subscribeInfo{
+		id:      "test.0",
+		session: "test",
+		user:    "admin",
+		paths:   []string{"/openconfig-interfaces:interfaces"},
+		mode:    OnChange,
+		q:       queue.NewPriorityQueue(1, false),
+		sKeys:   []SubscribedDBKey{{DB: "CONFIG_DB", Table: "PORT", Key: "*"}},
+	}
+	addActiveSubscription(sInfo)
+	defer removeActiveSubscription(sInfo)
+
+	ne := notificationEvent{id: sInfo.id, sInfo: sInfo}
+	ne.send(&SubscribeResponse{Timestamp: 1000})
+	ne.send(&SubscribeResponse{Timestamp: 2000})
+
+	var info *SubscriptionInfo
+	for _, x := range GetSubscriptions() {
+		if x.ID == sInfo.id {
+			info = x
+		}
+	}
+	if info == nil {
+		t.Fatalf("GetSubscriptions did not return %s", sInfo.id)
+	}
+	if info.SessionID != "test" || info.User != "admin" || info.Mode != OnChange {
+		t.Errorf("Unexpected client info: %#v", info)
+	}
+	if !reflect.DeepEqual(info.Paths, sInfo.paths) || !reflect.DeepEqual(info.DBKeys, sInfo.sKeys) {
+		t.Errorf("Unexpected paths or db keys: %#v", info)
+	}
+	if info.NotificationCount != 2 || info.LastNotificationTime.UnixNano() != 2000 {
+		t.Errorf("Unexpected notification stats: count=%d, time=%v",
+			info.NotificationCount, info.LastNotificationTime)
+	}
+	if info.QueueDepth != 2 {
+		t.Errorf("Expected QueueDepth=2; found %d", info.QueueDepth)
+	}
+}
+
+func Test_GetSubscriptions_sample(t *testing.T) {
+	ss := NewSubscribeSession()
+	req := SubscribeRequest{
+		Paths:    []string{"/openconfig-interfaces:interfaces"},
+		Q:        queue.NewPriorityQueue(1, false),
+		Session:  ss,
+		Interval: 20,
+	}
+
+	// Stream is called for every interval
+	var dbs [db.MaxDB]*db.DB
+	sInfo := sampleSubscription(&req, subscribeContextId(ss), dbs, nil)
+	ne := notificationEvent{id: sInfo.id, sInfo: sInfo}
+	ne.send(&SubscribeResponse{Timestamp: 1000})
+	if x := sampleSubscription(&req, subscribeContextId(ss), dbs, nil); x != sInfo {
+		t.Fatalf("Sample subscription not retained across intervals")
+	}
+	ne.send(&SubscribeResponse{Timestamp: 2000})
+
+	// Requests differing in the namespace, queue or thresholds are not
+	// the same sample subscription.
+	others := []SubscribeRequest{req, req, req}
+	others[0].Namespace = "asic0"
+	others[1].Q = queue.NewPriorityQueue(1, false)
+	others[2].Thresholds = []*Threshold{{Leaf: "in-octets", Delta: 100}}
+	for i := range others {
+		r := &others[i]
+		x := sampleSubscription(r, fmt.Sprintf("%s-%d", sInfo.id, i), dbs, nil)
+		if x == sInfo || x.q != r.Q || x.namespace != r.Namespace {
+			t.Errorf("Sample subscription shared with %+v", *r)
+		}
+	}
+
+	var info *SubscriptionInfo
+	for _, x := range GetSubscriptions() {
+		if x.ID == sInfo.id {
+			info = x
+		}
+	}
+	if info == nil {
+		t.Fatalf("GetSubscriptions did not return %s", sInfo.id)
+	}
+	if info.Mode != Sample || info.Interval != 20 || info.NotificationCount != 2 {
+		t.Errorf("Unexpected sample subscription info: %#v", info)
+	}
+
+	ss.Close()
+	for _, x := range GetSubscriptions() {
+		if x.ID == sInfo.id {
+			t.Errorf("GetSubscriptions returned %s after session close", sInfo.id)
+		}
+	}
+}
+
+func Test_replayBuffer(t *testing.T) {
+	rb := newReplayBuffer(3)
+	if resps, ok := rb.since(0); !ok || len(resps) != 0 {
+		t.Fatalf("since(0) on empty buffer returned %d, %v", len(resps), ok)
+	}
+
+	for i := 0; i < 5; i++ {
+		rb.add(&SubscribeResponse{Timestamp: int64(i)})
+	}
+
+	verifySince := func(seq uint64, expOk bool, expSeqs ...uint64) {
+		t.Helper()
+		resps, ok := rb.since(seq)
+		if ok != expOk {
+			t.Fatalf("since(%d) returned ok=%v; expected %v", seq, ok, expOk)
+		}
+		var seqs []uint64
+		for _, r := range resps {
+			seqs = append(seqs, r.Sequence)
+		}
+		if !reflect.DeepEqual(seqs, expSeqs) {
+			t.Fatalf("since(%d) returned %v; expected %v", seq, seqs, expSeqs)
+		}
+	}
+
+	verifySince(1, false) // seq 2 was discarded
+	verifySince(2, true, 3, 4, 5)
+	verifySince(4, true, 5)
+	verifySince(5, true)
+	verifySince(6, false)
+}
+
+func Test_resumeSubscribe_NoMatch(t *testing.T) {
+	req := SubscribeRequest{ResumeID: "Test_resumeSubscribe_NoMatch"}
+	if resumeSubscribe(req) {
+		t.Fatalf("resumeSubscribe returned true for unknown id")
+	}
+}
+
+// Test_Subscribe_Resume subscribes to the PSU oper-status, detaches it by
+// closing the stop channel, and resumes it from a sequence number. Only
+// the notifications after that sequence number should be replayed.
+func Test_Subscribe_Resume(t *testing.T) {
+	if err := createPsuInfo(); err != nil {
+		t.Fatalf("createPsuInfo failed; err=%v", err)
+	}
+	defer clearPsuInfo()
+
+	d := getStateDB()
+	defer d.DeleteDB()
+	setStatus := func(status string) {
+		t.Helper()
+		err := d.ModEntry(&db.TableSpec{Name: PSU_INFO_TABLE}, asKey(TEST_PSU_NAME),
+			db.Value{Field: map[string]string{"status": status}})
+		if err != nil {
+			t.Fatalf("Failed to set PSU status; err=%v", err)
+		}
+	}
+	waitFor := func(desc string, cond func() bool) {
+		t.Helper()
+		for i := 0; !cond(); i++ {
+			if i == 50 {
+				t.Fatalf("Timed out waiting for %s", desc)
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	getResps := func(q *queue.PriorityQueue, n int) []*SubscribeResponse {
+		t.Helper()
+		waitFor(fmt.Sprintf("%d responses", n), func() bool { return q.Len() >= n })
+		items, _ := q.Get(q.Len())
+		var resps []*SubscribeResponse
+		for _, item := range items {
+			resps = append(resps, item.(*SubscribeResponse))
+		}
+		return resps
+	}
+	lastSeq := func() uint64 {
+		sMutex.Lock()
+		defer sMutex.Unlock()
+		if sInfo := resumeMap[t.Name()]; sInfo != nil {
+			return sInfo.replay.lastSeq
+		}
+		return 0
+	}
+
+	req := SubscribeRequest{
+		Paths:         []string{"/openconfig-platform:components/component[name=" + TEST_PSU_NAME + "]/state/oper-status"},
+		Q:             queue.NewPriorityQueue(1, false),
+		Stop:          make(chan struct{}),
+		ResumeID:      t.Name(),
+		ReplayBufSize: 10,
+		ResumeTimeout: time.Minute,
+	}
+	if err := Subscribe(req); err != nil {
+		t.Fatalf("Subscribe failed; err=%v", err)
+	}
+	resps := getResps(req.Q, 2) // initial update + sync
+	if resps[0].Sequence != 1 || !resps[len(resps)-1].SyncComplete {
+		t.Fatalf("Unexpected initial responses: %v", resps)
+	}
+
+	setStatus("false")
+	setStatus("true")
+	if resps = getResps(req.Q, 2); resps[len(resps)-1].Sequence != 3 {
+		t.Fatalf("Unexpected responses: %v", resps)
+	}
+
+	// Detach and miss 2 notifications
+	close(req.Stop)
+	waitFor("detach", func() bool { return lastSeq() == 3 })
+	setStatus("false")
+	setStatus("true")
+	waitFor("missed notifications", func() bool { return lastSeq() == 5 })
+
+	req.Q = queue.NewPriorityQueue(1, false)
+	req.Stop = make(chan struct{})
+	req.ResumeSeq = 3
+	if err := Subscribe(req); err != nil {
+		t.Fatalf("Subscribe to resume failed; err=%v", err)
+	}
+	defer func() {
+		close(req.Stop)
+		waitFor("detach", func() bool { return lastSeq() != 0 })
+		sMutex.Lock()
+		if sInfo := resumeMap[t.Name()]; sInfo != nil {
+			delete(resumeMap, t.Name())
+			sInfo.resumeTimer.Stop()
+			cleanupSubscription(sInfo)
+		}
+		sMutex.Unlock()
+	}()
+
+	resps = getResps(req.Q, 3)
+	var seqs []uint64
+	for _, r := range resps[:len(resps)-1] {
+		if r.Update == nil {
+			t.Errorf("Replayed response %d has no update", r.Sequence)
+		}
+		seqs = append(seqs, r.Sequence)
+	}
+	if !reflect.DeepEqual(seqs, []uint64{4, 5}) || !resps[len(resps)-1].SyncComplete {
+		t.Fatalf("Expected responses 4, 5 and sync; found %v", resps)
+	}
+
+	// Notifications continue after the resume
+	setStatus("false")
+	if resps = getResps(req.Q, 1); resps[0].Sequence != 6 {
+		t.Fatalf("Expected response 6 after resume; found %v", resps)
+	}
+}
+
+func Test_Threshold_isCrossed(t *testing.T) {
+	tests := []struct {
+		name     string
+		th       Threshold
+		old, new float64
+		exp      bool
+	}{
+		{"delta_below", Threshold{Delta: 10}, 100, 109, false},
+		{"delta_equal", Threshold{Delta: 10}, 100, 110, true},
+		{"delta_decrease", Threshold{Delta: 10}, 100, 80, true},
+		{"percent_below", Threshold{Percent: 5}, 100, 104, false},
+		{"percent_above", Threshold{Percent: 5}, 100, 106, true},
+		{"percent_from_zero", Threshold{Percent: 5}, 0, 1, true},
+		{"percent_no_change", Threshold{Percent: 5}, 0, 0, false},
+		{"watermark_up", Threshold{Watermarks: []float64{50}}, 49, 50, true},
+		{"watermark_down", Threshold{Watermarks: []float64{50}}, 50, 20, true},
+		{"watermark_none", Threshold{Watermarks: []float64{50}}, 51, 80, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if v := tc.th.isCrossed(tc.old, tc.new); v != tc.exp {
+				t.Errorf("isCrossed(%v, %v) = %v; expected %v", tc.old, tc.new, v, tc.exp)
+			}
+		})
+	}
+}
+
+func Test_thresholdFilter(t *testing.T) {
+	tf, err := newThresholdFilter([]*Threshold{{Leaf: "*-errors", Delta: 10}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nInfo := &notificationInfo{
+		fields: parseFieldsJSON(`{"": {"IN_ERR": "in-errors", "IN_OCT": "in-octets"}}`),
+	}
+	ne := &notificationEvent{id: t.Name(), key: db.NewKey("Ethernet0")}
+	update := func(oldErr, newErr string) []string {
+		diff := &apis.EntryDiff{
+			OldValue:      db.Value{Field: map[string]string{"IN_ERR": oldErr, "IN_OCT": "1"}},
+			NewValue:      db.Value{Field: map[string]string{"IN_ERR": newErr, "IN_OCT": "2"}},
+			UpdatedFields: []string{"IN_ERR", "IN_OCT"},
+		}
+		return tf.filterUpdatedFields(ne, nInfo, diff)
+	}
+
+	if f := update("0", "5"); !reflect.DeepEqual(f, []string{"IN_OCT"}) {
+		t.Fatalf("Expected only IN_OCT for 0->5; found %v", f)
+	}
+	if f := update("5", "9"); !reflect.DeepEqual(f, []string{"IN_OCT"}) {
+		t.Fatalf("Expected only IN_OCT for 5->9; found %v", f)
+	}
+	if f := update("9", "12"); !reflect.DeepEqual(f, []string{"IN_ERR", "IN_OCT"}) {
+		t.Fatalf("Expected IN_ERR and IN_OCT for 9->12; found %v", f)
+	}
+	if f := update("12", "15"); !reflect.DeepEqual(f, []string{"IN_OCT"}) {
+		t.Fatalf("Expected only IN_OCT for 12->15; found %v", f)
+	}
+
+	// Deleted entries are forgotten
+	tf.forget(nInfo, ne.key)
+	if len(tf.lastValues) != 0 {
+		t.Fatalf("lastValues not removed on delete: %v", tf.lastValues)
+	}
+
+	if _, err := newThresholdFilter([]*Threshold{{Leaf: "[", Delta: 1}}); err == nil {
+		t.Fatalf("newThresholdFilter accepted invalid pattern")
+	}
+}
+
+// Test_thresholdFilter_sample verifies the thresholds on the interface
+// counters, which are sample subscriptions without db field mappings.
+func Test_thresholdFilter_sample(t *testing.T) {
+	tf, err := newThresholdFilter([]*Threshold{{Leaf: "in-errors", Delta: 10}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sInfo := &subscribeInfo{
+		id:         t.Name(),
+		mode:       Sample,
+		q:          queue.NewPriorityQueue(1, false),
+		thresholds: tf,
+	}
+	ne := notificationEvent{id: sInfo.id, sInfo: sInfo}
+
+	// sample sends the counters of the interfaces, as a Stream interval
+	sample := func(inErrors map[string]uint64) map[string]*uint64 {
+		t.Helper()
+		tf.beginSample()
+		defer tf.endSample()
+		sent := make(map[string]*uint64)
+		for name, n := range inErrors {
+			counters := &ocbinds.OpenconfigInterfaces_Interfaces_Interface_State_Counters{
+				InErrors: ygot.Uint64(n),
+				InOctets: ygot.Uint64(n * 100),
+			}
+			ne.send(&SubscribeResponse{
+				Path:   "/openconfig-interfaces:interfaces/interface[name=" + name + "]/state/counters",
+				Update: counters,
+			})
+			if counters.InOctets == nil {
+				t.Fatalf("Leaf without threshold was removed")
+			}
+			sent[name] = counters.InErrors
+		}
+		return sent
+	}
+
+	if sent := sample(map[string]uint64{"Ethernet0": 0, "Ethernet4": 0}); sent["Ethernet0"] == nil || sent["Ethernet4"] == nil {
+		t.Fatalf("First sample should send all the counters")
+	}
+	if sent := sample(map[string]uint64{"Ethernet0": 5, "Ethernet4": 20}); sent["Ethernet0"] != nil || sent["Ethernet4"] == nil {
+		t.Fatalf("Expected only Ethernet4 in-errors for 0->5, 0->20; found %v", sent)
+	}
+	if sent := sample(map[string]uint64{"Ethernet0": 10}); sent["Ethernet0"] == nil {
+		t.Fatalf("Expected Ethernet0 in-errors for 0->10")
+	}
+	if len(tf.lastValues) != 1 {
+		t.Fatalf("Deleted interface not forgotten: %v", tf.lastValues)
+	}
+
+	tf.reset()
+	if len(tf.lastValues) != 0 {
+		t.Fatalf("lastValues not removed on reset: %v", tf.lastValues)
+	}
+}
+
 ///////////////////
 
 // Messages is a utility to collect list of
@@ -0,0 +1,290 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package translib
+
+import (
+	"fmt"
+	"math"
+	pathpkg "path"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/sonic-mgmt-common/translib/db"
+	"github.com/Azure/sonic-mgmt-common/translib/internal/apis"
+	"github.com/Azure/sonic-mgmt-common/translib/tlerr"
+	log "github.com/golang/glog"
+)
+
+// Threshold is a subscription option to suppress notifications for small
+// changes in numeric leaf values, like counters. A modified leaf matching
+// the threshold is notified only when its value has changed by at least
+// Delta or Percent since the last notification, or if it has crossed one
+// of the Watermarks. Zero values disable the respective checks.
+//
+// For on_change subscriptions, the leaves are matched through the db field
+// mappings of the path. For sample subscriptions (like interface counters,
+// which have no such mappings), the numeric leaves of each sample update
+// are matched; the ones within their thresholds are removed from the
+// update.
+type Threshold struct {
+	Leaf       string    // Leaf name or glob pattern of leaf names (like "*-errors")
+	Delta      float64   // Minimum absolute change
+	Percent    float64   // Minimum change, as a percentage of last notified value
+	Watermarks []float64 // Notify when value crosses any of these levels
+}
+
+// thresholdFilter evaluates the Thresholds of a subscription. It remembers
+// the last notified value of the db fields matching the thresholds.
+// Guarded by sMutex.
+type thresholdFilter struct {
+	thresholds []*Threshold
+	lastValues map[thresholdKey]float64
+	sampleSeen map[thresholdKey]bool // Sample leaves seen in current interval
+}
+
+// thresholdKey identifies a db field of an on_change subscription, or a
+// leaf path of a sample subscription (with nil nInfo and empty field).
+type thresholdKey struct {
+	nInfo *notificationInfo
+	key   string // db key, or leaf path
+	field string // db field name
+}
+
+// newThresholdFilter creates a thresholdFilter for the Thresholds.
+// Returns nil if there are no thresholds to evaluate.
+func newThresholdFilter(thresholds []*Threshold) (*thresholdFilter, error) {
+	var tf *thresholdFilter
+	for _, t := range thresholds {
+		if t == nil || (t.Delta <= 0 && t.Percent <= 0 && len(t.Watermarks) == 0) {
+			continue
+		}
+		if _, err := pathpkg.Match(t.Leaf, ""); err != nil || len(t.Leaf) == 0 {
+			return nil, tlerr.InvalidArgs("Invalid threshold leaf pattern \"%s\"", t.Leaf)
+		}
+		if tf == nil {
+			tf = &thresholdFilter{lastValues: make(map[thresholdKey]float64)}
+		}
+		tf.thresholds = append(tf.thresholds, t)
+	}
+	return tf, nil
+}
+
+// match returns the Threshold matching the yang leaf name
+func (tf *thresholdFilter) match(leaf string) *Threshold {
+	for _, t := range tf.thresholds {
+		if ok, _ := pathpkg.Match(t.Leaf, leaf); ok {
+			return t
+		}
+	}
+	return nil
+}
+
+// find returns the Threshold matching the yang leaf of a db field.
+// Returns nil if no threshold is configured for the field.
+func (tf *thresholdFilter) find(nInfo *notificationInfo, field string) *Threshold {
+	for _, nDbFldInfo := range nInfo.fields {
+		leaf, ok := nDbFldInfo.dbFldYgPathMap[field]
+		if !ok {
+			continue
+		}
+		for _, s := range strings.Split(leaf, ",") {
+			if t := tf.match(pathpkg.Base(strings.TrimSpace(s))); t != nil {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// filterUpdatedFields returns the subset of entryDiff.UpdatedFields which
+// satisfy their thresholds. Fields without thresholds are always included.
+func (tf *thresholdFilter) filterUpdatedFields(ne *notificationEvent, nInfo *notificationInfo, entryDiff *apis.EntryDiff) []string {
+	var fields []string
+	for _, f := range entryDiff.UpdatedFields {
+		t := tf.find(nInfo, f)
+		if t == nil {
+			fields = append(fields, f)
+			continue
+		}
+
+		newVal, err := strconv.ParseFloat(entryDiff.NewValue.Get(f), 64)
+		if err != nil {
+			fields = append(fields, f) // not a number
+			continue
+		}
+
+		tk := thresholdKey{nInfo: nInfo, key: strings.Join(ne.key.Comp, "|"), field: f}
+		oldVal, ok := tf.lastValues[tk]
+		if !ok {
+			// Use cached value as the baseline; it would have been
+			// notified during initial sync or the last update.
+			if oldVal, err = strconv.ParseFloat(entryDiff.OldValue.Get(f), 64); err != nil {
+				oldVal = newVal
+			}
+		}
+
+		if t.isCrossed(oldVal, newVal) {
+			log.V(2).Infof("[%s] field %s changed from %v to %v; threshold crossed", ne.id, f, oldVal, newVal)
+			tf.lastValues[tk] = newVal
+			fields = append(fields, f)
+		} else {
+			log.V(2).Infof("[%s] field %s changed from %v to %v; within threshold", ne.id, f, oldVal, newVal)
+			tf.lastValues[tk] = oldVal
+		}
+	}
+	return fields
+}
+
+// forget removes the last notified values of the db key of an on_change
+// subscription; all the fields, if none are specified. Called when the key
+// or its fields are deleted.
+func (tf *thresholdFilter) forget(nInfo *notificationInfo, key *db.Key, fields ...string) {
+	k := strings.Join(key.Comp, "|")
+	if len(fields) != 0 {
+		for _, f := range fields {
+			delete(tf.lastValues, thresholdKey{nInfo: nInfo, key: k, field: f})
+		}
+		return
+	}
+	for tk := range tf.lastValues {
+		if tk.nInfo == nInfo && tk.key == k {
+			delete(tf.lastValues, tk)
+		}
+	}
+}
+
+// reset removes all the last notified values. Called when the subscription
+// ends.
+func (tf *thresholdFilter) reset() {
+	if tf != nil {
+		tf.lastValues = make(map[thresholdKey]float64)
+		tf.sampleSeen = nil
+	}
+}
+
+// beginSample starts a sample interval. Should be called before the
+// filterSampleUpdate calls of the interval.
+func (tf *thresholdFilter) beginSample() {
+	tf.sampleSeen = make(map[thresholdKey]bool)
+}
+
+// endSample ends a sample interval. The leaves not seen in the interval
+// (Eg: of deleted interfaces) are forgotten.
+func (tf *thresholdFilter) endSample() {
+	for tk := range tf.lastValues {
+		if tk.nInfo == nil && !tf.sampleSeen[tk] {
+			delete(tf.lastValues, tk)
+		}
+	}
+	tf.sampleSeen = nil
+}
+
+// filterSampleUpdate removes the numeric leaves of a sample update which
+// changed by less than their thresholds since they were last sent.
+func (tf *thresholdFilter) filterSampleUpdate(resp *SubscribeResponse) {
+	if resp.Update != nil {
+		tf.filterStruct(reflect.ValueOf(resp.Update), resp.Path)
+	}
+}
+
+// filterStruct walks a ygot struct pointer, and filters its leaves.
+// Leaf paths are derived from the ygot "path" tags.
+func (tf *thresholdFilter) filterStruct(v reflect.Value, prefix string) {
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return
+	}
+	v = v.Elem()
+	for i := 0; i < v.NumField(); i++ {
+		fv := v.Field(i)
+		name := strings.Split(v.Type().Field(i).Tag.Get("path"), "|")[0]
+		if len(name) == 0 {
+			continue
+		}
+		p := prefix + "/" + name
+
+		switch fv.Kind() {
+		case reflect.Ptr:
+			if fv.IsNil() {
+				continue
+			}
+			if fv.Elem().Kind() == reflect.Struct {
+				tf.filterStruct(fv, p)
+			} else if !tf.keepSampleLeaf(p, pathpkg.Base(name), fv.Elem()) {
+				fv.Set(reflect.Zero(fv.Type()))
+			}
+		case reflect.Map:
+			for _, k := range fv.MapKeys() {
+				tf.filterStruct(fv.MapIndex(k), fmt.Sprintf("%s[%v]", p, k.Interface()))
+			}
+		}
+	}
+}
+
+// keepSampleLeaf checks if a sample leaf value should be sent. Non numeric
+// leaves, and the leaves without thresholds are always sent.
+func (tf *thresholdFilter) keepSampleLeaf(leafPath, leaf string, v reflect.Value) bool {
+	var newVal float64
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		newVal = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		newVal = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		newVal = v.Float()
+	default:
+		return true
+	}
+
+	t := tf.match(leaf)
+	if t == nil {
+		return true
+	}
+
+	tk := thresholdKey{key: leafPath}
+	if tf.sampleSeen != nil {
+		tf.sampleSeen[tk] = true
+	}
+	oldVal, ok := tf.lastValues[tk]
+	if ok && !t.isCrossed(oldVal, newVal) {
+		log.V(2).Infof("leaf %s changed from %v to %v; within threshold", leafPath, oldVal, newVal)
+		return false
+	}
+	tf.lastValues[tk] = newVal
+	return true
+}
+
+// isCrossed checks if the change from oldVal to newVal is significant
+// as per this Threshold.
+func (t *Threshold) isCrossed(oldVal, newVal float64) bool {
+	diff := math.Abs(newVal - oldVal)
+	if t.Delta > 0 && diff >= t.Delta {
+		return true
+	}
+	if t.Percent > 0 && (oldVal == 0 || diff*100/math.Abs(oldVal) >= t.Percent) {
+		return oldVal != newVal
+	}
+	for _, w := range t.Watermarks {
+		if (oldVal < w && newVal >= w) || (oldVal >= w && newVal < w) {
+			return true
+		}
+	}
+	return false
+}
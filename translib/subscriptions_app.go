@@ -0,0 +1,174 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package translib
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/Azure/sonic-mgmt-common/translib/db"
+	"github.com/Azure/sonic-mgmt-common/translib/ocbinds"
+	"github.com/Azure/sonic-mgmt-common/translib/tlerr"
+
+	"github.com/golang/glog"
+	"github.com/openconfig/ygot/ygot"
+)
+
+// subscriptionsApp implements app interface for the
+// sonic-subscriptions module. Data is served from GetSubscriptions API.
+type subscriptionsApp struct {
+	pathInfo *PathInfo
+	ygotRoot *ocbinds.Device
+}
+
+func init() {
+	err := register("/sonic-subscriptions:subscriptions",
+		&appInfo{
+			appType:      reflect.TypeOf(subscriptionsApp{}),
+			ygotRootType: reflect.TypeOf(ocbinds.SonicSubscriptions_Subscriptions{}),
+			isNative:     false,
+		})
+	if err != nil {
+		glog.Fatal("register() failed for subscriptionsApp;", err)
+	}
+
+	err = addModel(&ModelData{
+		Name: "sonic-subscriptions",
+		Org:  "SONiC",
+		Ver:  "2026-10-19",
+	})
+	if err != nil {
+		glog.Fatal("addModel() failed for subscriptionsApp;", err)
+	}
+}
+
+func (app *subscriptionsApp) initialize(data appData) {
+	app.pathInfo = NewPathInfo(data.path)
+	app.ygotRoot = (*data.ygotRoot).(*ocbinds.Device)
+}
+
+func (app *subscriptionsApp) translateCreate(d *db.DB) ([]db.WatchKeys, error) {
+	return nil, tlerr.NotSupported("Unsupported")
+}
+
+func (app *subscriptionsApp) translateUpdate(d *db.DB) ([]db.WatchKeys, error) {
+	return nil, tlerr.NotSupported("Unsupported")
+}
+
+func (app *subscriptionsApp) translateReplace(d *db.DB) ([]db.WatchKeys, error) {
+	return nil, tlerr.NotSupported("Unsupported")
+}
+
+func (app *subscriptionsApp) translateDelete(d *db.DB) ([]db.WatchKeys, error) {
+	return nil, tlerr.NotSupported("Unsupported")
+}
+
+func (app *subscriptionsApp) translateGet(dbs [db.MaxDB]*db.DB) error {
+	return nil // NOOP! everyting is in processGet
+}
+
+func (app *subscriptionsApp) translateAction(dbs [db.MaxDB]*db.DB) error {
+	return tlerr.NotSupported("Unsupported")
+}
+
+func (app *subscriptionsApp) translateSubscribe(req translateSubRequest) (translateSubResponse, error) {
+	return emptySubscribeResponse(req.path)
+}
+
+func (app *subscriptionsApp) processSubscribe(req processSubRequest) (processSubResponse, error) {
+	return processSubResponse{}, tlerr.New("not implemented")
+}
+
+func (app *subscriptionsApp) processCreate(d *db.DB) (SetResponse, error) {
+	return SetResponse{}, tlerr.NotSupported("Unsupported")
+}
+
+func (app *subscriptionsApp) processUpdate(d *db.DB) (SetResponse, error) {
+	return SetResponse{}, tlerr.NotSupported("Unsupported")
+}
+
+func (app *subscriptionsApp) processReplace(d *db.DB) (SetResponse, error) {
+	return SetResponse{}, tlerr.NotSupported("Unsupported")
+}
+
+func (app *subscriptionsApp) processDelete(d *db.DB) (SetResponse, error) {
+	return SetResponse{}, tlerr.NotSupported("Unsupported")
+}
+
+func (app *subscriptionsApp) processAction(dbs [db.MaxDB]*db.DB) (ActionResponse, error) {
+	return ActionResponse{}, tlerr.NotSupported("Unsupported")
+}
+
+func (app *subscriptionsApp) processGet(dbs [db.MaxDB]*db.DB, fmtType TranslibFmtType) (GetResponse, error) {
+	var resp GetResponse
+	id := app.pathInfo.Var("id")
+	subs := &ocbinds.SonicSubscriptions_Subscriptions{}
+
+	for _, info := range GetSubscriptions() {
+		if len(id) != 0 && info.ID != id {
+			continue
+		}
+		if err := fillSubscriptionYgot(subs, info); err != nil {
+			return resp, err
+		}
+	}
+
+	if len(id) != 0 && len(subs.Subscription) == 0 {
+		return resp, tlerr.NotFound("Subscription %s not found", id)
+	}
+
+	app.ygotRoot.Subscriptions = subs
+	var root ygot.GoStruct = app.ygotRoot
+	return generateGetResponse(app.pathInfo.Path, &root, fmtType)
+}
+
+// fillSubscriptionYgot adds a SubscriptionInfo to the ygot subscriptions container.
+func fillSubscriptionYgot(subs *ocbinds.SonicSubscriptions_Subscriptions, info *SubscriptionInfo) error {
+	s, err := subs.NewSubscription(info.ID)
+	if err != nil {
+		return err
+	}
+
+	s.Path = info.Paths
+	s.NotificationCount = ygot.Uint64(info.NotificationCount)
+	s.QueueDepth = ygot.Uint32(uint32(info.QueueDepth))
+	if len(info.SessionID) != 0 {
+		s.SessionId = ygot.String(info.SessionID)
+	}
+	if len(info.User) != 0 {
+		s.User = ygot.String(info.User)
+	}
+	if info.Mode != TargetDefined {
+		s.Mode = ygot.String(info.Mode.String())
+	}
+	if info.Interval > 0 {
+		s.Interval = ygot.Uint32(uint32(info.Interval))
+	}
+	if !info.LastNotificationTime.IsZero() {
+		s.LastNotificationTime = ygot.String(info.LastNotificationTime.UTC().Format(time.RFC3339Nano))
+	}
+	for _, k := range info.DBKeys {
+		if _, err = s.NewDbKey(k.DB, k.Table, k.Key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -84,6 +84,16 @@ func (e TranslibTransactionFail) Error() string {
 	return p.Sprintf("Translib Redis Error: Transaction Fails")
 }
 
+// TranslibDBCondFail indicates the condition of a conditional write
+// (Eg: SetEntryIf) was not satisfied by the DB entry.
+type TranslibDBCondFail struct {
+	Entry string
+}
+
+func (e TranslibDBCondFail) Error() string {
+	return p.Sprintf("Translib Redis Error: Condition not satisfied: %s", e.Entry)
+}
+
 type TranslibDBSubscribeFail struct {
 }
 
@@ -66,6 +66,8 @@ func getDBOptionsWithSeparator(dbNo db.DBNum, initIndicator string, tableSeparat
 	})
 }
 
+// TestMain runs against the redis-server; set TRANSLIB_DB_BACKEND=memory
+// (db.BackendEnv) to run hermetically on the in-memory DB backend.
 func TestMain(t *testing.M) {
 	fmt.Println("----- Setting up transformer tests -----")
 	if err := setup(); err != nil {
@@ -249,6 +251,7 @@ func getDbClient(dbNum int) *redis.Client {
 		Password:    pass,
 		DB:          dbNum,
 		DialTimeout: 0,
+		Dialer:      db.GetBackend().Dialer(),
 	})
 	_, err := rclient.Ping().Result()
 	if err != nil {
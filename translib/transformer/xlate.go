@@ -112,92 +112,239 @@ func TraverseDb(dbs [db.MaxDB]*db.DB, spec KeySpec, result *map[db.DBNum]map[str
 	return nil
 }
 
+// traverseDbHelper reads the data of the KeySpec tree into the result. The
+// tree is traversed level by level; the entries with specific keys of a
+// level, across all its tables, are read with a single redis pipeline per
+// DB, and each table with a pattern is read once.
 func traverseDbHelper(dbs [db.MaxDB]*db.DB, spec *KeySpec, result *map[db.DBNum]map[string]map[string]db.Value,
 	parentKey *db.Key, dbTblKeyGetCache map[db.DBNum]map[string]map[string]bool, reqCtxt context.Context) error {
-	var err error
-	if isReqContextCancelled(reqCtxt) {
-		err = tlerr.RequestContextCancelled("Client request's context cancelled.", reqCtxt.Err())
-		log.Warningf(err.Error())
-		return err
+	root := &dbTraverseNode{spec: spec, parentKey: parentKey}
+	reads := dbTraverseReads{
+		entries: make(map[db.DBNum]map[string]map[string]dbTraverseEntry),
+		tables:  make(map[db.DBNum]map[string]*dbTraverseTable),
+	}
+
+	for level := []*dbTraverseNode{root}; len(level) > 0; {
+		if isReqContextCancelled(reqCtxt) {
+			err := tlerr.RequestContextCancelled("Client request's context cancelled.", reqCtxt.Err())
+			log.Warningf(err.Error())
+			return err
+		}
+
+		reads.read(dbs, level)
+
+		var next []*dbTraverseNode
+		for _, node := range level {
+			next = append(next, node.visit(&reads, result, dbTblKeyGetCache)...)
+		}
+		level = next
+	}
+
+	return root.traverseErr()
+}
+
+// dbTraverseNode is a KeySpec visit of the traverseDbHelper
+type dbTraverseNode struct {
+	spec      *KeySpec
+	parentKey *db.Key
+	key       db.Key // spec.Key; with "*" appended for a pattern
+	isPattern bool
+	err       error // read error of the spec table
+	children  []*dbTraverseNode
+}
+
+// dbTraverseEntry is an entry read by the traverseDbHelper
+type dbTraverseEntry struct {
+	value db.Value
+	err   error
+}
+
+// dbTraverseTable is a table read by the traverseDbHelper
+type dbTraverseTable struct {
+	tbl  db.Table
+	keys []db.Key
+	err  error
+}
+
+// dbTraverseReads has the entries and tables read by the traverseDbHelper
+type dbTraverseReads struct {
+	entries map[db.DBNum]map[string]map[string]dbTraverseEntry // table -> key -> entry
+	tables  map[db.DBNum]map[string]*dbTraverseTable
+}
+
+func (n *dbTraverseNode) isSpecificKey() bool {
+	return n.spec.Key.Len() > 0 && !n.spec.IsPartialKey
+}
+
+func (n *dbTraverseNode) dbKeyStr() string {
+	return strings.Join(n.spec.Key.Comp, getDBOptions(n.spec.DbNum).KeySeparator)
+}
+
+// read reads the entries, and the tables, needed by the nodes of a level
+// that are not already read.
+func (r *dbTraverseReads) read(dbs [db.MaxDB]*db.DB, level []*dbTraverseNode) {
+	tblKeys := make(map[db.DBNum][]db.TableKeys)
+	tblKeyStrs := make(map[db.DBNum][][]string)
+	tblIdx := make(map[db.DBNum]map[string]int)
+
+	for _, n := range level {
+		spec := n.spec
+		if spec.Ts.Name == XFMR_NONE_STRING {
+			continue
+		}
+
+		if !n.isSpecificKey() {
+			if _, ok := r.tables[spec.DbNum][spec.Ts.Name]; ok {
+				continue
+			}
+			t := &dbTraverseTable{}
+			t.tbl, t.err = dbs[spec.DbNum].GetTablePattern(&spec.Ts, *db.NewKey("*"))
+			if t.err == nil {
+				t.keys, t.err = t.tbl.GetKeys()
+			}
+			if r.tables[spec.DbNum] == nil {
+				r.tables[spec.DbNum] = make(map[string]*dbTraverseTable)
+			}
+			r.tables[spec.DbNum][spec.Ts.Name] = t
+			continue
+		}
+
+		dbKeyStr := n.dbKeyStr()
+		if _, ok := r.entries[spec.DbNum][spec.Ts.Name][dbKeyStr]; ok {
+			continue
+		}
+		if tblIdx[spec.DbNum] == nil {
+			tblIdx[spec.DbNum] = make(map[string]int)
+		}
+		i, ok := tblIdx[spec.DbNum][spec.Ts.Name]
+		if !ok {
+			i = len(tblKeys[spec.DbNum])
+			tblIdx[spec.DbNum][spec.Ts.Name] = i
+			tblKeys[spec.DbNum] = append(tblKeys[spec.DbNum], db.TableKeys{Ts: &spec.Ts})
+			tblKeyStrs[spec.DbNum] = append(tblKeyStrs[spec.DbNum], nil)
+		}
+		tblKeys[spec.DbNum][i].Keys = append(tblKeys[spec.DbNum][i].Keys, spec.Key)
+		tblKeyStrs[spec.DbNum][i] = append(tblKeyStrs[spec.DbNum][i], dbKeyStr)
+		r.setEntry(spec.DbNum, spec.Ts.Name, dbKeyStr, dbTraverseEntry{})
+	}
+
+	for dbNum, tks := range tblKeys {
+		xfmrLogDebug("Reading %v tables of DB %v in traverseDbHelper", len(tks), dbNum)
+		// A pipeline error (Eg: a key which is not a hash) is the error of
+		// all the entries of the level in the DB; see GetMultiTableEntries.
+		values, errs := dbs[dbNum].GetMultiTableEntries(tks)
+		for i, tk := range tks {
+			for j, dbKeyStr := range tblKeyStrs[dbNum][i] {
+				e := dbTraverseEntry{value: values[i][j]}
+				if errs[i] != nil {
+					e.err = errs[i][j]
+				}
+				r.setEntry(dbNum, tk.Ts.Name, dbKeyStr, e)
+			}
+		}
 	}
+}
 
-	var dbOpts db.Options = getDBOptions(spec.DbNum)
+func (r *dbTraverseReads) setEntry(dbNum db.DBNum, tblName, dbKeyStr string, e dbTraverseEntry) {
+	if r.entries[dbNum] == nil {
+		r.entries[dbNum] = make(map[string]map[string]dbTraverseEntry)
+	}
+	if r.entries[dbNum][tblName] == nil {
+		r.entries[dbNum][tblName] = make(map[string]dbTraverseEntry)
+	}
+	r.entries[dbNum][tblName][dbKeyStr] = e
+}
 
-	separator := dbOpts.KeySeparator
+// visit updates the result with the data of the node, and returns the
+// child nodes to be visited.
+func (n *dbTraverseNode) visit(reads *dbTraverseReads, result *map[db.DBNum]map[string]map[string]db.Value,
+	dbTblKeyGetCache map[db.DBNum]map[string]map[string]bool) []*dbTraverseNode {
+	spec := n.spec
+	separator := getDBOptions(spec.DbNum).KeySeparator
 
-	if spec.Key.Len() > 0 && !spec.IsPartialKey {
+	if n.isSpecificKey() {
+		n.key = spec.Key
 		// get an entry with a specific key
 		if spec.Ts.Name != XFMR_NONE_STRING { // Do not traverse for NONE table
-			data, err := dbs[spec.DbNum].GetEntry(&spec.Ts, spec.Key)
-			dbKeyStr := strings.Join(spec.Key.Comp, separator)
-			if err != nil {
+			dbKeyStr := n.dbKeyStr()
+			e := reads.entries[spec.DbNum][spec.Ts.Name][dbKeyStr]
+			data := e.value
+			if e.err != nil {
 				updateDbDataMapAndKeyCache(dbKeyStr, &data, spec, result, dbTblKeyGetCache, false)
 				if log.V(5) {
 					log.Warningf("Didn't get data for tbl(%v), key(%v) in traverseDbHelper", spec.Ts.Name, spec.Key)
 				}
-				return err
+				n.err = e.err
+				return nil
 			}
 			updateDbDataMapAndKeyCache(dbKeyStr, &data, spec, result, dbTblKeyGetCache, true)
 		}
-		if len(spec.Child) > 0 {
-			for _, ch := range spec.Child {
-				if err = traverseDbHelper(dbs, &ch, result, &spec.Key, dbTblKeyGetCache, reqCtxt); err != nil &&
-					isReqContextCancelledError(err) {
-					break
-				}
+		return n.addChildren(&n.key)
+	}
+
+	n.isPattern = true
+	n.key.Comp = append(append([]string{}, spec.Key.Comp...), "*")
+	// TODO - GetEntry support with regex patten, 'abc*' for optimization
+	if spec.Ts.Name == XFMR_NONE_STRING { //Do not traverse for NONE table
+		return n.addChildren(&n.key)
+	}
+
+	t := reads.tables[spec.DbNum][spec.Ts.Name]
+	if t.err != nil {
+		log.Warningf("GetTablePattern returned error %v for tbl(%v) in traverseDbHelper", t.err, spec.Ts.Name)
+		n.err = t.err
+		return nil
+	}
+	xfmrLogDebug("keys for table %v in DB %v are %v", spec.Ts.Name, spec.DbNum, t.keys)
+	parentDbKeyStr := ""
+	if n.parentKey != nil && !spec.IgnoreParentKey {
+		parentDbKeyStr = strings.Join(n.parentKey.Comp, separator)
+	}
+
+	var children []*dbTraverseNode
+	for i := range t.keys {
+		dbKey := &t.keys[i]
+		dbKeyStr := strings.Join(dbKey.Comp, separator)
+		if len(parentDbKeyStr) > 0 {
+			// TODO - multi-depth with a custom delimiter
+			if !strings.Contains(dbKeyStr, parentDbKeyStr) {
+				continue
 			}
 		}
-	} else {
-		spec.Key.Comp = append(spec.Key.Comp, "*")
-		// TODO - GetEntry support with regex patten, 'abc*' for optimization
-		if spec.Ts.Name != XFMR_NONE_STRING { //Do not traverse for NONE table
-			tblObj, err := dbs[spec.DbNum].GetTablePattern(&spec.Ts, *db.NewKey("*"))
-			if err != nil {
-				log.Warningf("GetTablePattern returned error %v for tbl(%v) in traverseDbHelper", err, spec.Ts.Name)
-				return err
-			}
-			dbKeys, err := tblObj.GetKeys()
-			if err != nil {
-				log.Warningf("Table.GetKeys returned error %v for tbl(%v) in traverseDbHelper", err, spec.Ts.Name)
-				return err
-			}
-			xfmrLogDebug("keys for table %v in DB %v are %v", spec.Ts.Name, spec.DbNum, dbKeys)
-			parentDbKeyStr := ""
-			if parentKey != nil && !spec.IgnoreParentKey {
-				parentDbKeyStr = strings.Join((*parentKey).Comp, separator)
-			}
-			for _, dbKey := range dbKeys {
-				dbKeyStr := strings.Join(dbKey.Comp, separator)
-				if len(parentDbKeyStr) > 0 {
-					// TODO - multi-depth with a custom delimiter
-					if !strings.Contains(dbKeyStr, parentDbKeyStr) {
-						continue
-					}
-				}
-				data, err := tblObj.GetEntry(dbKey)
-				if err != nil {
-					log.Warningf("Table.GetEntry returned error %v for tbl(%v), and the key %v in traverseDbHelper", err, spec.Ts.Name, dbKey)
-					updateDbDataMapAndKeyCache(dbKeyStr, &data, spec, result, dbTblKeyGetCache, false)
-				} else if data.IsPopulated() {
-					updateDbDataMapAndKeyCache(dbKeyStr, &data, spec, result, dbTblKeyGetCache, true)
-				}
-				if len(spec.Child) > 0 {
-					for _, ch := range spec.Child {
-						if err = traverseDbHelper(dbs, &ch, result, &dbKey, dbTblKeyGetCache, reqCtxt); err != nil &&
-							isReqContextCancelledError(err) {
-							break
-						}
-					}
-				}
-			}
-		} else if len(spec.Child) > 0 {
-			for _, ch := range spec.Child {
-				err = traverseDbHelper(dbs, &ch, result, &spec.Key, dbTblKeyGetCache, reqCtxt)
-				if err != nil && isReqContextCancelledError(err) {
-					break
-				}
-			}
+		data, err := t.tbl.GetEntry(*dbKey)
+		if err != nil {
+			log.Warningf("Table.GetEntry returned error %v for tbl(%v), and the key %v in traverseDbHelper", err, spec.Ts.Name, dbKey)
+			updateDbDataMapAndKeyCache(dbKeyStr, &data, spec, result, dbTblKeyGetCache, false)
+		} else if data.IsPopulated() {
+			updateDbDataMapAndKeyCache(dbKeyStr, &data, spec, result, dbTblKeyGetCache, true)
 		}
+		children = append(children, n.addChildren(dbKey)...)
+	}
+	return children
+}
+
+func (n *dbTraverseNode) addChildren(parentKey *db.Key) []*dbTraverseNode {
+	children := make([]*dbTraverseNode, len(n.spec.Child))
+	for i := range n.spec.Child {
+		children[i] = &dbTraverseNode{spec: &n.spec.Child[i], parentKey: parentKey}
+	}
+	n.children = append(n.children, children...)
+	return children
+}
+
+// traverseErr returns the error of the node; the read error of its table,
+// else the error of its last child. The child errors of a table pattern
+// are ignored.
+func (n *dbTraverseNode) traverseErr() error {
+	if n.err != nil {
+		return n.err
+	}
+	if n.isPattern && n.spec.Ts.Name != XFMR_NONE_STRING {
+		return nil
+	}
+	var err error
+	for _, ch := range n.children {
+		err = ch.traverseErr()
 	}
 	return err
 }
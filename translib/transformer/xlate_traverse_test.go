@@ -0,0 +1,200 @@
+////////////////////////////////////////////////////////////////////////////////
+//                                                                            //
+//  Copyright 2026 Broadcom. The term Broadcom refers to Broadcom Inc. and/or //
+//  its subsidiaries.                                                         //
+//                                                                            //
+//  Licensed under the Apache License, Version 2.0 (the "License");           //
+//  you may not use this file except in compliance with the License.          //
+//  You may obtain a copy of the License at                                   //
+//                                                                            //
+//     http://www.apache.org/licenses/LICENSE-2.0                             //
+//                                                                            //
+//  Unless required by applicable law or agreed to in writing, software       //
+//  distributed under the License is distributed on an "AS IS" BASIS,         //
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  //
+//  See the License for the specific language governing permissions and       //
+//  limitations under the License.                                            //
+//                                                                            //
+////////////////////////////////////////////////////////////////////////////////
+
+package transformer
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/Azure/sonic-mgmt-common/translib/db"
+	"github.com/Azure/sonic-mgmt-common/translib/tlerr"
+)
+
+// newTraverseTestDBs opens the ConfigDB and ApplDB, on the DB backend, with
+// the TRAV_* test tables. The test is skipped if the redis-server backend
+// is not reachable.
+func newTraverseTestDBs(t *testing.T, backend db.Backend) [db.MaxDB]*db.DB {
+	prev := db.GetBackend()
+	db.SetBackend(backend)
+	t.Cleanup(func() { db.SetBackend(prev) })
+
+	var dbs [db.MaxDB]*db.DB
+	for _, dbNum := range []db.DBNum{db.ConfigDB, db.ApplDB} {
+		d, err := db.NewDB(getDBOptions(dbNum))
+		if err != nil {
+			t.Fatalf("NewDB(%v) failed: %v", dbNum, err)
+		}
+		t.Cleanup(func() { d.DeleteDB() })
+		dbs[dbNum] = d
+	}
+
+	entries := []struct {
+		dbNum db.DBNum
+		tbl   string
+		key   []string
+	}{
+		{db.ConfigDB, "TRAV_PORT", []string{"Ethernet0"}},
+		{db.ConfigDB, "TRAV_PORT", []string{"Ethernet4"}},
+		{db.ConfigDB, "TRAV_VLAN_MEMBER", []string{"Vlan10", "Ethernet0"}},
+		{db.ConfigDB, "TRAV_VLAN_MEMBER", []string{"Vlan20", "Ethernet4"}},
+		{db.ConfigDB, "TRAV_VLAN_MEMBER", []string{"Vlan30", "Ethernet8"}},
+		{db.ApplDB, "TRAV_APP_PORT", []string{"Ethernet0"}},
+	}
+	for _, e := range entries {
+		d, ts, key := dbs[e.dbNum], &db.TableSpec{Name: e.tbl}, db.Key{Comp: e.key}
+		err := d.SetEntry(ts, key, db.Value{Field: map[string]string{"mtu": "9100"}})
+		if err != nil && backend.Name() == "redis" {
+			t.Skipf("redis-server is not reachable; SetEntry() failed: %v", err)
+		} else if err != nil {
+			t.Fatalf("SetEntry(%v, %v) failed: %v", e.tbl, e.key, err)
+		}
+		t.Cleanup(func() { d.DeleteEntry(ts, key) })
+	}
+	return dbs
+}
+
+// onTraverseTestBackends runs f, with the test DBs, on the MemoryBackend
+// and on the redis-server (if reachable). The MemoryBackend runs Go
+// equivalents of the Lua scripts of the pattern reads.
+func onTraverseTestBackends(t *testing.T, f func(t *testing.T, dbs [db.MaxDB]*db.DB)) {
+	for _, backend := range []db.Backend{db.NewMemoryBackend(), &db.RedisBackend{}} {
+		t.Run(backend.Name(), func(t *testing.T) {
+			f(t, newTraverseTestDBs(t, backend))
+		})
+	}
+}
+
+// traverseTestSpec returns a NONE table KeySpec with; a TRAV_PORT pattern,
+// with a nested TRAV_VLAN_MEMBER pattern (of the parent keys), and a
+// TRAV_APP_PORT specific key, with a nested TRAV_APP_QUEUE specific key
+// (missing, if missingKey).
+func traverseTestSpec(missingKey bool) KeySpec {
+	appPort := KeySpec{DbNum: db.ApplDB, Ts: db.TableSpec{Name: "TRAV_APP_PORT"},
+		Key: *db.NewKey("Ethernet0")}
+	if missingKey {
+		appPort.Child = []KeySpec{{DbNum: db.ApplDB, Ts: db.TableSpec{Name: "TRAV_APP_QUEUE"},
+			Key: *db.NewKey("Ethernet0", "0")}}
+	}
+	return KeySpec{
+		DbNum: db.ConfigDB,
+		Ts:    db.TableSpec{Name: XFMR_NONE_STRING},
+		Child: []KeySpec{
+			{
+				DbNum:           db.ConfigDB,
+				Ts:              db.TableSpec{Name: "TRAV_PORT"},
+				IgnoreParentKey: true,
+				Child: []KeySpec{{DbNum: db.ConfigDB,
+					Ts: db.TableSpec{Name: "TRAV_VLAN_MEMBER"}}},
+			},
+			appPort,
+		},
+	}
+}
+
+func newTraverseResult() RedisDbMap {
+	result := make(RedisDbMap)
+	for i := db.ApplDB; i < db.MaxDB; i++ {
+		result[i] = make(map[string]map[string]db.Value)
+	}
+	return result
+}
+
+func traverseResultKeys(result RedisDbMap) map[db.DBNum]map[string][]string {
+	keys := make(map[db.DBNum]map[string][]string)
+	for dbNum, tblData := range result {
+		for tbl, data := range tblData {
+			if keys[dbNum] == nil {
+				keys[dbNum] = make(map[string][]string)
+			}
+			for key := range data {
+				keys[dbNum][tbl] = append(keys[dbNum][tbl], key)
+			}
+			sort.Strings(keys[dbNum][tbl])
+		}
+	}
+	return keys
+}
+
+func TestTraverseDb(t *testing.T) {
+	onTraverseTestBackends(t, testTraverseDb)
+}
+
+func testTraverseDb(t *testing.T, dbs [db.MaxDB]*db.DB) {
+	result := newTraverseResult()
+	cache := make(map[db.DBNum]map[string]map[string]bool)
+
+	err := TraverseDb(dbs, traverseTestSpec(false), &result, nil, cache, context.Background())
+	if err != nil {
+		t.Fatalf("TraverseDb() failed: %v", err)
+	}
+
+	exp := map[db.DBNum]map[string][]string{
+		db.ConfigDB: {
+			"TRAV_PORT":        {"Ethernet0", "Ethernet4"},
+			"TRAV_VLAN_MEMBER": {"Vlan10|Ethernet0", "Vlan20|Ethernet4"},
+		},
+		db.ApplDB: {
+			"TRAV_APP_PORT": {"Ethernet0"},
+		},
+	}
+	if keys := traverseResultKeys(result); !reflect.DeepEqual(keys, exp) {
+		t.Errorf("TraverseDb() read %v; expected %v", keys, exp)
+	}
+	if v := result[db.ConfigDB]["TRAV_PORT"]["Ethernet4"]; v.Get("mtu") != "9100" {
+		t.Errorf("TraverseDb() read TRAV_PORT|Ethernet4 = %v", v)
+	}
+	if !cache[db.ApplDB]["TRAV_APP_PORT"]["Ethernet0"] {
+		t.Errorf("dbTblKeyGetCache = %v; expected TRAV_APP_PORT:Ethernet0 read", cache)
+	}
+}
+
+func TestTraverseDbMissingKey(t *testing.T) {
+	onTraverseTestBackends(t, testTraverseDbMissingKey)
+}
+
+func testTraverseDbMissingKey(t *testing.T, dbs [db.MaxDB]*db.DB) {
+	result := newTraverseResult()
+	cache := make(map[db.DBNum]map[string]map[string]bool)
+	spec := traverseTestSpec(true)
+
+	err := traverseDbHelper(dbs, &spec, &result, nil, cache, context.Background())
+	if _, ok := err.(tlerr.TranslibRedisClientEntryNotExist); !ok {
+		t.Fatalf("traverseDbHelper() returned %v; expected the missing key error", err)
+	}
+
+	// The data read, other than the missing key, is still in the result
+	keys := traverseResultKeys(result)
+	if !reflect.DeepEqual(keys[db.ConfigDB]["TRAV_VLAN_MEMBER"], []string{"Vlan10|Ethernet0", "Vlan20|Ethernet4"}) ||
+		!reflect.DeepEqual(keys[db.ApplDB]["TRAV_APP_PORT"], []string{"Ethernet0"}) {
+		t.Errorf("traverseDbHelper() read %v", keys)
+	}
+	if readOk, ok := cache[db.ApplDB]["TRAV_APP_QUEUE"]["Ethernet0:0"]; !ok || readOk {
+		t.Errorf("dbTblKeyGetCache = %v; expected TRAV_APP_QUEUE:Ethernet0:0 not read", cache)
+	}
+
+	// TraverseDb returns the error, without the result
+	result = newTraverseResult()
+	err = TraverseDb(dbs, traverseTestSpec(true), &result, nil, nil, context.Background())
+	if err == nil || len(traverseResultKeys(result)) != 0 {
+		t.Errorf("TraverseDb() = %v, with %v; expected the missing key error", err, result)
+	}
+}
@@ -35,6 +35,8 @@ package translib
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 	"sync"
 
 	"github.com/Azure/sonic-mgmt-common/translib/db"
@@ -73,6 +75,7 @@ type SetRequest struct {
 	AuthEnabled      bool
 	ClientVersion    Version
 	DeleteEmptyEntry bool
+	Ctxt             context.Context // Cancels the transaction retries
 }
 
 type SetResponse struct {
@@ -94,6 +97,7 @@ type GetRequest struct {
 	ClientVersion Version
 	QueryParams   QueryParameters
 	Ctxt          context.Context
+	Namespace     string // DB namespace, for multi-ASIC; "" is default
 }
 
 type GetResponse struct {
@@ -128,6 +132,7 @@ type BulkRequest struct {
 	User          UserRoles
 	AuthEnabled   bool
 	ClientVersion Version
+	Ctxt          context.Context // Cancels the transaction retries
 }
 
 // BulkResponseEntry - Entry for BulkResponse
@@ -154,7 +159,6 @@ func init() {
 
 // Create - Creates entries in the redis DB pertaining to the path and payload
 func Create(req SetRequest) (SetResponse, error) {
-	var keys []db.WatchKeys
 	var resp SetResponse
 	path := req.Path
 	payload := req.Payload
@@ -185,7 +189,7 @@ func Create(req SetRequest) (SetResponse, error) {
 	writeMutex.Lock()
 	defer writeMutex.Unlock()
 
-	d, err := db.NewDB(getDBOptions(db.ConfigDB))
+	d, err := db.NewDB(getDBOptions(db.ConfigDB, withUser(req.User.Name)))
 
 	if err != nil {
 		resp.ErrSrc = ProtoErr
@@ -194,40 +198,11 @@ func Create(req SetRequest) (SetResponse, error) {
 
 	defer d.DeleteDB()
 
-	keys, err = (*app).translateCreate(d)
-
-	if err != nil {
-		resp.ErrSrc = AppErr
-		return resp, err
-	}
-
-	err = d.StartTx(keys, appInfo.tablesToWatch)
-
-	if err != nil {
-		resp.ErrSrc = AppErr
-		return resp, err
-	}
-
-	resp, err = (*app).processCreate(d)
-
-	if err != nil {
-		d.AbortTx()
-		resp.ErrSrc = AppErr
-		return resp, err
-	}
-
-	err = d.CommitTx()
-
-	if err != nil {
-		resp.ErrSrc = AppErr
-	}
-
-	return resp, err
+	return runSetTx(d, app, appInfo, req, nil, CREATE)
 }
 
 // Update - Updates entries in the redis DB pertaining to the path and payload
 func Update(req SetRequest) (SetResponse, error) {
-	var keys []db.WatchKeys
 	var resp SetResponse
 	path := req.Path
 	payload := req.Payload
@@ -258,7 +233,7 @@ func Update(req SetRequest) (SetResponse, error) {
 	writeMutex.Lock()
 	defer writeMutex.Unlock()
 
-	d, err := db.NewDB(getDBOptions(db.ConfigDB))
+	d, err := db.NewDB(getDBOptions(db.ConfigDB, withUser(req.User.Name)))
 
 	if err != nil {
 		resp.ErrSrc = ProtoErr
@@ -267,41 +242,12 @@ func Update(req SetRequest) (SetResponse, error) {
 
 	defer d.DeleteDB()
 
-	keys, err = (*app).translateUpdate(d)
-
-	if err != nil {
-		resp.ErrSrc = AppErr
-		return resp, err
-	}
-
-	err = d.StartTx(keys, appInfo.tablesToWatch)
-
-	if err != nil {
-		resp.ErrSrc = AppErr
-		return resp, err
-	}
-
-	resp, err = (*app).processUpdate(d)
-
-	if err != nil {
-		d.AbortTx()
-		resp.ErrSrc = AppErr
-		return resp, err
-	}
-
-	err = d.CommitTx()
-
-	if err != nil {
-		resp.ErrSrc = AppErr
-	}
-
-	return resp, err
+	return runSetTx(d, app, appInfo, req, nil, UPDATE)
 }
 
 // Replace - Replaces entries in the redis DB pertaining to the path and payload
 func Replace(req SetRequest) (SetResponse, error) {
 	var err error
-	var keys []db.WatchKeys
 	var resp SetResponse
 	path := req.Path
 	payload := req.Payload
@@ -332,7 +278,7 @@ func Replace(req SetRequest) (SetResponse, error) {
 	writeMutex.Lock()
 	defer writeMutex.Unlock()
 
-	d, err := db.NewDB(getDBOptions(db.ConfigDB))
+	d, err := db.NewDB(getDBOptions(db.ConfigDB, withUser(req.User.Name)))
 
 	if err != nil {
 		resp.ErrSrc = ProtoErr
@@ -341,41 +287,12 @@ func Replace(req SetRequest) (SetResponse, error) {
 
 	defer d.DeleteDB()
 
-	keys, err = (*app).translateReplace(d)
-
-	if err != nil {
-		resp.ErrSrc = AppErr
-		return resp, err
-	}
-
-	err = d.StartTx(keys, appInfo.tablesToWatch)
-
-	if err != nil {
-		resp.ErrSrc = AppErr
-		return resp, err
-	}
-
-	resp, err = (*app).processReplace(d)
-
-	if err != nil {
-		d.AbortTx()
-		resp.ErrSrc = AppErr
-		return resp, err
-	}
-
-	err = d.CommitTx()
-
-	if err != nil {
-		resp.ErrSrc = AppErr
-	}
-
-	return resp, err
+	return runSetTx(d, app, appInfo, req, nil, REPLACE)
 }
 
 // Delete - Deletes entries in the redis DB pertaining to the path
 func Delete(req SetRequest) (SetResponse, error) {
 	var err error
-	var keys []db.WatchKeys
 	var resp SetResponse
 	path := req.Path
 	if !isAuthorizedForSet(req) {
@@ -405,7 +322,7 @@ func Delete(req SetRequest) (SetResponse, error) {
 	writeMutex.Lock()
 	defer writeMutex.Unlock()
 
-	d, err := db.NewDB(getDBOptions(db.ConfigDB))
+	d, err := db.NewDB(getDBOptions(db.ConfigDB, withUser(req.User.Name)))
 
 	if err != nil {
 		resp.ErrSrc = ProtoErr
@@ -414,35 +331,7 @@ func Delete(req SetRequest) (SetResponse, error) {
 
 	defer d.DeleteDB()
 
-	keys, err = (*app).translateDelete(d)
-
-	if err != nil {
-		resp.ErrSrc = AppErr
-		return resp, err
-	}
-
-	err = d.StartTx(keys, appInfo.tablesToWatch)
-
-	if err != nil {
-		resp.ErrSrc = AppErr
-		return resp, err
-	}
-
-	resp, err = (*app).processDelete(d)
-
-	if err != nil {
-		d.AbortTx()
-		resp.ErrSrc = AppErr
-		return resp, err
-	}
-
-	err = d.CommitTx()
-
-	if err != nil {
-		resp.ErrSrc = AppErr
-	}
-
-	return resp, err
+	return runSetTx(d, app, appInfo, req, &opts, DELETE)
 }
 
 // Get - Gets data from the redis DB and converts it to northbound format
@@ -474,7 +363,7 @@ func Get(req GetRequest) (GetResponse, error) {
 		return resp, err
 	}
 
-	dbs, err := getAllDbs(withWriteDisable)
+	dbs, err := getAllDbs(withWriteDisable, withNamespace(req.Namespace))
 
 	if err != nil {
 		resp = GetResponse{Payload: payload, ErrSrc: ProtoErr}
@@ -530,7 +419,7 @@ func Action(req ActionRequest) (ActionResponse, error) {
 	writeMutex.Lock()
 	defer writeMutex.Unlock()
 
-	dbs, err := getAllDbs()
+	dbs, err := getAllDbs(withUser(req.User.Name))
 
 	if err != nil {
 		resp = ActionResponse{Payload: payload, ErrSrc: ProtoErr}
@@ -556,9 +445,6 @@ func Action(req ActionRequest) (ActionResponse, error) {
 // Transaction based
 func Bulk(req BulkRequest) (BulkResponse, error) {
 	var err error
-	var keys []db.WatchKeys
-	var errSrc ErrSource
-	var appResp SetResponse
 
 	resp := BulkResponse{}
 
@@ -571,7 +457,7 @@ func Bulk(req BulkRequest) (BulkResponse, error) {
 	writeMutex.Lock()
 	defer writeMutex.Unlock()
 
-	d, err := db.NewDB(getDBOptions(db.ConfigDB))
+	d, err := db.NewDB(getDBOptions(db.ConfigDB, withUser(req.User.Name)))
 
 	if err != nil {
 		return resp, err
@@ -579,16 +465,30 @@ func Bulk(req BulkRequest) (BulkResponse, error) {
 
 	defer d.DeleteDB()
 
-	//Start the transaction without any keys or tables to watch will be added later using AppendWatchTx
-	err = d.StartTx(nil, nil)
+	// Transaction is started without any keys or tables to watch; they will be
+	// added later using AppendWatchTx. Whole request is processed again if
+	// the transaction fails due to a conflicting change by another writer.
+	err = d.RunTx(requestContext(req.Ctxt), func(d *db.DB) error {
+		resp = BulkResponse{}
+		return processBulk(d, req, &resp)
+	}, &setTxRetryPolicy)
 
-	if err != nil {
-		return resp, err
-	}
+	return resp, err
+}
+
+// processBulk translates and processes all the requests of a BulkRequest
+// within the current transaction. Responses are appended to resp. Each
+// request is a savepoint, so that the partial changes of a request, whose
+// error is ignored (or retried as REPLACE), are rolled back.
+func processBulk(d *db.DB, req BulkRequest, resp *BulkResponse) error {
+	var keys []db.WatchKeys
+	var errSrc ErrSource
+	var appResp SetResponse
 
 	for i := range req.Request {
 		path := req.Request[i].Entry.Path
 		operation := req.Request[i].Operation
+		spName := "bulk-" + strconv.Itoa(i)
 
 		log.Infof("Bulk Request operation: %v received with path = %v", req.Request[i].Operation, path)
 
@@ -597,6 +497,10 @@ func Bulk(req BulkRequest) (BulkResponse, error) {
 			errSrc = ProtoErr
 			goto BulkError
 		}
+		if err = d.DeclareNamedSP(spName); err != nil {
+			errSrc = ProtoErr
+			goto BulkError
+		}
 		if operation == DELETE {
 			opts := appOptions{deleteEmptyEntry: req.Request[i].Entry.DeleteEmptyEntry}
 			err = appInitialize(app, appInfo, path, nil, &opts, operation)
@@ -617,6 +521,10 @@ func Bulk(req BulkRequest) (BulkResponse, error) {
 				if !req.Request[i].ResourceCheckOnDelete {
 					//GNMI DELETE and YANG-PATCH REMOVE will come here
 					log.V(2).Infof("Ignoring Delete error: %+v", err)
+					if err = d.Rollback2NamedSP(spName); err != nil {
+						errSrc = AppErr
+						goto BulkError
+					}
 					appResp.Err = nil // so that northbounds can ignore
 					resp.Response = append(resp.Response, BulkResponseEntry{Operation: req.Request[i].Operation,
 						Entry: appResp})
@@ -667,6 +575,10 @@ func Bulk(req BulkRequest) (BulkResponse, error) {
 				if !req.Request[i].ResourceCheckOnDelete {
 					//GNMI DELETE and YANG-PATCH REMOVE will come here
 					log.V(2).Infof("Ignoring Delete error: %+v", err)
+					if err = d.Rollback2NamedSP(spName); err != nil {
+						errSrc = AppErr
+						goto BulkError
+					}
 					appResp.Err = nil // so that northbounds can ignore
 					resp.Response = append(resp.Response, BulkResponseEntry{Operation: req.Request[i].Operation,
 						Entry: appResp})
@@ -683,6 +595,14 @@ func Bulk(req BulkRequest) (BulkResponse, error) {
 				//REPLACE is chosen because PATH format and payload is same as UPDATE
 				log.V(2).Infof("Since UPDATE Failed, Changing operation type to REPLACE")
 				operation = REPLACE
+				// Discard the partial changes of the UPDATE
+				if err = d.Rollback2NamedSP(spName); err == nil {
+					err = d.DeclareNamedSP(spName)
+				}
+				if err != nil {
+					errSrc = AppErr
+					goto BulkError
+				}
 				payload := req.Request[i].Entry.Payload
 				err = appInitialize(app, appInfo, path, &payload, nil, operation)
 				if err != nil {
@@ -713,23 +633,25 @@ func Bulk(req BulkRequest) (BulkResponse, error) {
 			goto BulkError
 		}
 
+		if err = d.ReleaseNamedSP(spName); err != nil {
+			errSrc = AppErr
+			goto BulkError
+		}
+
 		resp.Response = append(resp.Response, BulkResponseEntry{Operation: req.Request[i].Operation, Entry: appResp})
 
 	BulkError:
 		if err != nil {
 			log.Infof("BulkError: %+v", err)
-			d.AbortTx()
 			appResp.ErrSrc = errSrc
 			appResp.Err = err
 			resp.Response = append(resp.Response, BulkResponseEntry{Operation: req.Request[i].Operation,
 				Entry: appResp})
-			return resp, err
+			return err
 		}
 	}
 
-	err = d.CommitTx()
-
-	return resp, err
+	return nil
 }
 
 // GetModels - Gets all the models supported by Translib
@@ -739,6 +661,104 @@ func GetModels() ([]ModelData, error) {
 	return getModels(), err
 }
 
+// setTxRetryPolicy is the db.RetryPolicy for the transactions of the set APIs
+var setTxRetryPolicy = db.DefaultRetryPolicy
+
+// runSetTx translates and processes a set request (CREATE, UPDATE, REPLACE or
+// DELETE) in a transaction. The transaction is retried if it fails due to a
+// conflicting change by another writer. The app module is created again for
+// every retry, since its state cannot be reused.
+func runSetTx(d *db.DB, app *appInterface, appInfo *appInfo, req SetRequest, opts *appOptions, opCode int) (SetResponse, error) {
+	var resp SetResponse
+	attempt := 0
+	errSrc := AppErr
+
+	err := d.RunTx(requestContext(req.Ctxt), func(d *db.DB) error {
+		var err error
+		var keys []db.WatchKeys
+
+		if attempt++; attempt > 1 {
+			log.Infof("Retrying %s request with path = %s", setOpName(opCode), req.Path)
+			var payload *[]byte
+			if opCode != DELETE {
+				p := req.Payload
+				payload = &p
+			}
+			if app, appInfo, err = getAppModule(req.Path, req.ClientVersion); err != nil {
+				errSrc = ProtoErr
+				return err
+			}
+			if err = appInitialize(app, appInfo, req.Path, payload, opts, opCode); err != nil {
+				return err
+			}
+		}
+
+		switch opCode {
+		case CREATE:
+			keys, err = (*app).translateCreate(d)
+		case UPDATE:
+			keys, err = (*app).translateUpdate(d)
+		case REPLACE:
+			keys, err = (*app).translateReplace(d)
+		case DELETE:
+			keys, err = (*app).translateDelete(d)
+		default:
+			err = tlerr.NotSupported("Unknown operation '%v'", opCode)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err = d.AppendWatchTx(keys, appInfo.tablesToWatch); err != nil {
+			return err
+		}
+
+		switch opCode {
+		case CREATE:
+			resp, err = (*app).processCreate(d)
+		case UPDATE:
+			resp, err = (*app).processUpdate(d)
+		case REPLACE:
+			resp, err = (*app).processReplace(d)
+		case DELETE:
+			resp, err = (*app).processDelete(d)
+		}
+
+		return err
+	}, &setTxRetryPolicy)
+
+	if err != nil {
+		resp.ErrSrc = errSrc
+	}
+
+	return resp, err
+}
+
+// requestContext returns the context of a request; background context if
+// the request has none.
+func requestContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
+
+// setOpName returns the name of a set operation, for the logs
+func setOpName(opCode int) string {
+	switch opCode {
+	case CREATE:
+		return "CREATE"
+	case UPDATE:
+		return "UPDATE"
+	case REPLACE:
+		return "REPLACE"
+	case DELETE:
+		return "DELETE"
+	}
+	return fmt.Sprintf("operation %d", opCode)
+}
+
 // Creates connection will all the redis DBs. To be used for get request
 func getAllDbs(opts ...func(*db.Options)) ([db.MaxDB]*db.DB, error) {
 	var dbs [db.MaxDB]*db.DB
@@ -794,6 +814,18 @@ func withOnChange(o *db.Options) {
 	o.IsOnChangeEnabled = true
 }
 
+func withNamespace(ns string) func(*db.Options) {
+	return func(o *db.Options) {
+		o.Namespace = ns
+	}
+}
+
+func withUser(user string) func(*db.Options) {
+	return func(o *db.Options) {
+		o.User = user
+	}
+}
+
 func getAppModule(path string, clientVer Version) (*appInterface, *appInfo, error) {
 	var app appInterface
 